@@ -0,0 +1,250 @@
+package google
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// bigQueryTableWideningCasts lists the column types that ALTER COLUMN SET
+// DATA TYPE can change a column of a type to.
+var bigQueryTableWideningCasts = map[string][]string{
+	"INT64":   {"NUMERIC", "BIGNUMERIC", "FLOAT64"},
+	"NUMERIC": {"BIGNUMERIC", "FLOAT64"},
+}
+
+// bigQueryTableStandardType returns the standard SQL name of a column type.
+func bigQueryTableStandardType(t string) string {
+	switch t = strings.ToUpper(t); t {
+	case "INTEGER":
+		return "INT64"
+	case "FLOAT":
+		return "FLOAT64"
+	case "BOOLEAN":
+		return "BOOL"
+	case "RECORD":
+		return "STRUCT"
+	}
+	return t
+}
+
+func bigQueryTableTypeCanWiden(old, new string) bool {
+	old, new = bigQueryTableStandardType(old), bigQueryTableStandardType(new)
+	for _, t := range bigQueryTableWideningCasts[old] {
+		if t == new {
+			return true
+		}
+	}
+	return false
+}
+
+// bigQueryTableSchemaMigration returns the DDL statements migrating a table
+// from the old to the new schema, where columns of the old schema named in
+// renames are renamed instead of dropped. Changes the tables.update call can
+// make without DDL, such as added columns, don't need statements. An error is
+// returned when a change can't be applied to the existing data.
+func bigQueryTableSchemaMigration(table string, old, new []interface{}, renames map[string]string) ([]string, error) {
+	if err := bigQueryTablecheckNameExists(old); err != nil {
+		return nil, err
+	}
+	if err := bigQueryTablecheckNameExists(new); err != nil {
+		return nil, err
+	}
+	oldColumns := bigQueryArrayToMapIndexedByName(old)
+	newColumns := bigQueryArrayToMapIndexedByName(new)
+
+	for from, to := range renames {
+		if _, ok := oldColumns[from]; !ok {
+			// The column was renamed by an earlier migration.
+			continue
+		}
+		if _, ok := newColumns[to]; !ok {
+			return nil, fmt.Errorf("column %q is renamed to %q, which is not in the new schema", from, to)
+		}
+		if _, ok := oldColumns[to]; ok {
+			return nil, fmt.Errorf("column %q can't be renamed to %q, which already exists", from, to)
+		}
+	}
+
+	var drops, renameStatements, alters []string
+	migrated := map[string]bool{}
+	// Iterate in the order of the old schema to produce a stable plan.
+	for _, raw := range old {
+		name := raw.(map[string]interface{})["name"].(string)
+		oldColumn := raw.(map[string]interface{})
+
+		newName := name
+		if to, ok := renames[name]; ok {
+			newName = to
+			renameStatements = append(renameStatements, fmt.Sprintf("ALTER TABLE `%s` RENAME COLUMN `%s` TO `%s`", table, name, to))
+		}
+		newColumn, ok := newColumns[newName].(map[string]interface{})
+		if !ok {
+			drops = append(drops, fmt.Sprintf("ALTER TABLE `%s` DROP COLUMN `%s`", table, name))
+			continue
+		}
+		migrated[newName] = true
+
+		// Compare the columns as if the old one had the new name and type, so
+		// that only changes that need no DDL remain.
+		compared := map[string]interface{}{}
+		for k, v := range oldColumn {
+			compared[k] = v
+		}
+		compared["name"] = newColumn["name"]
+		oldType, _ := oldColumn["type"].(string)
+		newType, _ := newColumn["type"].(string)
+		if oldType != "" && newType != "" && !bigQueryTableTypeEq(oldType, newType) {
+			if !bigQueryTableTypeCanWiden(oldType, newType) {
+				return nil, fmt.Errorf("column %q can't be changed from type %s to %s", name, oldType, newType)
+			}
+			compared["type"] = newType
+			alters = append(alters, fmt.Sprintf("ALTER TABLE `%s` ALTER COLUMN `%s` SET DATA TYPE %s", table, newName, bigQueryTableStandardType(newType)))
+		}
+
+		changeable, err := resourceBigQueryTableSchemaIsChangeable(compared, newColumn)
+		if err != nil {
+			return nil, err
+		}
+		if !changeable {
+			return nil, fmt.Errorf("column %q can't be changed: only top-level columns can be dropped or renamed, and a column can't be made REQUIRED or change its mode from or to REPEATED", name)
+		}
+	}
+
+	for _, raw := range new {
+		column := raw.(map[string]interface{})
+		if migrated[column["name"].(string)] {
+			continue
+		}
+		if bigQueryTableNormalizeMode(column["mode"]) == "REQUIRED" {
+			return nil, fmt.Errorf("column %q can't be added with mode REQUIRED", column["name"])
+		}
+	}
+
+	statements := append(drops, renameStatements...)
+	return append(statements, alters...), nil
+}
+
+// bigQueryTableSchemaMigrationDdl returns the DDL statements migrating the
+// schema of the table planned in d, and whether the schema change is migrated
+// at all.
+func bigQueryTableSchemaMigrationDdl(d TerraformResourceDiff) ([]string, bool, error) {
+	migration, ok := d.GetOk("schema_migration")
+	if !ok || !d.HasChange("schema") {
+		return nil, false, nil
+	}
+	old, new, ok := bigQueryTableSchemaColumnsChange(d)
+	if !ok {
+		return nil, false, nil
+	}
+
+	changeable, err := resourceBigQueryTableSchemaIsChangeable(old, new)
+	if err != nil {
+		return nil, false, err
+	}
+	if changeable {
+		return []string{}, true, nil
+	}
+
+	renames := map[string]string{}
+	if m, ok := migration.([]interface{})[0].(map[string]interface{}); ok {
+		for from, to := range m["column_renames"].(map[string]interface{}) {
+			renames[from] = to.(string)
+		}
+	}
+	table := fmt.Sprintf("%s.%s.%s", d.Get("project"), d.Get("dataset_id"), d.Get("table_id"))
+	statements, err := bigQueryTableSchemaMigration(table, old, new, renames)
+	if err != nil {
+		return nil, false, fmt.Errorf("the schema change can't be migrated: %s", err)
+	}
+	return statements, true, nil
+}
+
+// bigQueryTableSchemaColumnsChange returns the columns of the old and new
+// schema planned in d, unless one of them isn't set.
+func bigQueryTableSchemaColumnsChange(d TerraformResourceDiff) ([]interface{}, []interface{}, bool) {
+	o, n := d.GetChange("schema")
+	var old, new []interface{}
+	if err := json.Unmarshal([]byte(o.(string)), &old); err != nil {
+		return nil, nil, false
+	}
+	if err := json.Unmarshal([]byte(n.(string)), &new); err != nil {
+		return nil, nil, false
+	}
+	// The API can return an empty schema which gets encoded to "null" during read.
+	if old == nil {
+		old = []interface{}{}
+	}
+	if new == nil {
+		new = []interface{}{}
+	}
+	return old, new, true
+}
+
+func resourceBigQueryTableSchemaMigrationCustomizeDiff(_ context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	statements, migrated, err := bigQueryTableSchemaMigrationDdl(d)
+	if err != nil || !migrated {
+		return err
+	}
+	return d.SetNew("schema_migration_ddl", statements)
+}
+
+// bigQueryTableRunDDL runs statements as query jobs of google_bigquery_job,
+// one after the other, waiting for each to finish.
+func bigQueryTableRunDDL(config *Config, d *schema.ResourceData, userAgent, project, datasetID string, statements []string) error {
+	if len(statements) == 0 {
+		return nil
+	}
+	dataset, err := config.NewBigQueryClient(userAgent).Datasets.Get(project, datasetID).Do()
+	if err != nil {
+		return fmt.Errorf("Error reading dataset %s: %s", datasetID, err)
+	}
+
+	for _, statement := range statements {
+		job := resourceBigQueryJob().Data(nil)
+		fields := map[string]interface{}{
+			"project":  project,
+			"job_id":   resource.PrefixedUniqueId("tf_schema_migration_"),
+			"location": dataset.Location,
+			"query": []interface{}{map[string]interface{}{
+				"query":          statement,
+				"use_legacy_sql": false,
+			}},
+		}
+		for k, v := range fields {
+			if err := job.Set(k, v); err != nil {
+				return fmt.Errorf("Error setting %s of the job running %q: %s", k, statement, err)
+			}
+		}
+
+		if err := resourceBigQueryJobCreate(job, config); err != nil {
+			return fmt.Errorf("Error running %q: %s", statement, err)
+		}
+		err = PollingWaitTime(resourceBigQueryJobPollRead(job, config), bigQueryJobPollCheckForDone, fmt.Sprintf("Running %q", statement), d.Timeout(schema.TimeoutUpdate), 1)
+		if err != nil {
+			return fmt.Errorf("Error running %q: %s", statement, err)
+		}
+	}
+	return nil
+}
+
+// bigQueryJobPollCheckForDone waits for a job to be done, and returns the
+// error of a failed job. google_bigquery_job itself only waits for jobs to
+// exist.
+func bigQueryJobPollCheckForDone(res map[string]interface{}, respErr error) PollResult {
+	if respErr != nil {
+		return ErrorPollResult(respErr)
+	}
+	status, _ := res["status"].(map[string]interface{})
+	if state, _ := status["state"].(string); state != "DONE" {
+		return PendingStatusPollResult(state)
+	}
+	if jobErr, ok := status["errorResult"].(map[string]interface{}); ok {
+		return ErrorPollResult(fmt.Errorf("%s: %s", jobErr["reason"], jobErr["message"]))
+	}
+	return SuccessPollResult()
+}
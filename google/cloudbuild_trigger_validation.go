@@ -0,0 +1,312 @@
+package google
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// resourceCloudBuildTriggerCustomizeDiff checks the inline build of a trigger,
+// which the API only validates when a build runs.
+var resourceCloudBuildTriggerCustomizeDiff = customdiff.All(
+	customdiff.Sequence(
+		buildTimeoutCustomizeDiff,
+		stepTimeoutCustomizeDiff,
+	),
+	buildStepCustomizeDiff,
+)
+
+// cloudBuildDurationRegex matches a duration in the JSON form of a
+// google.protobuf.Duration, the only form the Cloud Build API accepts.
+var cloudBuildDurationRegex = regexp.MustCompile(`^[0-9]+(\.[0-9]{1,9})?s$`)
+
+// buildTimeoutCustomizeDiff checks that the timeouts of the build and its
+// steps are durations the API accepts, before stepTimeoutCustomizeDiff adds
+// them up.
+func buildTimeoutCustomizeDiff(_ context.Context, diff *schema.ResourceDiff, v interface{}) error {
+	buildList := diff.Get("build").([]interface{})
+	if len(buildList) == 0 || buildList[0] == nil {
+		return nil
+	}
+	build := buildList[0].(map[string]interface{})
+	if t := build["timeout"].(string); t != "" && !cloudBuildDurationRegex.MatchString(t) {
+		return fmt.Errorf("Error parsing build timeout: %q isn't a number of seconds terminated by 's', such as \"600s\"", t)
+	}
+
+	var errs []error
+	for i, raw := range build["step"].([]interface{}) {
+		if raw == nil {
+			continue
+		}
+		if t := raw.(map[string]interface{})["timeout"].(string); t != "" && !cloudBuildDurationRegex.MatchString(t) {
+			errs = append(errs, fmt.Errorf("build step %d: timeout %q isn't a number of seconds terminated by 's', such as \"600s\"", i, t))
+		}
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return multierror.Append(nil, errs...)
+}
+
+// Substitutions starting with an underscore are user-defined, except for
+// these, which Cloud Build provides to GitHub pull request builds.
+var cloudBuildBuiltinUserSubstitutions = map[string]bool{
+	"_HEAD_BRANCH":   true,
+	"_BASE_BRANCH":   true,
+	"_HEAD_REPO_URL": true,
+	"_PR_NUMBER":     true,
+}
+
+// cloudBuildUserSubstitutionRegex matches $_FOO and ${_FOO...} references,
+// and $$ escapes so that $$_FOO isn't taken for one.
+var cloudBuildUserSubstitutionRegex = regexp.MustCompile(`\$\$|\$\{(_[A-Z0-9_]+)|\$(_[A-Z0-9_]+)`)
+
+// buildStepCustomizeDiff reports, with the index of the build step at fault,
+// duplicate step ids, wait_for ids that don't match a step, cycles between
+// steps, secret environment variables that no secret provides and references
+// to user-defined substitutions that aren't defined. Undefined substitutions
+// are allowed when options.substitution_option is ALLOW_LOOSE, and the
+// provider's skip_cloudbuild_trigger_validation skips the checks of
+// references altogether. Checks depending on values unknown at plan time are
+// skipped.
+func buildStepCustomizeDiff(_ context.Context, diff *schema.ResourceDiff, meta interface{}) error {
+	buildList := diff.Get("build").([]interface{})
+	if len(buildList) == 0 || buildList[0] == nil {
+		return nil
+	}
+	build := buildList[0].(map[string]interface{})
+	steps := build["step"].([]interface{})
+
+	skipReferences := false
+	if config, ok := meta.(*Config); ok {
+		skipReferences = config.SkipCloudBuildTriggerValidation
+	}
+
+	var errs, referenceErrs []error
+
+	idsKnown := true
+	for i := range steps {
+		idsKnown = idsKnown && diff.NewValueKnown(fmt.Sprintf("build.0.step.%d.id", i)) && diff.NewValueKnown(fmt.Sprintf("build.0.step.%d.wait_for", i))
+	}
+	if idsKnown {
+		dependencyErrs, unknownIdErrs := cloudBuildStepDependencyErrors(steps)
+		errs = append(errs, dependencyErrs...)
+		referenceErrs = append(referenceErrs, unknownIdErrs...)
+	}
+
+	if diff.NewValueKnown("substitutions") && diff.NewValueKnown("build.0.substitutions") && diff.Get("build.0.options.0.substitution_option").(string) != "ALLOW_LOOSE" {
+		defined := make(map[string]bool)
+		for k := range diff.Get("substitutions").(map[string]interface{}) {
+			defined[k] = true
+		}
+		for k := range build["substitutions"].(map[string]interface{}) {
+			defined[k] = true
+		}
+		referenceErrs = append(referenceErrs, cloudBuildSubstitutionErrors(build, defined)...)
+	}
+
+	if diff.NewValueKnown("build.0.available_secrets") && diff.NewValueKnown("build.0.secret") {
+		referenceErrs = append(referenceErrs, cloudBuildSecretEnvErrors(build)...)
+	}
+
+	if !skipReferences {
+		errs = append(errs, referenceErrs...)
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	err := multierror.Append(nil, errs...)
+	if !skipReferences && len(referenceErrs) > 0 {
+		return fmt.Errorf("%s\nSet skip_cloudbuild_trigger_validation in the provider to skip the checks of step ids, secrets and substitutions.", err)
+	}
+	return err
+}
+
+func cloudBuildStepLabel(steps []interface{}, i int) string {
+	if step, ok := steps[i].(map[string]interface{}); ok && step["id"].(string) != "" {
+		return fmt.Sprintf("%d (%q)", i, step["id"])
+	}
+	return fmt.Sprintf("%d", i)
+}
+
+// cloudBuildStepDependencyErrors checks that step ids are unique and that
+// steps don't wait for each other in a cycle, and reports wait_for ids that
+// don't match a step separately. A step without wait_for waits for all the
+// steps before it.
+func cloudBuildStepDependencyErrors(steps []interface{}) (errs, unknownIdErrs []error) {
+	ids := make(map[string]int)
+	for i, raw := range steps {
+		if raw == nil {
+			continue
+		}
+		id := raw.(map[string]interface{})["id"].(string)
+		if id == "" {
+			continue
+		}
+		if j, ok := ids[id]; ok {
+			errs = append(errs, fmt.Errorf("build step %d: id %q is already used by build step %d", i, id, j))
+			continue
+		}
+		ids[id] = i
+	}
+
+	deps := make([][]int, len(steps))
+	for i, raw := range steps {
+		var waitFor []interface{}
+		if raw != nil {
+			waitFor = raw.(map[string]interface{})["wait_for"].([]interface{})
+		}
+		if len(waitFor) == 0 {
+			for j := 0; j < i; j++ {
+				deps[i] = append(deps[i], j)
+			}
+			continue
+		}
+		for _, w := range waitFor {
+			id, _ := w.(string)
+			// "-" starts the step right away.
+			if id == "" || id == "-" {
+				continue
+			}
+			j, ok := ids[id]
+			if !ok {
+				unknownIdErrs = append(unknownIdErrs, fmt.Errorf("build step %d: wait_for references unknown step id %q", i, id))
+				continue
+			}
+			deps[i] = append(deps[i], j)
+		}
+	}
+
+	// Depth-first search, reporting the first cycle found.
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make([]int, len(steps))
+	var path []int
+	var cycle []int
+	var visit func(i int) bool
+	visit = func(i int) bool {
+		state[i] = visiting
+		path = append(path, i)
+		for _, j := range deps[i] {
+			switch state[j] {
+			case visiting:
+				for k, p := range path {
+					if p == j {
+						cycle = append(append(cycle, path[k:]...), j)
+						break
+					}
+				}
+				return true
+			case unvisited:
+				if visit(j) {
+					return true
+				}
+			}
+		}
+		path = path[:len(path)-1]
+		state[i] = visited
+		return false
+	}
+	for i := range steps {
+		if state[i] == unvisited && visit(i) {
+			labels := make([]string, len(cycle))
+			for k, c := range cycle {
+				labels[k] = cloudBuildStepLabel(steps, c)
+			}
+			errs = append(errs, fmt.Errorf("build step %d: wait_for forms a cycle between steps %s", cycle[0], strings.Join(labels, " -> ")))
+			break
+		}
+	}
+
+	return errs, unknownIdErrs
+}
+
+// cloudBuildSubstitutionErrors reports references to user-defined
+// substitutions in the steps and images of build that aren't in defined.
+func cloudBuildSubstitutionErrors(build map[string]interface{}, defined map[string]bool) []error {
+	var errs []error
+	check := func(where, v string) {
+		for _, m := range cloudBuildUserSubstitutionRegex.FindAllStringSubmatch(v, -1) {
+			name := m[1] + m[2]
+			if name == "" || defined[name] || cloudBuildBuiltinUserSubstitutions[name] {
+				continue
+			}
+			errs = append(errs, fmt.Errorf("%s: substitution $%s isn't defined in substitutions", where, name))
+		}
+	}
+
+	for i, raw := range build["step"].([]interface{}) {
+		if raw == nil {
+			continue
+		}
+		step := raw.(map[string]interface{})
+		where := fmt.Sprintf("build step %d", i)
+		for _, field := range []string{"name", "dir", "entrypoint"} {
+			check(where, step[field].(string))
+		}
+		for _, field := range []string{"args", "env"} {
+			for _, v := range step[field].([]interface{}) {
+				s, _ := v.(string)
+				check(where, s)
+			}
+		}
+	}
+	for _, v := range build["images"].([]interface{}) {
+		s, _ := v.(string)
+		check("build images", s)
+	}
+	return errs
+}
+
+// cloudBuildSecretEnvErrors reports secret_env variables of the steps and
+// options of build that neither available_secrets nor secret provide.
+func cloudBuildSecretEnvErrors(build map[string]interface{}) []error {
+	provided := make(map[string]bool)
+	for _, raw := range build["available_secrets"].([]interface{}) {
+		if raw == nil {
+			continue
+		}
+		for _, sm := range raw.(map[string]interface{})["secret_manager"].([]interface{}) {
+			if sm != nil {
+				provided[sm.(map[string]interface{})["env"].(string)] = true
+			}
+		}
+	}
+	for _, raw := range build["secret"].([]interface{}) {
+		if raw == nil {
+			continue
+		}
+		for env := range raw.(map[string]interface{})["secret_env"].(map[string]interface{}) {
+			provided[env] = true
+		}
+	}
+
+	var errs []error
+	check := func(where string, envs []interface{}) {
+		for _, v := range envs {
+			env, _ := v.(string)
+			if env != "" && !provided[env] {
+				errs = append(errs, fmt.Errorf("%s: secret_env %q isn't provided by available_secrets or secret", where, env))
+			}
+		}
+	}
+	for i, raw := range build["step"].([]interface{}) {
+		if raw != nil {
+			check(fmt.Sprintf("build step %d", i), raw.(map[string]interface{})["secret_env"].([]interface{}))
+		}
+	}
+	for _, raw := range build["options"].([]interface{}) {
+		if raw != nil {
+			check("build options", raw.(map[string]interface{})["secret_env"].([]interface{}))
+		}
+	}
+	return errs
+}
@@ -0,0 +1,544 @@
+package google
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+
+	"google.golang.org/api/compute/v1"
+)
+
+// firewallProtocolNames maps the IP protocol numbers accepted by firewall
+// rules to the names the API also accepts, so both forms compare equal.
+var firewallProtocolNames = map[string]string{
+	"1":   "icmp",
+	"6":   "tcp",
+	"17":  "udp",
+	"50":  "esp",
+	"51":  "ah",
+	"94":  "ipip",
+	"132": "sctp",
+}
+
+func normalizeFirewallProtocol(p string) string {
+	p = strings.ToLower(p)
+	if name, ok := firewallProtocolNames[p]; ok {
+		return name
+	}
+	return p
+}
+
+type firewallPortRange struct {
+	from, to int
+}
+
+func parseFirewallPortRange(s string) (firewallPortRange, error) {
+	parts := strings.SplitN(s, "-", 2)
+	from, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return firewallPortRange{}, fmt.Errorf("invalid port %q", s)
+	}
+	to := from
+	if len(parts) == 2 {
+		if to, err = strconv.Atoi(parts[1]); err != nil {
+			return firewallPortRange{}, fmt.Errorf("invalid port range %q", s)
+		}
+	}
+	return firewallPortRange{from, to}, nil
+}
+
+// firewallAnalysisProtocol matches a protocol and, if ports is not empty,
+// the given ports of that protocol.
+type firewallAnalysisProtocol struct {
+	protocol string
+	ports    []firewallPortRange
+}
+
+func newFirewallAnalysisProtocol(protocol string, ports []string) (firewallAnalysisProtocol, error) {
+	p := firewallAnalysisProtocol{protocol: normalizeFirewallProtocol(protocol)}
+	for _, s := range ports {
+		r, err := parseFirewallPortRange(s)
+		if err != nil {
+			return p, err
+		}
+		p.ports = append(p.ports, r)
+	}
+	return p, nil
+}
+
+func (p firewallAnalysisProtocol) covers(o firewallAnalysisProtocol) bool {
+	if p.protocol == "all" {
+		return true
+	}
+	if p.protocol != o.protocol {
+		return false
+	}
+	if len(p.ports) == 0 {
+		return true
+	}
+	if len(o.ports) == 0 {
+		return false
+	}
+
+	// The ports of o must be within the union of the ports of p.
+	merged := append([]firewallPortRange{}, p.ports...)
+	sort.Slice(merged, func(i, j int) bool { return merged[i].from < merged[j].from })
+	for _, r := range o.ports {
+		next := r.from
+		for _, m := range merged {
+			if m.from <= next && m.to >= next {
+				next = m.to + 1
+			}
+		}
+		if next <= r.to {
+			return false
+		}
+	}
+	return true
+}
+
+func (p firewallAnalysisProtocol) overlaps(o firewallAnalysisProtocol) bool {
+	if p.protocol != "all" && o.protocol != "all" && p.protocol != o.protocol {
+		return false
+	}
+	if len(p.ports) == 0 || len(o.ports) == 0 {
+		return true
+	}
+	for _, a := range p.ports {
+		for _, b := range o.ports {
+			if a.from <= b.to && b.from <= a.to {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (p firewallAnalysisProtocol) matches(protocol string, port int) bool {
+	if p.protocol != "all" && p.protocol != normalizeFirewallProtocol(protocol) {
+		return false
+	}
+	if len(p.ports) == 0 {
+		return true
+	}
+	for _, r := range p.ports {
+		if port >= r.from && port <= r.to {
+			return true
+		}
+	}
+	return false
+}
+
+// firewallAnalysisPeer is the set of sources or destinations a rule applies
+// to. A peer without ranges, tags or service accounts matches any address.
+type firewallAnalysisPeer struct {
+	ranges          []*net.IPNet
+	tags            []string
+	serviceAccounts []string
+}
+
+func newFirewallAnalysisPeer(ranges, tags, serviceAccounts []string) (firewallAnalysisPeer, error) {
+	p := firewallAnalysisPeer{tags: tags, serviceAccounts: serviceAccounts}
+	for _, r := range ranges {
+		if !strings.Contains(r, "/") {
+			if strings.Contains(r, ":") {
+				r += "/128"
+			} else {
+				r += "/32"
+			}
+		}
+		_, ipnet, err := net.ParseCIDR(r)
+		if err != nil {
+			return p, fmt.Errorf("invalid IP range %q: %s", r, err)
+		}
+		p.ranges = append(p.ranges, ipnet)
+	}
+	return p, nil
+}
+
+func (p firewallAnalysisPeer) any() bool {
+	return len(p.ranges) == 0 && len(p.tags) == 0 && len(p.serviceAccounts) == 0
+}
+
+func (p firewallAnalysisPeer) covers(o firewallAnalysisPeer) bool {
+	if p.any() {
+		return true
+	}
+	if o.any() {
+		return false
+	}
+	for _, r := range o.ranges {
+		if !ipNetsContain(p.ranges, r) {
+			return false
+		}
+	}
+	return stringsSubset(o.tags, p.tags) && stringsSubset(o.serviceAccounts, p.serviceAccounts)
+}
+
+func (p firewallAnalysisPeer) overlaps(o firewallAnalysisPeer) bool {
+	if p.any() || o.any() {
+		return true
+	}
+	for _, a := range p.ranges {
+		for _, b := range o.ranges {
+			if a.Contains(b.IP) || b.Contains(a.IP) {
+				return true
+			}
+		}
+	}
+	return stringsIntersect(p.tags, o.tags) || stringsIntersect(p.serviceAccounts, o.serviceAccounts)
+}
+
+func (p firewallAnalysisPeer) matches(ip net.IP, tags []string, serviceAccount string) bool {
+	if p.any() {
+		return true
+	}
+	for _, r := range p.ranges {
+		if ip != nil && r.Contains(ip) {
+			return true
+		}
+	}
+	return stringsIntersect(p.tags, tags) || (serviceAccount != "" && stringInSlice(p.serviceAccounts, serviceAccount))
+}
+
+// firewallAnalysisTargets are the instances a rule applies to. Targets
+// without tags or service accounts apply to all instances of the network.
+type firewallAnalysisTargets struct {
+	tags            []string
+	serviceAccounts []string
+}
+
+func (t firewallAnalysisTargets) all() bool {
+	return len(t.tags) == 0 && len(t.serviceAccounts) == 0
+}
+
+func (t firewallAnalysisTargets) covers(o firewallAnalysisTargets) bool {
+	if t.all() {
+		return true
+	}
+	if o.all() {
+		return false
+	}
+	return stringsSubset(o.tags, t.tags) && stringsSubset(o.serviceAccounts, t.serviceAccounts)
+}
+
+func (t firewallAnalysisTargets) overlaps(o firewallAnalysisTargets) bool {
+	if t.all() || o.all() {
+		return true
+	}
+	// An instance may have both a tag and a service account, so rules that
+	// select targets differently may apply to the same instance.
+	if (len(t.tags) == 0) != (len(o.tags) == 0) {
+		return true
+	}
+	return stringsIntersect(t.tags, o.tags) || stringsIntersect(t.serviceAccounts, o.serviceAccounts)
+}
+
+func (t firewallAnalysisTargets) matches(tags []string, serviceAccount string) bool {
+	return t.all() || stringsIntersect(t.tags, tags) || (serviceAccount != "" && stringInSlice(t.serviceAccounts, serviceAccount))
+}
+
+// firewallAnalysisRule is a VPC firewall rule or a hierarchical firewall
+// policy rule, in the common form used by the analysis.
+type firewallAnalysisRule struct {
+	id             string
+	name           string
+	firewallPolicy string
+	// level is the evaluation stage of the rule: the index of its firewall
+	// policy in evaluation order, with the VPC firewall rules evaluated last.
+	level       int
+	priority    int64
+	direction   string
+	action      string
+	disabled    bool
+	source      firewallAnalysisPeer
+	destination firewallAnalysisPeer
+	targets     firewallAnalysisTargets
+	protocols   []firewallAnalysisProtocol
+}
+
+func newFirewallAnalysisRuleFromFirewall(id string, fw *compute.Firewall, level int) (*firewallAnalysisRule, error) {
+	r := &firewallAnalysisRule{
+		id:        id,
+		name:      fw.Name,
+		level:     level,
+		priority:  fw.Priority,
+		direction: fw.Direction,
+		action:    "allow",
+		disabled:  fw.Disabled,
+		targets: firewallAnalysisTargets{
+			tags:            fw.TargetTags,
+			serviceAccounts: fw.TargetServiceAccounts,
+		},
+	}
+	if r.direction == "" {
+		r.direction = "INGRESS"
+	}
+
+	var err error
+	if r.direction == "INGRESS" {
+		r.source, err = newFirewallAnalysisPeer(fw.SourceRanges, fw.SourceTags, fw.SourceServiceAccounts)
+	} else {
+		r.source, err = newFirewallAnalysisPeer(fw.SourceRanges, nil, nil)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("firewall %s: %s", fw.Name, err)
+	}
+	if r.destination, err = newFirewallAnalysisPeer(fw.DestinationRanges, nil, nil); err != nil {
+		return nil, fmt.Errorf("firewall %s: %s", fw.Name, err)
+	}
+
+	for _, a := range fw.Allowed {
+		p, err := newFirewallAnalysisProtocol(a.IPProtocol, a.Ports)
+		if err != nil {
+			return nil, fmt.Errorf("firewall %s: %s", fw.Name, err)
+		}
+		r.protocols = append(r.protocols, p)
+	}
+	if len(fw.Denied) > 0 {
+		r.action = "deny"
+		for _, a := range fw.Denied {
+			p, err := newFirewallAnalysisProtocol(a.IPProtocol, a.Ports)
+			if err != nil {
+				return nil, fmt.Errorf("firewall %s: %s", fw.Name, err)
+			}
+			r.protocols = append(r.protocols, p)
+		}
+	}
+	return r, nil
+}
+
+func newFirewallAnalysisRuleFromPolicyRule(id, policy string, rule *compute.FirewallPolicyRule, level int) (*firewallAnalysisRule, error) {
+	r := &firewallAnalysisRule{
+		id:             id,
+		name:           strconv.FormatInt(rule.Priority, 10),
+		firewallPolicy: policy,
+		level:          level,
+		priority:       rule.Priority,
+		direction:      rule.Direction,
+		action:         rule.Action,
+		disabled:       rule.Disabled,
+		targets: firewallAnalysisTargets{
+			serviceAccounts: rule.TargetServiceAccounts,
+		},
+	}
+	if rule.Match == nil {
+		return r, nil
+	}
+
+	var err error
+	if r.source, err = newFirewallAnalysisPeer(rule.Match.SrcIpRanges, nil, nil); err != nil {
+		return nil, fmt.Errorf("firewall policy %s rule %d: %s", policy, rule.Priority, err)
+	}
+	if r.destination, err = newFirewallAnalysisPeer(rule.Match.DestIpRanges, nil, nil); err != nil {
+		return nil, fmt.Errorf("firewall policy %s rule %d: %s", policy, rule.Priority, err)
+	}
+	for _, c := range rule.Match.Layer4Configs {
+		p, err := newFirewallAnalysisProtocol(c.IpProtocol, c.Ports)
+		if err != nil {
+			return nil, fmt.Errorf("firewall policy %s rule %d: %s", policy, rule.Priority, err)
+		}
+		r.protocols = append(r.protocols, p)
+	}
+	return r, nil
+}
+
+// covers returns whether r matches all the traffic o matches.
+func (r *firewallAnalysisRule) covers(o *firewallAnalysisRule) bool {
+	if r.direction != o.direction || !r.targets.covers(o.targets) || !r.source.covers(o.source) || !r.destination.covers(o.destination) {
+		return false
+	}
+	if len(r.protocols) == 0 {
+		return true
+	}
+	if len(o.protocols) == 0 {
+		return false
+	}
+	for _, op := range o.protocols {
+		covered := false
+		for _, rp := range r.protocols {
+			if rp.covers(op) {
+				covered = true
+				break
+			}
+		}
+		if !covered {
+			return false
+		}
+	}
+	return true
+}
+
+// overlaps returns whether r and o may match the same traffic.
+func (r *firewallAnalysisRule) overlaps(o *firewallAnalysisRule) bool {
+	if r.direction != o.direction || !r.targets.overlaps(o.targets) || !r.source.overlaps(o.source) || !r.destination.overlaps(o.destination) {
+		return false
+	}
+	if len(r.protocols) == 0 || len(o.protocols) == 0 {
+		return true
+	}
+	for _, rp := range r.protocols {
+		for _, op := range o.protocols {
+			if rp.overlaps(op) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// terminal returns whether the evaluation stops at r when it matches.
+func (r *firewallAnalysisRule) terminal() bool {
+	return r.action == "allow" || r.action == "deny"
+}
+
+// sortFirewallAnalysisRules sorts rules in evaluation order: by firewall
+// policy, then by priority. At equal priority, deny rules take precedence.
+func sortFirewallAnalysisRules(rules []*firewallAnalysisRule) {
+	sort.SliceStable(rules, func(i, j int) bool {
+		a, b := rules[i], rules[j]
+		if a.level != b.level {
+			return a.level < b.level
+		}
+		if a.priority != b.priority {
+			return a.priority < b.priority
+		}
+		if (a.action == "deny") != (b.action == "deny") {
+			return a.action == "deny"
+		}
+		return a.id < b.id
+	})
+}
+
+type firewallAnalysisConflict struct {
+	winningRule    string
+	overriddenRule string
+}
+
+type firewallAnalysis struct {
+	// rules in evaluation order
+	rules []*firewallAnalysisRule
+	// shadowedBy maps the ids of shadowed rules to the id of the rule
+	// shadowing them.
+	shadowedBy map[string]string
+	conflicts  []firewallAnalysisConflict
+}
+
+// analyzeFirewallRules finds the rules that never match because a rule
+// evaluated before them matches all of their traffic, and the pairs of
+// overlapping rules with opposite actions. Shadowing is only detected when a
+// single rule matches all the traffic of another.
+func analyzeFirewallRules(rules []*firewallAnalysisRule) *firewallAnalysis {
+	sorted := append([]*firewallAnalysisRule{}, rules...)
+	sortFirewallAnalysisRules(sorted)
+
+	a := &firewallAnalysis{
+		rules:      sorted,
+		shadowedBy: make(map[string]string),
+	}
+	for i, r := range sorted {
+		if r.disabled {
+			continue
+		}
+		for _, prev := range sorted[:i] {
+			if prev.disabled {
+				continue
+			}
+			// A goto_next rule only skips the rest of its own firewall policy.
+			if _, ok := a.shadowedBy[r.id]; !ok && (prev.terminal() || prev.level == r.level) && prev.covers(r) {
+				a.shadowedBy[r.id] = prev.id
+			}
+			if _, ok := a.shadowedBy[prev.id]; ok {
+				continue
+			}
+			if prev.terminal() && r.terminal() && prev.action != r.action && prev.overlaps(r) {
+				a.conflicts = append(a.conflicts, firewallAnalysisConflict{winningRule: prev.id, overriddenRule: r.id})
+			}
+		}
+	}
+	return a
+}
+
+// firewallAnalysisQuery describes a connection whose verdict is evaluated.
+// The targets are the instances receiving ingress traffic or sending egress
+// traffic.
+type firewallAnalysisQuery struct {
+	direction            string
+	sourceIp             net.IP
+	destinationIp        net.IP
+	protocol             string
+	port                 int
+	sourceTags           []string
+	sourceServiceAccount string
+	targetTags           []string
+	targetServiceAccount string
+}
+
+func (r *firewallAnalysisRule) matches(q firewallAnalysisQuery) bool {
+	if r.disabled || r.direction != q.direction || !r.targets.matches(q.targetTags, q.targetServiceAccount) {
+		return false
+	}
+	if !r.source.matches(q.sourceIp, q.sourceTags, q.sourceServiceAccount) || !r.destination.matches(q.destinationIp, nil, "") {
+		return false
+	}
+	if len(r.protocols) == 0 {
+		return true
+	}
+	for _, p := range r.protocols {
+		if p.matches(q.protocol, q.port) {
+			return true
+		}
+	}
+	return false
+}
+
+// verdict returns the action applied to the connection and the id of the
+// rule deciding it, or an empty id if the implied rules of the network apply.
+func (a *firewallAnalysis) verdict(q firewallAnalysisQuery) (string, string) {
+	skipLevel := -1
+	for _, r := range a.rules {
+		if r.level == skipLevel || !r.matches(q) {
+			continue
+		}
+		if r.terminal() {
+			return r.action, r.id
+		}
+		skipLevel = r.level
+	}
+	if q.direction == "EGRESS" {
+		return "allow", ""
+	}
+	return "deny", ""
+}
+
+// ipNetsContain returns whether n is within one of nets.
+func ipNetsContain(nets []*net.IPNet, n *net.IPNet) bool {
+	nOnes, nBits := n.Mask.Size()
+	for _, c := range nets {
+		ones, bits := c.Mask.Size()
+		if bits == nBits && ones <= nOnes && c.Contains(n.IP) {
+			return true
+		}
+	}
+	return false
+}
+
+func stringsSubset(sub, set []string) bool {
+	for _, s := range sub {
+		if !stringInSlice(set, s) {
+			return false
+		}
+	}
+	return true
+}
+
+func stringsIntersect(a, b []string) bool {
+	for _, s := range a {
+		if stringInSlice(b, s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,251 @@
+package google
+
+import (
+	"fmt"
+	"net"
+	"reflect"
+	"testing"
+
+	"google.golang.org/api/compute/v1"
+)
+
+func testFirewallAnalysisRules(t *testing.T) []*firewallAnalysisRule {
+	firewalls := []*compute.Firewall{
+		{
+			Name:         "allow-ssh",
+			Priority:     1000,
+			Direction:    "INGRESS",
+			SourceRanges: []string{"10.0.0.0/8"},
+			Allowed:      []*compute.FirewallAllowed{{IPProtocol: "tcp", Ports: []string{"22"}}},
+		},
+		{
+			// shadowed by allow-ssh
+			Name:         "allow-ssh-bastion",
+			Priority:     1000,
+			Direction:    "INGRESS",
+			SourceRanges: []string{"10.1.0.0/16", "10.2.0.1"},
+			TargetTags:   []string{"bastion"},
+			Allowed:      []*compute.FirewallAllowed{{IPProtocol: "6", Ports: []string{"22"}}},
+		},
+		{
+			Name:         "deny-web",
+			Priority:     500,
+			Direction:    "INGRESS",
+			SourceRanges: []string{"0.0.0.0/0"},
+			TargetTags:   []string{"web"},
+			Denied:       []*compute.FirewallDenied{{IPProtocol: "tcp", Ports: []string{"80-90"}}},
+		},
+		{
+			// shadowed by deny-web
+			Name:         "allow-web",
+			Priority:     900,
+			Direction:    "INGRESS",
+			SourceRanges: []string{"192.168.0.0/16"},
+			TargetTags:   []string{"web"},
+			Allowed:      []*compute.FirewallAllowed{{IPProtocol: "tcp", Ports: []string{"80", "85-86"}}},
+		},
+		{
+			// overlaps deny-web, but port 443 is still allowed
+			Name:         "allow-https",
+			Priority:     1000,
+			Direction:    "INGRESS",
+			SourceRanges: []string{"0.0.0.0/0"},
+			Allowed:      []*compute.FirewallAllowed{{IPProtocol: "tcp", Ports: []string{"80", "443"}}},
+		},
+		{
+			Name:       "allow-internal",
+			Priority:   2000,
+			Direction:  "INGRESS",
+			SourceTags: []string{"app"},
+			Allowed:    []*compute.FirewallAllowed{{IPProtocol: "all"}},
+		},
+		{
+			Name:              "deny-egress",
+			Priority:          65534,
+			Direction:         "EGRESS",
+			DestinationRanges: []string{"0.0.0.0/0"},
+			Denied:            []*compute.FirewallDenied{{IPProtocol: "all"}},
+		},
+		{
+			Name:              "allow-egress-google",
+			Priority:          1000,
+			Direction:         "EGRESS",
+			DestinationRanges: []string{"199.36.153.8/30"},
+			Allowed:           []*compute.FirewallAllowed{{IPProtocol: "tcp", Ports: []string{"443"}}},
+		},
+		{
+			// disabled rules neither shadow nor are shadowed
+			Name:         "disabled",
+			Priority:     0,
+			Direction:    "INGRESS",
+			SourceRanges: []string{"0.0.0.0/0"},
+			Denied:       []*compute.FirewallDenied{{IPProtocol: "all"}},
+			Disabled:     true,
+		},
+	}
+	policyRules := []*compute.FirewallPolicyRule{
+		{
+			Priority:  10,
+			Direction: "INGRESS",
+			Action:    "goto_next",
+			Match: &compute.FirewallPolicyRuleMatcher{
+				SrcIpRanges:   []string{"10.0.0.0/8"},
+				Layer4Configs: []*compute.FirewallPolicyRuleMatcherLayer4Config{{IpProtocol: "all"}},
+			},
+		},
+		{
+			// shadowed by the goto_next rule of the same policy
+			Priority:  20,
+			Direction: "INGRESS",
+			Action:    "deny",
+			Match: &compute.FirewallPolicyRuleMatcher{
+				SrcIpRanges:   []string{"10.3.0.0/16"},
+				Layer4Configs: []*compute.FirewallPolicyRuleMatcherLayer4Config{{IpProtocol: "tcp"}},
+			},
+		},
+		{
+			Priority:  30,
+			Direction: "INGRESS",
+			Action:    "deny",
+			Match: &compute.FirewallPolicyRuleMatcher{
+				SrcIpRanges:   []string{"203.0.113.0/24"},
+				Layer4Configs: []*compute.FirewallPolicyRuleMatcherLayer4Config{{IpProtocol: "all"}},
+			},
+		},
+	}
+
+	var rules []*firewallAnalysisRule
+	for _, p := range policyRules {
+		r, err := newFirewallAnalysisRuleFromPolicyRule(fmt.Sprintf("policy/%s-%d", p.Action, p.Priority), "policy", p, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rules = append(rules, r)
+	}
+	for _, fw := range firewalls {
+		r, err := newFirewallAnalysisRuleFromFirewall(fw.Name, fw, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rules = append(rules, r)
+	}
+	return rules
+}
+
+func TestAnalyzeFirewallRules(t *testing.T) {
+	analysis := analyzeFirewallRules(testFirewallAnalysisRules(t))
+
+	order := make([]string, 0, len(analysis.rules))
+	for _, r := range analysis.rules {
+		order = append(order, r.id)
+	}
+	expectedOrder := []string{
+		"policy/goto_next-10", "policy/deny-20", "policy/deny-30",
+		"disabled", "deny-web", "allow-web", "allow-egress-google", "allow-https", "allow-ssh", "allow-ssh-bastion", "allow-internal", "deny-egress",
+	}
+	if !reflect.DeepEqual(order, expectedOrder) {
+		t.Errorf("expected evaluation order %v, got %v", expectedOrder, order)
+	}
+
+	expectedShadowed := map[string]string{
+		"policy/deny-20":    "policy/goto_next-10",
+		"allow-web":         "deny-web",
+		"allow-ssh-bastion": "allow-ssh",
+	}
+	if !reflect.DeepEqual(analysis.shadowedBy, expectedShadowed) {
+		t.Errorf("expected shadowed rules %v, got %v", expectedShadowed, analysis.shadowedBy)
+	}
+
+	// Shadowed rules never take precedence over other rules.
+	expectedConflicts := []firewallAnalysisConflict{
+		{winningRule: "deny-web", overriddenRule: "allow-web"},
+		{winningRule: "policy/deny-30", overriddenRule: "allow-https"},
+		{winningRule: "deny-web", overriddenRule: "allow-https"},
+		{winningRule: "allow-egress-google", overriddenRule: "deny-egress"},
+	}
+	if !reflect.DeepEqual(analysis.conflicts, expectedConflicts) {
+		t.Errorf("expected conflicts\n%v\ngot\n%v", expectedConflicts, analysis.conflicts)
+	}
+}
+
+func TestFirewallAnalysisVerdict(t *testing.T) {
+	analysis := analyzeFirewallRules(testFirewallAnalysisRules(t))
+
+	cases := map[string]struct {
+		query          firewallAnalysisQuery
+		expectedAction string
+		expectedRule   string
+	}{
+		"allowed by range": {
+			query:          firewallAnalysisQuery{direction: "INGRESS", sourceIp: net.ParseIP("10.4.5.6"), protocol: "tcp", port: 22},
+			expectedAction: "allow",
+			expectedRule:   "allow-ssh",
+		},
+		"goto_next skips the rest of the policy": {
+			query:          firewallAnalysisQuery{direction: "INGRESS", sourceIp: net.ParseIP("10.3.0.1"), protocol: "TCP", port: 22},
+			expectedAction: "allow",
+			expectedRule:   "allow-ssh",
+		},
+		"denied by policy": {
+			query:          firewallAnalysisQuery{direction: "INGRESS", sourceIp: net.ParseIP("203.0.113.9"), protocol: "tcp", port: 443},
+			expectedAction: "deny",
+			expectedRule:   "policy/deny-30",
+		},
+		"denied by target tag": {
+			query:          firewallAnalysisQuery{direction: "INGRESS", sourceIp: net.ParseIP("192.168.1.1"), protocol: "tcp", port: 80, targetTags: []string{"web"}},
+			expectedAction: "deny",
+			expectedRule:   "deny-web",
+		},
+		"allowed without target tag": {
+			query:          firewallAnalysisQuery{direction: "INGRESS", sourceIp: net.ParseIP("192.168.1.1"), protocol: "tcp", port: 80},
+			expectedAction: "allow",
+			expectedRule:   "allow-https",
+		},
+		"allowed by source tag": {
+			query:          firewallAnalysisQuery{direction: "INGRESS", protocol: "udp", port: 53, sourceTags: []string{"app"}},
+			expectedAction: "allow",
+			expectedRule:   "allow-internal",
+		},
+		"implied ingress deny": {
+			query:          firewallAnalysisQuery{direction: "INGRESS", sourceIp: net.ParseIP("8.8.8.8"), protocol: "udp", port: 53},
+			expectedAction: "deny",
+			expectedRule:   "",
+		},
+		"egress allowed": {
+			query:          firewallAnalysisQuery{direction: "EGRESS", destinationIp: net.ParseIP("199.36.153.9"), protocol: "tcp", port: 443},
+			expectedAction: "allow",
+			expectedRule:   "allow-egress-google",
+		},
+		"egress denied": {
+			query:          firewallAnalysisQuery{direction: "EGRESS", destinationIp: net.ParseIP("8.8.8.8"), protocol: "tcp", port: 443},
+			expectedAction: "deny",
+			expectedRule:   "deny-egress",
+		},
+	}
+
+	for tn, tc := range cases {
+		action, rule := analysis.verdict(tc.query)
+		if action != tc.expectedAction || rule != tc.expectedRule {
+			t.Errorf("%s: expected %s by %q, got %s by %q", tn, tc.expectedAction, tc.expectedRule, action, rule)
+		}
+	}
+}
+
+func TestFirewallAnalysisProtocolCovers(t *testing.T) {
+	cases := []struct {
+		a, b     firewallAnalysisProtocol
+		expected bool
+	}{
+		{firewallAnalysisProtocol{protocol: "all"}, firewallAnalysisProtocol{protocol: "udp"}, true},
+		{firewallAnalysisProtocol{protocol: "tcp"}, firewallAnalysisProtocol{protocol: "tcp", ports: []firewallPortRange{{1, 10}}}, true},
+		{firewallAnalysisProtocol{protocol: "tcp", ports: []firewallPortRange{{1, 10}}}, firewallAnalysisProtocol{protocol: "tcp"}, false},
+		{firewallAnalysisProtocol{protocol: "tcp", ports: []firewallPortRange{{5, 10}, {1, 4}}}, firewallAnalysisProtocol{protocol: "tcp", ports: []firewallPortRange{{2, 8}}}, true},
+		{firewallAnalysisProtocol{protocol: "tcp", ports: []firewallPortRange{{1, 4}, {6, 10}}}, firewallAnalysisProtocol{protocol: "tcp", ports: []firewallPortRange{{2, 8}}}, false},
+		{firewallAnalysisProtocol{protocol: "udp"}, firewallAnalysisProtocol{protocol: "tcp"}, false},
+	}
+	for i, tc := range cases {
+		if got := tc.a.covers(tc.b); got != tc.expected {
+			t.Errorf("case %d: expected %v, got %v", i, tc.expected, got)
+		}
+	}
+}
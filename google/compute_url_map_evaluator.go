@@ -0,0 +1,457 @@
+package google
+
+import (
+	"fmt"
+	"math"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// urlMapRequest is a request routed by evaluateUrlMap.
+type urlMapRequest struct {
+	scheme string
+	host   string
+	// path without the query string
+	path     string
+	rawQuery string
+	// header names are lower case
+	headers map[string]string
+}
+
+// newUrlMapRequest builds a request from a host and a path that may include a
+// query string.
+func newUrlMapRequest(scheme, host, path string, headers map[string]string) urlMapRequest {
+	if i := strings.Index(path, "#"); i >= 0 {
+		path = path[:i]
+	}
+	rawQuery := ""
+	if i := strings.Index(path, "?"); i >= 0 {
+		path, rawQuery = path[:i], path[i+1:]
+	}
+
+	r := urlMapRequest{
+		scheme:   scheme,
+		host:     host,
+		path:     path,
+		rawQuery: rawQuery,
+		headers: map[string]string{
+			"host":       host,
+			":authority": host,
+			":scheme":    scheme,
+			":path":      path,
+		},
+	}
+	if rawQuery != "" {
+		r.headers[":path"] = path + "?" + rawQuery
+	}
+	for k, v := range headers {
+		r.headers[strings.ToLower(k)] = v
+	}
+	return r
+}
+
+type urlMapWeightedBackendService struct {
+	backendService string
+	weight         int
+}
+
+// urlMapRoute is the outcome of routing a request through a URL map.
+type urlMapRoute struct {
+	// the name of the selected path matcher, empty if no host rule matched
+	pathMatcher string
+	// the rule that selected the route, e.g. "route_rule:10",
+	// "path_rule:/static/*" or "default"
+	matchedRule string
+
+	service                 string
+	weightedBackendServices []urlMapWeightedBackendService
+	// host and path forwarded to the backend, after URL rewrites
+	host string
+	path string
+
+	redirectUrl          string
+	redirectResponseCode int
+}
+
+var urlMapRedirectResponseCodes = map[string]int{
+	"MOVED_PERMANENTLY_DEFAULT": 301,
+	"FOUND":                     302,
+	"SEE_OTHER":                 303,
+	"TEMPORARY_REDIRECT":        307,
+	"PERMANENT_REDIRECT":        308,
+}
+
+// evaluateUrlMap routes a request the way an HTTP(S) load balancer does, given
+// the API representation of a URL map. Route rules with metadata filters only
+// apply to Traffic Director and never match.
+func evaluateUrlMap(urlMap map[string]interface{}, req urlMapRequest) (*urlMapRoute, error) {
+	route := &urlMapRoute{host: req.host, path: req.path}
+
+	name := urlMapFindPathMatcher(urlMap, req.host)
+	if name == "" {
+		route.matchedRule = "default"
+		urlMapApplyAction(route, req, urlMapDefaultAction(urlMap), "")
+		return route, nil
+	}
+
+	var pm map[string]interface{}
+	for _, m := range urlMapList(urlMap, "pathMatchers") {
+		if urlMapString(m, "name") == name {
+			pm = m
+			break
+		}
+	}
+	if pm == nil {
+		return nil, fmt.Errorf("host rule for %q references path matcher %q, which doesn't exist", req.host, name)
+	}
+	route.pathMatcher = name
+
+	routeRules := urlMapList(pm, "routeRules")
+	sort.SliceStable(routeRules, func(i, j int) bool {
+		return urlMapInt(routeRules[i]["priority"]) < urlMapInt(routeRules[j]["priority"])
+	})
+	for _, rr := range routeRules {
+		matched, ok, err := urlMapRouteRuleMatches(rr, req)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			route.matchedRule = fmt.Sprintf("route_rule:%d", urlMapInt(rr["priority"]))
+			urlMapApplyAction(route, req, urlMapAction{
+				service:     urlMapString(rr, "service"),
+				routeAction: urlMapObject(rr, "routeAction"),
+				urlRedirect: urlMapObject(rr, "urlRedirect"),
+			}, matched)
+			return route, nil
+		}
+	}
+
+	if pr, pattern := urlMapFindPathRule(pm, req.path); pr != nil {
+		route.matchedRule = "path_rule:" + pattern
+		urlMapApplyAction(route, req, urlMapAction{
+			service:     urlMapString(pr, "service"),
+			routeAction: urlMapObject(pr, "routeAction"),
+			urlRedirect: urlMapObject(pr, "urlRedirect"),
+		}, strings.TrimSuffix(pattern, "*"))
+		return route, nil
+	}
+
+	route.matchedRule = "default"
+	urlMapApplyAction(route, req, urlMapDefaultAction(pm), "")
+	return route, nil
+}
+
+// urlMapFindPathMatcher returns the path matcher of the host rule that matches
+// host most specifically. Exact hosts take precedence over wildcards, and
+// longer wildcards over shorter ones.
+func urlMapFindPathMatcher(urlMap map[string]interface{}, host string) string {
+	host = strings.ToLower(host)
+	hostname := host
+	if i := strings.LastIndex(host, ":"); i >= 0 && !strings.HasSuffix(host, "]") {
+		hostname = host[:i]
+	}
+
+	best, bestScore := "", -1
+	for _, hr := range urlMapList(urlMap, "hostRules") {
+		for _, h := range urlMapStrings(hr, "hosts") {
+			pattern := strings.ToLower(h)
+			candidate := hostname
+			if strings.Contains(pattern, ":") {
+				candidate = host
+			}
+
+			score := -1
+			switch {
+			case pattern == candidate:
+				score = math.MaxInt32
+			case pattern == "*":
+				score = 0
+			case strings.HasPrefix(pattern, "*") && strings.HasSuffix(candidate, pattern[1:]) && len(candidate) > len(pattern)-1:
+				score = len(pattern)
+			}
+			if score > bestScore {
+				best, bestScore = urlMapString(hr, "pathMatcher"), score
+			}
+		}
+	}
+	return best
+}
+
+// urlMapFindPathRule returns the path rule with the longest pattern matching
+// path, along with the pattern. Patterns ending in /* match any path under the
+// prefix, other patterns only match exactly.
+func urlMapFindPathRule(pm map[string]interface{}, path string) (map[string]interface{}, string) {
+	var best map[string]interface{}
+	bestPattern, bestScore := "", -1
+	for _, pr := range urlMapList(pm, "pathRules") {
+		for _, p := range urlMapStrings(pr, "paths") {
+			score := -1
+			if strings.HasSuffix(p, "*") {
+				if strings.HasPrefix(path, strings.TrimSuffix(p, "*")) {
+					score = 2 * (len(p) - 1)
+				}
+			} else if p == path {
+				// exact matches win over prefixes of the same length
+				score = 2*len(p) + 1
+			}
+			if score > bestScore {
+				best, bestPattern, bestScore = pr, p, score
+			}
+		}
+	}
+	return best, bestPattern
+}
+
+// urlMapRouteRuleMatches returns whether any of the match rules of a route rule
+// matches the request, and the portion of the path that was matched.
+func urlMapRouteRuleMatches(rr map[string]interface{}, req urlMapRequest) (string, bool, error) {
+	matchRules := urlMapList(rr, "matchRules")
+	if len(matchRules) == 0 {
+		return "", true, nil
+	}
+	for _, mr := range matchRules {
+		matched, ok, err := urlMapMatchRuleMatches(mr, req)
+		if err != nil || ok {
+			return matched, ok, err
+		}
+	}
+	return "", false, nil
+}
+
+func urlMapMatchRuleMatches(mr map[string]interface{}, req urlMapRequest) (string, bool, error) {
+	if len(urlMapList(mr, "metadataFilters")) > 0 {
+		return "", false, nil
+	}
+
+	path := req.path
+	ignoreCase := urlMapBool(mr, "ignoreCase")
+	matched := ""
+	if prefix := urlMapString(mr, "prefixMatch"); prefix != "" {
+		if !urlMapHasPrefix(path, prefix, ignoreCase) {
+			return "", false, nil
+		}
+		matched = path[:len(prefix)]
+	} else if full := urlMapString(mr, "fullPathMatch"); full != "" {
+		if path != full && !(ignoreCase && strings.EqualFold(path, full)) {
+			return "", false, nil
+		}
+		matched = path
+	} else if re := urlMapString(mr, "regexMatch"); re != "" {
+		ok, err := urlMapRegexMatches(re, path)
+		if err != nil || !ok {
+			return "", false, err
+		}
+		matched = path
+	}
+
+	for _, hm := range urlMapList(mr, "headerMatches") {
+		ok, err := urlMapHeaderMatches(hm, req.headers)
+		if err != nil || !ok {
+			return "", false, err
+		}
+	}
+
+	query, err := url.ParseQuery(req.rawQuery)
+	if err != nil {
+		return "", false, fmt.Errorf("invalid query string %q: %s", req.rawQuery, err)
+	}
+	for _, qm := range urlMapList(mr, "queryParameterMatches") {
+		values, present := query[urlMapString(qm, "name")]
+		value := ""
+		if present {
+			value = values[0]
+		}
+
+		var ok bool
+		if _, isSet := qm["exactMatch"]; isSet {
+			ok = present && value == urlMapString(qm, "exactMatch")
+		} else if re := urlMapString(qm, "regexMatch"); re != "" {
+			ok, err = urlMapRegexMatches(re, value)
+			ok = ok && present
+		} else {
+			ok = present
+		}
+		if err != nil || !ok {
+			return "", false, err
+		}
+	}
+
+	return matched, true, nil
+}
+
+func urlMapHeaderMatches(hm map[string]interface{}, headers map[string]string) (bool, error) {
+	value, present := headers[strings.ToLower(urlMapString(hm, "headerName"))]
+
+	var ok bool
+	var err error
+	if _, isSet := hm["exactMatch"]; isSet {
+		ok = present && value == urlMapString(hm, "exactMatch")
+	} else if re := urlMapString(hm, "regexMatch"); re != "" {
+		ok, err = urlMapRegexMatches(re, value)
+		ok = ok && present
+	} else if prefix := urlMapString(hm, "prefixMatch"); prefix != "" {
+		ok = present && strings.HasPrefix(value, prefix)
+	} else if suffix := urlMapString(hm, "suffixMatch"); suffix != "" {
+		ok = present && strings.HasSuffix(value, suffix)
+	} else if r := urlMapObject(hm, "rangeMatch"); r != nil {
+		n, perr := strconv.ParseInt(value, 10, 64)
+		ok = present && perr == nil && n >= int64(urlMapInt(r["rangeStart"])) && n < int64(urlMapInt(r["rangeEnd"]))
+	} else {
+		ok = present
+	}
+	if err != nil {
+		return false, err
+	}
+
+	if urlMapBool(hm, "invertMatch") {
+		return !ok, nil
+	}
+	return ok, nil
+}
+
+type urlMapAction struct {
+	service     string
+	routeAction map[string]interface{}
+	urlRedirect map[string]interface{}
+}
+
+func urlMapDefaultAction(m map[string]interface{}) urlMapAction {
+	return urlMapAction{
+		service:     urlMapString(m, "defaultService"),
+		routeAction: urlMapObject(m, "defaultRouteAction"),
+		urlRedirect: urlMapObject(m, "defaultUrlRedirect"),
+	}
+}
+
+// urlMapApplyAction sets the backend, rewrites or redirect of a route. matched
+// is the portion of the path matched by the rule, which prefix rewrites and
+// prefix redirects replace.
+func urlMapApplyAction(route *urlMapRoute, req urlMapRequest, action urlMapAction, matched string) {
+	if r := action.urlRedirect; r != nil {
+		scheme := req.scheme
+		if urlMapBool(r, "httpsRedirect") {
+			scheme = "https"
+		}
+		host := req.host
+		if h := urlMapString(r, "hostRedirect"); h != "" {
+			host = h
+		}
+		path := req.path
+		if p := urlMapString(r, "pathRedirect"); p != "" {
+			path = p
+		} else if p := urlMapString(r, "prefixRedirect"); p != "" {
+			path = p + strings.TrimPrefix(req.path, matched)
+		}
+
+		route.redirectUrl = fmt.Sprintf("%s://%s%s", scheme, host, path)
+		if !urlMapBool(r, "stripQuery") && req.rawQuery != "" {
+			route.redirectUrl += "?" + req.rawQuery
+		}
+		route.redirectResponseCode = 301
+		if code, ok := urlMapRedirectResponseCodes[urlMapString(r, "redirectResponseCode")]; ok {
+			route.redirectResponseCode = code
+		}
+		return
+	}
+
+	route.service = urlMapRelativeLink(action.service)
+	if ra := action.routeAction; ra != nil {
+		for _, wbs := range urlMapList(ra, "weightedBackendServices") {
+			route.weightedBackendServices = append(route.weightedBackendServices, urlMapWeightedBackendService{
+				backendService: urlMapRelativeLink(urlMapString(wbs, "backendService")),
+				weight:         urlMapInt(wbs["weight"]),
+			})
+		}
+		if rw := urlMapObject(ra, "urlRewrite"); rw != nil {
+			if h := urlMapString(rw, "hostRewrite"); h != "" {
+				route.host = h
+			}
+			if p := urlMapString(rw, "pathPrefixRewrite"); p != "" {
+				route.path = p + strings.TrimPrefix(req.path, matched)
+			}
+		}
+	}
+}
+
+func urlMapHasPrefix(s, prefix string, ignoreCase bool) bool {
+	if len(s) < len(prefix) {
+		return false
+	}
+	if ignoreCase {
+		return strings.EqualFold(s[:len(prefix)], prefix)
+	}
+	return s[:len(prefix)] == prefix
+}
+
+// urlMapRegexMatches returns whether the RE2 expression matches all of s.
+func urlMapRegexMatches(re, s string) (bool, error) {
+	r, err := regexp.Compile("^(?:" + re + ")$")
+	if err != nil {
+		return false, fmt.Errorf("invalid regular expression %q: %s", re, err)
+	}
+	return r.MatchString(s), nil
+}
+
+// urlMapRelativeLink returns the relative resource name of a backend service
+// or bucket reference, so that results compare equal to their id.
+func urlMapRelativeLink(link string) string {
+	if rel, err := getRelativePath(link); err == nil {
+		return rel
+	}
+	return link
+}
+
+func urlMapList(m map[string]interface{}, key string) []map[string]interface{} {
+	raw, _ := m[key].([]interface{})
+	l := make([]map[string]interface{}, 0, len(raw))
+	for _, v := range raw {
+		if o, ok := v.(map[string]interface{}); ok {
+			l = append(l, o)
+		}
+	}
+	return l
+}
+
+func urlMapObject(m map[string]interface{}, key string) map[string]interface{} {
+	o, _ := m[key].(map[string]interface{})
+	return o
+}
+
+func urlMapStrings(m map[string]interface{}, key string) []string {
+	raw, _ := m[key].([]interface{})
+	l := make([]string, 0, len(raw))
+	for _, v := range raw {
+		if s, ok := v.(string); ok {
+			l = append(l, s)
+		}
+	}
+	return l
+}
+
+func urlMapString(m map[string]interface{}, key string) string {
+	s, _ := m[key].(string)
+	return s
+}
+
+func urlMapBool(m map[string]interface{}, key string) bool {
+	b, _ := m[key].(bool)
+	return b
+}
+
+// urlMapInt converts a number of the API representation, which is a float64
+// when decoded from a response and an int when expanded from configuration.
+func urlMapInt(v interface{}) int {
+	switch n := v.(type) {
+	case int:
+		return n
+	case float64:
+		return int(n)
+	case string:
+		i, _ := strconv.Atoi(n)
+		return i
+	}
+	return 0
+}
@@ -0,0 +1,351 @@
+package google
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+// testUrlMapRouting is a URL map as returned by the API.
+const testUrlMapRouting = `{
+  "defaultService": "https://www.googleapis.com/compute/v1/projects/my-project/global/backendServices/default",
+  "hostRules": [
+    {"hosts": ["example.com"], "pathMatcher": "exact"},
+    {"hosts": ["*.example.com"], "pathMatcher": "wildcard"},
+    {"hosts": ["*.api.example.com"], "pathMatcher": "routes"},
+    {"hosts": ["redirect.example.com:8080"], "pathMatcher": "redirects"}
+  ],
+  "pathMatchers": [
+    {
+      "name": "exact",
+      "defaultService": "https://www.googleapis.com/compute/v1/projects/my-project/global/backendServices/home",
+      "pathRules": [
+        {"paths": ["/static", "/static/*"], "service": "https://www.googleapis.com/compute/v1/projects/my-project/global/backendBuckets/static"},
+        {"paths": ["/static/images/*"], "service": "https://www.googleapis.com/compute/v1/projects/my-project/global/backendBuckets/images"},
+        {
+          "paths": ["/v1/*"],
+          "service": "https://www.googleapis.com/compute/v1/projects/my-project/global/backendServices/api",
+          "routeAction": {"urlRewrite": {"pathPrefixRewrite": "/api/v1/", "hostRewrite": "api.internal"}}
+        }
+      ]
+    },
+    {
+      "name": "wildcard",
+      "defaultService": "https://www.googleapis.com/compute/v1/projects/my-project/global/backendServices/wildcard"
+    },
+    {
+      "name": "routes",
+      "defaultService": "https://www.googleapis.com/compute/v1/projects/my-project/global/backendServices/api",
+      "routeRules": [
+        {
+          "priority": 20,
+          "matchRules": [{"prefixMatch": "/"}],
+          "routeAction": {
+            "weightedBackendServices": [
+              {"backendService": "https://www.googleapis.com/compute/v1/projects/my-project/global/backendServices/stable", "weight": 90},
+              {"backendService": "https://www.googleapis.com/compute/v1/projects/my-project/global/backendServices/canary", "weight": 10}
+            ]
+          }
+        },
+        {
+          "priority": 1,
+          "matchRules": [{
+            "prefixMatch": "/",
+            "headerMatches": [{"headerName": "x-canary", "exactMatch": "true"}]
+          }],
+          "service": "https://www.googleapis.com/compute/v1/projects/my-project/global/backendServices/canary"
+        },
+        {
+          "priority": 2,
+          "matchRules": [
+            {"fullPathMatch": "/Health", "ignoreCase": true},
+            {"regexMatch": "/status/[0-9]+"}
+          ],
+          "service": "https://www.googleapis.com/compute/v1/projects/my-project/global/backendServices/health"
+        },
+        {
+          "priority": 3,
+          "matchRules": [{
+            "prefixMatch": "/search",
+            "queryParameterMatches": [{"name": "debug", "presentMatch": true}],
+            "headerMatches": [
+              {"headerName": "x-version", "rangeMatch": {"rangeStart": "2", "rangeEnd": "5"}},
+              {"headerName": "x-blocked", "presentMatch": true, "invertMatch": true}
+            ]
+          }],
+          "service": "https://www.googleapis.com/compute/v1/projects/my-project/global/backendServices/debug"
+        },
+        {
+          "priority": 4,
+          "matchRules": [{"prefixMatch": "/internal", "metadataFilters": [{"filterMatchCriteria": "MATCH_ALL"}]}],
+          "service": "https://www.googleapis.com/compute/v1/projects/my-project/global/backendServices/internal"
+        }
+      ]
+    },
+    {
+      "name": "redirects",
+      "defaultUrlRedirect": {"httpsRedirect": true, "stripQuery": false},
+      "routeRules": [
+        {
+          "priority": 1,
+          "matchRules": [{"prefixMatch": "/old/"}],
+          "urlRedirect": {"prefixRedirect": "/new/", "redirectResponseCode": "FOUND", "stripQuery": true}
+        }
+      ]
+    }
+  ]
+}`
+
+func TestEvaluateUrlMap(t *testing.T) {
+	var urlMap map[string]interface{}
+	if err := json.Unmarshal([]byte(testUrlMapRouting), &urlMap); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := map[string]struct {
+		scheme   string
+		host     string
+		path     string
+		headers  map[string]string
+		expected urlMapRoute
+	}{
+		"no host rule": {
+			host: "other.com",
+			path: "/",
+			expected: urlMapRoute{
+				matchedRule: "default",
+				service:     "projects/my-project/global/backendServices/default",
+				host:        "other.com",
+				path:        "/",
+			},
+		},
+		"path matcher default": {
+			host: "Example.com:443",
+			path: "/index.html",
+			expected: urlMapRoute{
+				pathMatcher: "exact",
+				matchedRule: "default",
+				service:     "projects/my-project/global/backendServices/home",
+				host:        "Example.com:443",
+				path:        "/index.html",
+			},
+		},
+		"exact path rule": {
+			host: "example.com",
+			path: "/static",
+			expected: urlMapRoute{
+				pathMatcher: "exact",
+				matchedRule: "path_rule:/static",
+				service:     "projects/my-project/global/backendBuckets/static",
+				host:        "example.com",
+				path:        "/static",
+			},
+		},
+		"longest path rule": {
+			host: "example.com",
+			path: "/static/images/logo.png?v=2",
+			expected: urlMapRoute{
+				pathMatcher: "exact",
+				matchedRule: "path_rule:/static/images/*",
+				service:     "projects/my-project/global/backendBuckets/images",
+				host:        "example.com",
+				path:        "/static/images/logo.png",
+			},
+		},
+		"path rule rewrite": {
+			host: "example.com",
+			path: "/v1/users",
+			expected: urlMapRoute{
+				pathMatcher: "exact",
+				matchedRule: "path_rule:/v1/*",
+				service:     "projects/my-project/global/backendServices/api",
+				host:        "api.internal",
+				path:        "/api/v1/users",
+			},
+		},
+		"wildcard host": {
+			host: "www.example.com",
+			path: "/",
+			expected: urlMapRoute{
+				pathMatcher: "wildcard",
+				matchedRule: "default",
+				service:     "projects/my-project/global/backendServices/wildcard",
+				host:        "www.example.com",
+				path:        "/",
+			},
+		},
+		"longest wildcard host and weighted backends": {
+			host: "eu.api.example.com",
+			path: "/users",
+			expected: urlMapRoute{
+				pathMatcher: "routes",
+				matchedRule: "route_rule:20",
+				weightedBackendServices: []urlMapWeightedBackendService{
+					{backendService: "projects/my-project/global/backendServices/stable", weight: 90},
+					{backendService: "projects/my-project/global/backendServices/canary", weight: 10},
+				},
+				host: "eu.api.example.com",
+				path: "/users",
+			},
+		},
+		"route rule priority": {
+			host:    "eu.api.example.com",
+			path:    "/users",
+			headers: map[string]string{"X-Canary": "true"},
+			expected: urlMapRoute{
+				pathMatcher: "routes",
+				matchedRule: "route_rule:1",
+				service:     "projects/my-project/global/backendServices/canary",
+				host:        "eu.api.example.com",
+				path:        "/users",
+			},
+		},
+		"full path match ignoring case": {
+			host: "eu.api.example.com",
+			path: "/health",
+			expected: urlMapRoute{
+				pathMatcher: "routes",
+				matchedRule: "route_rule:2",
+				service:     "projects/my-project/global/backendServices/health",
+				host:        "eu.api.example.com",
+				path:        "/health",
+			},
+		},
+		"regex match": {
+			host: "eu.api.example.com",
+			path: "/status/500",
+			expected: urlMapRoute{
+				pathMatcher: "routes",
+				matchedRule: "route_rule:2",
+				service:     "projects/my-project/global/backendServices/health",
+				host:        "eu.api.example.com",
+				path:        "/status/500",
+			},
+		},
+		"query and header matches": {
+			host:    "eu.api.example.com",
+			path:    "/search?q=x&debug",
+			headers: map[string]string{"x-version": "4"},
+			expected: urlMapRoute{
+				pathMatcher: "routes",
+				matchedRule: "route_rule:3",
+				service:     "projects/my-project/global/backendServices/debug",
+				host:        "eu.api.example.com",
+				path:        "/search",
+			},
+		},
+		"header out of range": {
+			host:    "eu.api.example.com",
+			path:    "/search?debug",
+			headers: map[string]string{"x-version": "5"},
+			expected: urlMapRoute{
+				pathMatcher: "routes",
+				matchedRule: "route_rule:20",
+				weightedBackendServices: []urlMapWeightedBackendService{
+					{backendService: "projects/my-project/global/backendServices/stable", weight: 90},
+					{backendService: "projects/my-project/global/backendServices/canary", weight: 10},
+				},
+				host: "eu.api.example.com",
+				path: "/search",
+			},
+		},
+		"inverted present match": {
+			host:    "eu.api.example.com",
+			path:    "/search?debug=1",
+			headers: map[string]string{"x-version": "2", "x-blocked": ""},
+			expected: urlMapRoute{
+				pathMatcher: "routes",
+				matchedRule: "route_rule:20",
+				weightedBackendServices: []urlMapWeightedBackendService{
+					{backendService: "projects/my-project/global/backendServices/stable", weight: 90},
+					{backendService: "projects/my-project/global/backendServices/canary", weight: 10},
+				},
+				host: "eu.api.example.com",
+				path: "/search",
+			},
+		},
+		"metadata filters never match": {
+			host: "eu.api.example.com",
+			path: "/internal",
+			expected: urlMapRoute{
+				pathMatcher: "routes",
+				matchedRule: "route_rule:20",
+				weightedBackendServices: []urlMapWeightedBackendService{
+					{backendService: "projects/my-project/global/backendServices/stable", weight: 90},
+					{backendService: "projects/my-project/global/backendServices/canary", weight: 10},
+				},
+				host: "eu.api.example.com",
+				path: "/internal",
+			},
+		},
+		"host with port and prefix redirect": {
+			host: "redirect.example.com:8080",
+			path: "/old/page?a=b",
+			expected: urlMapRoute{
+				pathMatcher:          "redirects",
+				matchedRule:          "route_rule:1",
+				host:                 "redirect.example.com:8080",
+				path:                 "/old/page",
+				redirectUrl:          "http://redirect.example.com:8080/new/page",
+				redirectResponseCode: 302,
+			},
+		},
+		"https redirect": {
+			host: "redirect.example.com:8080",
+			path: "/page?a=b",
+			expected: urlMapRoute{
+				pathMatcher:          "redirects",
+				matchedRule:          "default",
+				host:                 "redirect.example.com:8080",
+				path:                 "/page",
+				redirectUrl:          "https://redirect.example.com:8080/page?a=b",
+				redirectResponseCode: 301,
+			},
+		},
+		"host without port": {
+			host: "redirect.example.com",
+			path: "/old/page",
+			expected: urlMapRoute{
+				pathMatcher: "wildcard",
+				matchedRule: "default",
+				service:     "projects/my-project/global/backendServices/wildcard",
+				host:        "redirect.example.com",
+				path:        "/old/page",
+			},
+		},
+	}
+
+	for tn, tc := range cases {
+		scheme := tc.scheme
+		if scheme == "" {
+			scheme = "http"
+		}
+		route, err := evaluateUrlMap(urlMap, newUrlMapRequest(scheme, tc.host, tc.path, tc.headers))
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", tn, err)
+			continue
+		}
+		if !reflect.DeepEqual(*route, tc.expected) {
+			t.Errorf("%s: expected\n%#v\ngot\n%#v", tn, tc.expected, *route)
+		}
+	}
+}
+
+func TestEvaluateUrlMap_errors(t *testing.T) {
+	cases := map[string]string{
+		"missing path matcher": `{"hostRules": [{"hosts": ["*"], "pathMatcher": "missing"}]}`,
+		"invalid regex": `{
+		  "hostRules": [{"hosts": ["*"], "pathMatcher": "pm"}],
+		  "pathMatchers": [{"name": "pm", "routeRules": [{"priority": 1, "matchRules": [{"regexMatch": "/("}]}]}]
+		}`,
+	}
+	for tn, raw := range cases {
+		var urlMap map[string]interface{}
+		if err := json.Unmarshal([]byte(raw), &urlMap); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := evaluateUrlMap(urlMap, newUrlMapRequest("http", "example.com", "/", nil)); err == nil {
+			t.Errorf("%s: expected error, got none", tn)
+		}
+	}
+}
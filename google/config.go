@@ -148,6 +148,9 @@ type Config struct {
 	BatchingConfig                     *batchingConfig
 	UserProjectOverride                bool
 	RequestReason                      string
+	SkipIamConditionValidation         bool
+	DetectIamConflicts                 bool
+	SkipCloudBuildTriggerValidation    bool
 	RequestTimeout                     time.Duration
 	// PollInterval is passed to resource.StateChangeConf in common_operation.go
 	// It controls the interval at which we poll for successful operations
@@ -196,6 +199,7 @@ type Config struct {
 	GameServicesBasePath         string
 	GKEHubBasePath               string
 	HealthcareBasePath           string
+	IAM2BasePath                 string
 	IapBasePath                  string
 	IdentityPlatformBasePath     string
 	KMSBasePath                  string
@@ -242,6 +246,16 @@ type Config struct {
 	requestBatcherServiceUsage *RequestBatcher
 	requestBatcherIam          *RequestBatcher
 
+	// IAM resources planned by this provider instance, see iamConflictCustomizeDiff
+	iamResourcesInConfig *iamConflictRegistry
+
+	// Services kept enabled by project service resources, see
+	// resourceGoogleProjectServicesCustomizeDiff
+	projectServicesInConfig *projectServiceRegistry
+
+	// Ranges allocated by google_compute_subnetwork_range_allocation
+	subnetworkRangeAllocations *subnetworkRangeRegistry
+
 	// start DCLBasePaths
 	// dataprocBasePath is implemented in mm
 	AssuredWorkloadsBasePath     string
@@ -295,6 +309,7 @@ const FirestoreBasePathKey = "Firestore"
 const GameServicesBasePathKey = "GameServices"
 const GKEHubBasePathKey = "GKEHub"
 const HealthcareBasePathKey = "Healthcare"
+const IAM2BasePathKey = "IAM2"
 const IapBasePathKey = "Iap"
 const IdentityPlatformBasePathKey = "IdentityPlatform"
 const KMSBasePathKey = "KMS"
@@ -377,6 +392,7 @@ var DefaultBasePaths = map[string]string{
 	GameServicesBasePathKey:         "https://gameservices.googleapis.com/v1/",
 	GKEHubBasePathKey:               "https://gkehub.googleapis.com/v1/",
 	HealthcareBasePathKey:           "https://healthcare.googleapis.com/v1/",
+	IAM2BasePathKey:                 "https://iam.googleapis.com/v2/",
 	IapBasePathKey:                  "https://iap.googleapis.com/v1/",
 	IdentityPlatformBasePathKey:     "https://identitytoolkit.googleapis.com/v2/",
 	KMSBasePathKey:                  "https://cloudkms.googleapis.com/v1/",
@@ -488,6 +504,9 @@ func (c *Config) LoadAndValidate(ctx context.Context) error {
 	c.Region = GetRegionFromRegionSelfLink(c.Region)
 	c.requestBatcherServiceUsage = NewRequestBatcher("Service Usage", ctx, c.BatchingConfig)
 	c.requestBatcherIam = NewRequestBatcher("IAM", ctx, c.BatchingConfig)
+	c.iamResourcesInConfig = newIamConflictRegistry()
+	c.projectServicesInConfig = newProjectServiceRegistry()
+	c.subnetworkRangeAllocations = newSubnetworkRangeRegistry()
 	c.PollInterval = 10 * time.Second
 
 	// gRPC Logging setup
@@ -1221,6 +1240,7 @@ func ConfigureBasePaths(c *Config) {
 	c.GameServicesBasePath = DefaultBasePaths[GameServicesBasePathKey]
 	c.GKEHubBasePath = DefaultBasePaths[GKEHubBasePathKey]
 	c.HealthcareBasePath = DefaultBasePaths[HealthcareBasePathKey]
+	c.IAM2BasePath = DefaultBasePaths[IAM2BasePathKey]
 	c.IapBasePath = DefaultBasePaths[IapBasePathKey]
 	c.IdentityPlatformBasePath = DefaultBasePaths[IdentityPlatformBasePathKey]
 	c.KMSBasePath = DefaultBasePaths[KMSBasePathKey]
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"strings"
 	"time"
 
 	"google.golang.org/api/container/v1"
@@ -84,9 +85,52 @@ func (w *ContainerOperationWaiter) QueryOp() (interface{}, error) {
 		return opErr
 	}, DefaultRequestTimeout)
 
+	if err == nil && op != nil && op.Progress != nil {
+		log.Printf("[INFO] Operation %s progress: %s", op.Name, containerOperationProgressString(op.Progress))
+	}
+
 	return op, err
 }
 
+// containerOperationProgressString summarizes the progress of long running
+// operations such as blue-green node pool upgrades, e.g.
+// "DRAINING_BLUE_POOL: RUNNING (nodes_drained=2, nodes_total=6)".
+func containerOperationProgressString(p *container.OperationProgress) string {
+	if p == nil {
+		return ""
+	}
+
+	var metrics []string
+	for _, m := range p.Metrics {
+		switch {
+		case m.StringValue != "":
+			metrics = append(metrics, fmt.Sprintf("%s=%s", m.Name, m.StringValue))
+		case m.DoubleValue != 0:
+			metrics = append(metrics, fmt.Sprintf("%s=%g", m.Name, m.DoubleValue))
+		default:
+			metrics = append(metrics, fmt.Sprintf("%s=%d", m.Name, m.IntValue))
+		}
+	}
+
+	parts := []string{}
+	if p.Name != "" {
+		parts = append(parts, p.Name+":")
+	}
+	if p.Status != "" {
+		parts = append(parts, p.Status)
+	}
+	if len(metrics) > 0 {
+		parts = append(parts, fmt.Sprintf("(%s)", strings.Join(metrics, ", ")))
+	}
+	for _, stage := range p.Stages {
+		if stage.Status == "RUNNING" {
+			parts = append(parts, "["+containerOperationProgressString(stage)+"]")
+		}
+	}
+
+	return strings.Join(parts, " ")
+}
+
 func (w *ContainerOperationWaiter) OpName() string {
 	if w == nil || w.Op == nil {
 		return "<nil>"
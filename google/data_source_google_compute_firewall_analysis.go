@@ -0,0 +1,317 @@
+package google
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"google.golang.org/api/compute/v1"
+)
+
+func dataSourceGoogleComputeFirewallAnalysis() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceGoogleComputeFirewallAnalysisRead,
+
+		Schema: map[string]*schema.Schema{
+			"network": {
+				Type:             schema.TypeString,
+				Required:         true,
+				DiffSuppressFunc: compareSelfLinkOrResourceName,
+				Description:      `The name or self link of the network whose firewall rules are analyzed.`,
+			},
+			"project": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+			"firewall_policies": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: `The hierarchical firewall policies applying to the network, in evaluation order: the policy of the organization first, then the policies of the folders from the top down.`,
+			},
+			"query": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Description: `Connections to evaluate the firewall rules against.`,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"direction": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							Default:      "INGRESS",
+							ValidateFunc: validation.StringInSlice([]string{"INGRESS", "EGRESS"}, false),
+						},
+						"protocol": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"port": {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							ValidateFunc: validation.IntBetween(0, 65535),
+						},
+						"source_ip": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							ValidateFunc: validation.IsIPAddress,
+						},
+						"destination_ip": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							ValidateFunc: validation.IsIPAddress,
+						},
+						"source_tags": {
+							Type:     schema.TypeList,
+							Optional: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+						"source_service_account": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"target_tags": {
+							Type:     schema.TypeList,
+							Optional: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+						"target_service_account": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+					},
+				},
+			},
+			"rules": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"firewall_policy": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"priority": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"direction": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"action": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"disabled": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+						"shadowed_by": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+			"shadowed_rules": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"conflicts": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"winning_rule": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"overridden_rule": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+			"verdicts": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"action": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"rule": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceGoogleComputeFirewallAnalysisRead(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*Config)
+	userAgent, err := generateUserAgentString(d, config.userAgent)
+	if err != nil {
+		return err
+	}
+
+	network, err := ParseNetworkFieldValue(d.Get("network").(string), d, config)
+	if err != nil {
+		return err
+	}
+	if err := d.Set("project", network.Project); err != nil {
+		return fmt.Errorf("Error setting project: %s", err)
+	}
+	computeClient := config.NewComputeClient(userAgent)
+
+	var rules []*firewallAnalysisRule
+	policies := convertStringArr(d.Get("firewall_policies").([]interface{}))
+	for i, p := range policies {
+		name := GetResourceNameFromSelfLink(p)
+		policy, err := computeClient.FirewallPolicies.Get(name).Do()
+		if err != nil {
+			return fmt.Errorf("Error reading firewall policy %q: %s", p, err)
+		}
+		for _, rule := range policy.Rules {
+			if !firewallPolicyRuleAppliesToNetwork(rule, network.RelativeLink()) {
+				continue
+			}
+			id := fmt.Sprintf("locations/global/firewallPolicies/%s/rules/%d", name, rule.Priority)
+			r, err := newFirewallAnalysisRuleFromPolicyRule(id, name, rule, i)
+			if err != nil {
+				return err
+			}
+			rules = append(rules, r)
+		}
+	}
+
+	filter := fmt.Sprintf("network eq .*/projects/%s/global/networks/%s$", network.Project, network.Name)
+	err = computeClient.Firewalls.List(network.Project).Filter(filter).Pages(config.context, func(list *compute.FirewallList) error {
+		for _, fw := range list.Items {
+			id := fmt.Sprintf("projects/%s/global/firewalls/%s", network.Project, fw.Name)
+			r, err := newFirewallAnalysisRuleFromFirewall(id, fw, len(policies))
+			if err != nil {
+				return err
+			}
+			rules = append(rules, r)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("Error listing firewall rules of network %s: %s", network.RelativeLink(), err)
+	}
+
+	analysis := analyzeFirewallRules(rules)
+	if err := flattenFirewallAnalysis(d, analysis); err != nil {
+		return err
+	}
+
+	verdicts, err := evaluateFirewallAnalysisQueries(analysis, d.Get("query").([]interface{}))
+	if err != nil {
+		return err
+	}
+	if err := d.Set("verdicts", verdicts); err != nil {
+		return fmt.Errorf("Error setting verdicts: %s", err)
+	}
+
+	d.SetId(network.RelativeLink())
+	return nil
+}
+
+// firewallPolicyRuleAppliesToNetwork returns whether a firewall policy rule
+// applies to the network with the given relative link.
+func firewallPolicyRuleAppliesToNetwork(rule *compute.FirewallPolicyRule, network string) bool {
+	if len(rule.TargetResources) == 0 {
+		return true
+	}
+	for _, target := range rule.TargetResources {
+		if compareSelfLinkRelativePaths("", target, network, nil) {
+			return true
+		}
+	}
+	return false
+}
+
+func flattenFirewallAnalysis(d *schema.ResourceData, analysis *firewallAnalysis) error {
+	rules := make([]map[string]interface{}, 0, len(analysis.rules))
+	shadowed := make([]string, 0)
+	for _, r := range analysis.rules {
+		rules = append(rules, map[string]interface{}{
+			"id":              r.id,
+			"name":            r.name,
+			"firewall_policy": r.firewallPolicy,
+			"priority":        int(r.priority),
+			"direction":       r.direction,
+			"action":          r.action,
+			"disabled":        r.disabled,
+			"shadowed_by":     analysis.shadowedBy[r.id],
+		})
+		if _, ok := analysis.shadowedBy[r.id]; ok {
+			shadowed = append(shadowed, r.id)
+		}
+	}
+	if err := d.Set("rules", rules); err != nil {
+		return fmt.Errorf("Error setting rules: %s", err)
+	}
+	if err := d.Set("shadowed_rules", shadowed); err != nil {
+		return fmt.Errorf("Error setting shadowed_rules: %s", err)
+	}
+
+	conflicts := make([]map[string]interface{}, 0, len(analysis.conflicts))
+	for _, c := range analysis.conflicts {
+		conflicts = append(conflicts, map[string]interface{}{
+			"winning_rule":    c.winningRule,
+			"overridden_rule": c.overriddenRule,
+		})
+	}
+	if err := d.Set("conflicts", conflicts); err != nil {
+		return fmt.Errorf("Error setting conflicts: %s", err)
+	}
+	return nil
+}
+
+func evaluateFirewallAnalysisQueries(analysis *firewallAnalysis, queries []interface{}) ([]map[string]interface{}, error) {
+	verdicts := make([]map[string]interface{}, 0, len(queries))
+	for i, raw := range queries {
+		q := raw.(map[string]interface{})
+		query := firewallAnalysisQuery{
+			direction:            q["direction"].(string),
+			protocol:             q["protocol"].(string),
+			port:                 q["port"].(int),
+			sourceTags:           convertStringArr(q["source_tags"].([]interface{})),
+			sourceServiceAccount: q["source_service_account"].(string),
+			targetTags:           convertStringArr(q["target_tags"].([]interface{})),
+			targetServiceAccount: q["target_service_account"].(string),
+		}
+		for field, ip := range map[string]*net.IP{"source_ip": &query.sourceIp, "destination_ip": &query.destinationIp} {
+			if v := q[field].(string); v != "" {
+				if *ip = net.ParseIP(v); *ip == nil {
+					return nil, fmt.Errorf("query.%d.%s: invalid IP address %q", i, field, v)
+				}
+			}
+		}
+
+		action, rule := analysis.verdict(query)
+		verdicts = append(verdicts, map[string]interface{}{
+			"action": action,
+			"rule":   rule,
+		})
+	}
+	return verdicts, nil
+}
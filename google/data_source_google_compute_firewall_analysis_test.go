@@ -0,0 +1,101 @@
+package google
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDataSourceGoogleComputeFirewallAnalysis(t *testing.T) {
+	t.Parallel()
+
+	context := map[string]interface{}{
+		"random_suffix": randString(t, 10),
+	}
+
+	vcrTest(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceGoogleComputeFirewallAnalysis(context),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.google_compute_firewall_analysis.analysis", "rules.#", "3"),
+					resource.TestCheckResourceAttrPair("data.google_compute_firewall_analysis.analysis", "shadowed_rules.0", "google_compute_firewall.allow_ssh_bastion", "id"),
+					resource.TestCheckResourceAttrPair("data.google_compute_firewall_analysis.analysis", "conflicts.0.winning_rule", "google_compute_firewall.deny_web", "id"),
+					resource.TestCheckResourceAttrPair("data.google_compute_firewall_analysis.analysis", "conflicts.0.overridden_rule", "google_compute_firewall.allow_ssh", "id"),
+					resource.TestCheckResourceAttr("data.google_compute_firewall_analysis.analysis", "verdicts.0.action", "allow"),
+					resource.TestCheckResourceAttrPair("data.google_compute_firewall_analysis.analysis", "verdicts.0.rule", "google_compute_firewall.allow_ssh", "id"),
+					resource.TestCheckResourceAttr("data.google_compute_firewall_analysis.analysis", "verdicts.1.action", "deny"),
+					resource.TestCheckResourceAttr("data.google_compute_firewall_analysis.analysis", "verdicts.1.rule", ""),
+				),
+			},
+		},
+	})
+}
+
+func testAccDataSourceGoogleComputeFirewallAnalysis(context map[string]interface{}) string {
+	return Nprintf(`
+resource "google_compute_network" "network" {
+  name                    = "tf-test-network%{random_suffix}"
+  auto_create_subnetworks = false
+}
+
+resource "google_compute_firewall" "allow_ssh" {
+  name          = "tf-test-allow-ssh%{random_suffix}"
+  network       = google_compute_network.network.name
+  source_ranges = ["10.0.0.0/8"]
+
+  allow {
+    protocol = "tcp"
+    ports    = ["22", "80"]
+  }
+}
+
+resource "google_compute_firewall" "allow_ssh_bastion" {
+  name          = "tf-test-allow-ssh-bastion%{random_suffix}"
+  network       = google_compute_network.network.name
+  source_ranges = ["10.1.0.0/16"]
+  target_tags   = ["bastion"]
+
+  allow {
+    protocol = "tcp"
+    ports    = ["22"]
+  }
+}
+
+resource "google_compute_firewall" "deny_web" {
+  name          = "tf-test-deny-web%{random_suffix}"
+  network       = google_compute_network.network.name
+  priority      = 500
+  source_ranges = ["0.0.0.0/0"]
+
+  deny {
+    protocol = "tcp"
+    ports    = ["80"]
+  }
+}
+
+data "google_compute_firewall_analysis" "analysis" {
+  network = google_compute_network.network.self_link
+
+  query {
+    protocol  = "tcp"
+    port      = 22
+    source_ip = "10.2.3.4"
+  }
+
+  query {
+    protocol  = "udp"
+    port      = 53
+    source_ip = "10.2.3.4"
+  }
+
+  depends_on = [
+    google_compute_firewall.allow_ssh,
+    google_compute_firewall.allow_ssh_bastion,
+    google_compute_firewall.deny_web,
+  ]
+}
+`, context)
+}
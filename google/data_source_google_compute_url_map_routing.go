@@ -0,0 +1,264 @@
+package google
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// urlMapRoutingInlineFields are the fields of google_compute_url_map that
+// can be evaluated inline instead of reading an existing URL map.
+var urlMapRoutingInlineFields = []string{"default_service", "default_route_action", "default_url_redirect", "host_rule", "path_matcher"}
+
+func dataSourceGoogleComputeUrlMapRouting() *schema.Resource {
+	dsSchema := map[string]*schema.Schema{
+		"url_map": {
+			Type:          schema.TypeString,
+			Optional:      true,
+			ConflictsWith: urlMapRoutingInlineFields,
+			Description:   `The self link, id or name of an existing global URL map to evaluate the requests against.`,
+		},
+		"project": {
+			Type:     schema.TypeString,
+			Optional: true,
+			Computed: true,
+		},
+		"request": {
+			Type:        schema.TypeList,
+			Required:    true,
+			Description: `The requests to route.`,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"host": {
+						Type:     schema.TypeString,
+						Required: true,
+					},
+					"path": {
+						Type:        schema.TypeString,
+						Required:    true,
+						Description: `The path of the request, which may include a query string.`,
+					},
+					"scheme": {
+						Type:         schema.TypeString,
+						Optional:     true,
+						Default:      "http",
+						ValidateFunc: validation.StringInSlice([]string{"http", "https"}, false),
+					},
+					"headers": {
+						Type:     schema.TypeMap,
+						Optional: true,
+						Elem:     &schema.Schema{Type: schema.TypeString},
+					},
+				},
+			},
+		},
+		"results": {
+			Type:        schema.TypeList,
+			Computed:    true,
+			Description: `The routing decision for each request, in the order of the requests.`,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"host": {
+						Type:     schema.TypeString,
+						Computed: true,
+					},
+					"path": {
+						Type:     schema.TypeString,
+						Computed: true,
+					},
+					"path_matcher": {
+						Type:     schema.TypeString,
+						Computed: true,
+					},
+					"matched_rule": {
+						Type:     schema.TypeString,
+						Computed: true,
+					},
+					"service": {
+						Type:     schema.TypeString,
+						Computed: true,
+					},
+					"weighted_backend_services": {
+						Type:     schema.TypeList,
+						Computed: true,
+						Elem: &schema.Resource{
+							Schema: map[string]*schema.Schema{
+								"backend_service": {
+									Type:     schema.TypeString,
+									Computed: true,
+								},
+								"weight": {
+									Type:     schema.TypeInt,
+									Computed: true,
+								},
+							},
+						},
+					},
+					"output_host": {
+						Type:     schema.TypeString,
+						Computed: true,
+					},
+					"output_path": {
+						Type:     schema.TypeString,
+						Computed: true,
+					},
+					"redirect_url": {
+						Type:     schema.TypeString,
+						Computed: true,
+					},
+					"redirect_response_code": {
+						Type:     schema.TypeInt,
+						Computed: true,
+					},
+				},
+			},
+		},
+	}
+
+	// The inline URL map uses the schema of google_compute_url_map, without the
+	// top-level constraints that would make url_map unusable.
+	urlMapSchema := resourceComputeUrlMap().Schema
+	for _, k := range urlMapRoutingInlineFields {
+		s := *urlMapSchema[k]
+		s.ExactlyOneOf = nil
+		s.ConflictsWith = []string{"url_map"}
+		dsSchema[k] = &s
+	}
+
+	return &schema.Resource{
+		Read:   dataSourceGoogleComputeUrlMapRoutingRead,
+		Schema: dsSchema,
+	}
+}
+
+func dataSourceGoogleComputeUrlMapRoutingRead(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*Config)
+	userAgent, err := generateUserAgentString(d, config.userAgent)
+	if err != nil {
+		return err
+	}
+
+	project, err := getProject(d, config)
+	if err != nil {
+		return err
+	}
+	if err := d.Set("project", project); err != nil {
+		return fmt.Errorf("Error setting project: %s", err)
+	}
+
+	var urlMap map[string]interface{}
+	if v, ok := d.GetOk("url_map"); ok {
+		url, err := urlMapRoutingUrl(d, config, v.(string))
+		if err != nil {
+			return err
+		}
+		urlMap, err = sendRequest(config, "GET", project, url, userAgent, nil)
+		if err != nil {
+			return fmt.Errorf("Error reading URL map %q: %s", v.(string), err)
+		}
+	} else {
+		urlMap, err = expandUrlMapRoutingInline(d, config)
+		if err != nil {
+			return err
+		}
+	}
+
+	requests := d.Get("request").([]interface{})
+	results := make([]map[string]interface{}, 0, len(requests))
+	for _, raw := range requests {
+		r := raw.(map[string]interface{})
+		headers := make(map[string]string)
+		for k, v := range r["headers"].(map[string]interface{}) {
+			headers[k] = v.(string)
+		}
+		req := newUrlMapRequest(r["scheme"].(string), r["host"].(string), r["path"].(string), headers)
+
+		route, err := evaluateUrlMap(urlMap, req)
+		if err != nil {
+			return fmt.Errorf("Error routing request for %s%s: %s", r["host"], r["path"], err)
+		}
+
+		weighted := make([]map[string]interface{}, 0, len(route.weightedBackendServices))
+		for _, wbs := range route.weightedBackendServices {
+			weighted = append(weighted, map[string]interface{}{
+				"backend_service": wbs.backendService,
+				"weight":          wbs.weight,
+			})
+		}
+		results = append(results, map[string]interface{}{
+			"host":                      r["host"],
+			"path":                      r["path"],
+			"path_matcher":              route.pathMatcher,
+			"matched_rule":              route.matchedRule,
+			"service":                   route.service,
+			"weighted_backend_services": weighted,
+			"output_host":               route.host,
+			"output_path":               route.path,
+			"redirect_url":              route.redirectUrl,
+			"redirect_response_code":    route.redirectResponseCode,
+		})
+	}
+
+	if err := d.Set("results", results); err != nil {
+		return fmt.Errorf("Error setting results: %s", err)
+	}
+
+	id, err := json.Marshal([]interface{}{urlMap, requests})
+	if err != nil {
+		return err
+	}
+	d.SetId(strconv.Itoa(hashcode(string(id))))
+	return nil
+}
+
+// urlMapRoutingUrl returns the API URL of a URL map given its self link, its
+// id or the name of a global URL map.
+func urlMapRoutingUrl(d *schema.ResourceData, config *Config, v string) (string, error) {
+	if strings.HasPrefix(v, "https://") {
+		return v, nil
+	}
+	if strings.HasPrefix(v, "projects/") {
+		return config.ComputeBasePath + v, nil
+	}
+	f, err := parseGlobalFieldValue("urlMaps", v, "project", d, config, false)
+	if err != nil {
+		return "", err
+	}
+	return config.ComputeBasePath + f.RelativeLink(), nil
+}
+
+// expandUrlMapRoutingInline returns the API representation of the inline URL
+// map, using the expanders of google_compute_url_map.
+func expandUrlMapRoutingInline(d *schema.ResourceData, config *Config) (map[string]interface{}, error) {
+	if _, ok := d.GetOk("default_service"); !ok {
+		if _, ok := d.GetOk("default_url_redirect"); !ok {
+			if _, ok := d.GetOk("default_route_action"); !ok {
+				return nil, fmt.Errorf("one of url_map, default_service, default_url_redirect or default_route_action must be set")
+			}
+		}
+	}
+
+	urlMap := make(map[string]interface{})
+	expanders := map[string]struct {
+		field  string
+		expand func(interface{}, TerraformResourceData, *Config) (interface{}, error)
+	}{
+		"defaultService":     {"default_service", expandComputeUrlMapDefaultService},
+		"defaultRouteAction": {"default_route_action", expandComputeUrlMapDefaultRouteAction},
+		"defaultUrlRedirect": {"default_url_redirect", expandComputeUrlMapDefaultUrlRedirect},
+		"hostRules":          {"host_rule", expandComputeUrlMapHostRule},
+		"pathMatchers":       {"path_matcher", expandComputeUrlMapPathMatcher},
+	}
+	for key, e := range expanders {
+		v, err := e.expand(d.Get(e.field), d, config)
+		if err != nil {
+			return nil, err
+		}
+		urlMap[key] = v
+	}
+	return urlMap, nil
+}
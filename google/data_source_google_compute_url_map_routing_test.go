@@ -0,0 +1,154 @@
+package google
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestDataSourceGoogleComputeUrlMapRouting_inline(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, dataSourceGoogleComputeUrlMapRouting().Schema, map[string]interface{}{
+		"default_service": "default",
+		"host_rule": []interface{}{
+			map[string]interface{}{
+				"hosts":        []interface{}{"example.com"},
+				"path_matcher": "pm",
+			},
+		},
+		"path_matcher": []interface{}{
+			map[string]interface{}{
+				"name":            "pm",
+				"default_service": "projects/my-project/global/backendServices/home",
+				"route_rules": []interface{}{
+					map[string]interface{}{
+						"priority": 0,
+						"match_rules": []interface{}{
+							map[string]interface{}{"prefix_match": "/api/"},
+						},
+						"service": "api",
+						"route_action": []interface{}{
+							map[string]interface{}{
+								"url_rewrite": []interface{}{
+									map[string]interface{}{"path_prefix_rewrite": "/"},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		"request": []interface{}{
+			map[string]interface{}{"host": "example.com", "path": "/api/users"},
+			map[string]interface{}{"host": "example.com", "path": "/"},
+			map[string]interface{}{"host": "other.com", "path": "/"},
+		},
+	})
+
+	if err := dataSourceGoogleComputeUrlMapRoutingRead(d, &Config{Project: "my-project"}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expected := []struct {
+		matchedRule string
+		service     string
+		outputPath  string
+	}{
+		{"route_rule:0", "projects/my-project/global/backendServices/api", "/users"},
+		{"default", "projects/my-project/global/backendServices/home", "/"},
+		{"default", "projects/my-project/global/backendServices/default", "/"},
+	}
+	for i, e := range expected {
+		prefix := fmt.Sprintf("results.%d", i)
+		if got := d.Get(prefix + ".matched_rule").(string); got != e.matchedRule {
+			t.Errorf("request %d: expected matched_rule %q, got %q", i, e.matchedRule, got)
+		}
+		if got := d.Get(prefix + ".service").(string); got != e.service {
+			t.Errorf("request %d: expected service %q, got %q", i, e.service, got)
+		}
+		if got := d.Get(prefix + ".output_path").(string); got != e.outputPath {
+			t.Errorf("request %d: expected output_path %q, got %q", i, e.outputPath, got)
+		}
+	}
+}
+
+func TestAccDataSourceGoogleComputeUrlMapRouting_urlMap(t *testing.T) {
+	t.Parallel()
+
+	name := fmt.Sprintf("urlmap-test-%s", randString(t, 10))
+	vcrTest(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceGoogleComputeUrlMapRouting_urlMap(name),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair("data.google_compute_url_map_routing.routing", "results.0.service", "google_compute_backend_service.foobar", "id"),
+					resource.TestCheckResourceAttr("data.google_compute_url_map_routing.routing", "results.0.path_matcher", "boop"),
+					resource.TestCheckResourceAttr("data.google_compute_url_map_routing.routing", "results.0.matched_rule", "path_rule:/static/*"),
+					resource.TestCheckResourceAttr("data.google_compute_url_map_routing.routing", "results.0.output_path", "/assets/logo.png"),
+					resource.TestCheckResourceAttr("data.google_compute_url_map_routing.routing", "results.1.redirect_url", "https://mysite.com/"),
+				),
+			},
+		},
+	})
+}
+
+func testAccDataSourceGoogleComputeUrlMapRouting_urlMap(name string) string {
+	return fmt.Sprintf(`
+resource "google_compute_backend_service" "foobar" {
+  name          = "%s"
+  health_checks = [google_compute_http_health_check.zero.self_link]
+}
+
+resource "google_compute_http_health_check" "zero" {
+  name               = "%s"
+  request_path       = "/"
+  check_interval_sec = 1
+  timeout_sec        = 1
+}
+
+resource "google_compute_url_map" "foobar" {
+  name            = "%s"
+  default_service = google_compute_backend_service.foobar.self_link
+
+  host_rule {
+    hosts        = ["mysite.com"]
+    path_matcher = "boop"
+  }
+
+  path_matcher {
+    name = "boop"
+    default_url_redirect {
+      https_redirect = true
+      strip_query    = false
+    }
+
+    path_rule {
+      paths   = ["/static/*"]
+      service = google_compute_backend_service.foobar.self_link
+      route_action {
+        url_rewrite {
+          path_prefix_rewrite = "/assets/"
+        }
+      }
+    }
+  }
+}
+
+data "google_compute_url_map_routing" "routing" {
+  url_map = google_compute_url_map.foobar.id
+
+  request {
+    host = "mysite.com"
+    path = "/static/logo.png"
+  }
+
+  request {
+    host = "mysite.com"
+    path = "/"
+  }
+}
+`, name, name, name)
+}
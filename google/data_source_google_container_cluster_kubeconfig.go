@@ -0,0 +1,312 @@
+package google
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const containerClusterKubeconfigExecApiVersion = "client.authentication.k8s.io/v1beta1"
+
+// containerClusterKubeconfigExecCommand is the credential plugin GKE provides.
+const containerClusterKubeconfigExecCommand = "gke-gcloud-auth-plugin"
+
+func dataSourceGoogleContainerClusterKubeconfig() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceGoogleContainerClusterKubeconfigRead,
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: `The name of the cluster.`,
+			},
+
+			"location": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: `The location (region or zone) of the cluster.`,
+			},
+
+			"project": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: `The project in which the cluster belongs. If it is not provided, the provider project is used.`,
+			},
+
+			"use_private_endpoint": {
+				Type:          schema.TypeBool,
+				Optional:      true,
+				ConflictsWith: []string{"use_dns_endpoint"},
+				Description:   `Whether to address the cluster through its private endpoint. The cluster must be a private cluster.`,
+			},
+
+			"use_dns_endpoint": {
+				Type:          schema.TypeBool,
+				Optional:      true,
+				ConflictsWith: []string{"use_private_endpoint"},
+				Description:   `Whether to address the cluster through its DNS-based control plane endpoint. The endpoint is served with a publicly trusted certificate, so no cluster CA certificate is written to the kubeconfig.`,
+			},
+
+			"exec": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				MaxItems:    1,
+				Description: `Authenticate with an exec credential plugin instead of a static access token.`,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"command": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Default:     containerClusterKubeconfigExecCommand,
+							Description: `The command to execute to obtain credentials.`,
+						},
+						"args": {
+							Type:        schema.TypeList,
+							Optional:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+							Description: `Arguments passed to the command.`,
+						},
+						"env": {
+							Type:        schema.TypeMap,
+							Optional:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+							Description: `Environment variables set when executing the command.`,
+						},
+						"api_version": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Default:     containerClusterKubeconfigExecApiVersion,
+							Description: `The API version of the ExecCredential returned by the command.`,
+						},
+					},
+				},
+			},
+
+			"context_name": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: `The name of the cluster, context and user entries in the kubeconfig.`,
+			},
+
+			"host": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: `The URL of the selected Kubernetes API server endpoint.`,
+			},
+
+			"cluster_ca_certificate": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: `Base64 encoded public certificate that is the root of trust for the cluster. Empty when use_dns_endpoint is set.`,
+			},
+
+			"token": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Sensitive:   true,
+				Description: `The OAuth2 access token written to the kubeconfig. Empty when exec is set.`,
+			},
+
+			"raw_config": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Sensitive:   true,
+				Description: `The kubeconfig in YAML format.`,
+			},
+		},
+	}
+}
+
+func dataSourceGoogleContainerClusterKubeconfigRead(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*Config)
+	userAgent, err := generateUserAgentString(d, config.userAgent)
+	if err != nil {
+		return err
+	}
+
+	clusterName := d.Get("name").(string)
+
+	location, err := getLocation(d, config)
+	if err != nil {
+		return err
+	}
+
+	project, err := getProject(d, config)
+	if err != nil {
+		return err
+	}
+
+	// The cluster is read without the container client as it doesn't know
+	// about DNS-based control plane endpoints yet.
+	url := config.ContainerBasePath + containerClusterFullName(project, location, clusterName)
+	cluster, err := sendRequest(config, "GET", project, url, userAgent, nil)
+	if err != nil {
+		return handleNotFoundError(err, d, fmt.Sprintf("Container Cluster %q", clusterName))
+	}
+
+	kubeconfig := &containerClusterKubeconfig{
+		Name: fmt.Sprintf("gke_%s_%s_%s", project, location, clusterName),
+	}
+
+	switch {
+	case d.Get("use_dns_endpoint").(bool):
+		endpoint := containerClusterKubeconfigLookup(cluster, "controlPlaneEndpointsConfig", "dnsEndpointConfig", "endpoint")
+		if endpoint == "" {
+			return fmt.Errorf("Cluster %q has no DNS-based control plane endpoint", clusterName)
+		}
+		kubeconfig.Server = "https://" + endpoint
+	case d.Get("use_private_endpoint").(bool):
+		endpoint := containerClusterKubeconfigLookup(cluster, "privateClusterConfig", "privateEndpoint")
+		if endpoint == "" {
+			return fmt.Errorf("Cluster %q has no private endpoint", clusterName)
+		}
+		kubeconfig.Server = "https://" + endpoint
+		kubeconfig.CertificateAuthorityData = containerClusterKubeconfigLookup(cluster, "masterAuth", "clusterCaCertificate")
+	default:
+		endpoint := containerClusterKubeconfigLookup(cluster, "endpoint")
+		if endpoint == "" {
+			return fmt.Errorf("Cluster %q has no endpoint", clusterName)
+		}
+		kubeconfig.Server = "https://" + endpoint
+		kubeconfig.CertificateAuthorityData = containerClusterKubeconfigLookup(cluster, "masterAuth", "clusterCaCertificate")
+	}
+
+	if v, ok := d.GetOk("exec"); ok && len(v.([]interface{})) > 0 && v.([]interface{})[0] != nil {
+		exec := v.([]interface{})[0].(map[string]interface{})
+		kubeconfig.Exec = &containerClusterKubeconfigExec{
+			ApiVersion: exec["api_version"].(string),
+			Command:    exec["command"].(string),
+			Args:       convertStringArr(exec["args"].([]interface{})),
+			Env:        convertStringMap(exec["env"].(map[string]interface{})),
+		}
+	} else {
+		token, err := config.tokenSource.Token()
+		if err != nil {
+			return err
+		}
+		kubeconfig.Token = token.AccessToken
+	}
+
+	d.SetId(containerClusterFullName(project, location, clusterName))
+	if err := d.Set("project", project); err != nil {
+		return fmt.Errorf("Error setting project: %s", err)
+	}
+	if err := d.Set("location", location); err != nil {
+		return fmt.Errorf("Error setting location: %s", err)
+	}
+	if err := d.Set("context_name", kubeconfig.Name); err != nil {
+		return fmt.Errorf("Error setting context_name: %s", err)
+	}
+	if err := d.Set("host", kubeconfig.Server); err != nil {
+		return fmt.Errorf("Error setting host: %s", err)
+	}
+	if err := d.Set("cluster_ca_certificate", kubeconfig.CertificateAuthorityData); err != nil {
+		return fmt.Errorf("Error setting cluster_ca_certificate: %s", err)
+	}
+	if err := d.Set("token", kubeconfig.Token); err != nil {
+		return fmt.Errorf("Error setting token: %s", err)
+	}
+	if err := d.Set("raw_config", kubeconfig.String()); err != nil {
+		return fmt.Errorf("Error setting raw_config: %s", err)
+	}
+
+	return nil
+}
+
+// containerClusterKubeconfigLookup returns the string found by following keys
+// through nested objects of an API response, or "" if any of them is missing.
+func containerClusterKubeconfigLookup(obj map[string]interface{}, keys ...string) string {
+	var v interface{} = obj
+	for _, k := range keys {
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return ""
+		}
+		v = m[k]
+	}
+	s, _ := v.(string)
+	return s
+}
+
+type containerClusterKubeconfigExec struct {
+	ApiVersion string
+	Command    string
+	Args       []string
+	Env        map[string]string
+}
+
+// containerClusterKubeconfig is a kubeconfig with a single cluster, user and
+// context, all sharing the same name.
+type containerClusterKubeconfig struct {
+	Name                     string
+	Server                   string
+	CertificateAuthorityData string
+	Token                    string
+	Exec                     *containerClusterKubeconfigExec
+}
+
+// String renders the kubeconfig as YAML. Scalars are written as JSON strings,
+// which are valid double-quoted YAML scalars.
+func (k *containerClusterKubeconfig) String() string {
+	q := func(s string) string {
+		b, _ := json.Marshal(s)
+		return string(b)
+	}
+
+	var b strings.Builder
+	b.WriteString("apiVersion: v1\n")
+	b.WriteString("kind: Config\n")
+	fmt.Fprintf(&b, "current-context: %s\n", q(k.Name))
+	b.WriteString("clusters:\n")
+	fmt.Fprintf(&b, "- name: %s\n", q(k.Name))
+	b.WriteString("  cluster:\n")
+	fmt.Fprintf(&b, "    server: %s\n", q(k.Server))
+	if k.CertificateAuthorityData != "" {
+		fmt.Fprintf(&b, "    certificate-authority-data: %s\n", q(k.CertificateAuthorityData))
+	}
+	b.WriteString("contexts:\n")
+	fmt.Fprintf(&b, "- name: %s\n", q(k.Name))
+	b.WriteString("  context:\n")
+	fmt.Fprintf(&b, "    cluster: %s\n", q(k.Name))
+	fmt.Fprintf(&b, "    user: %s\n", q(k.Name))
+	b.WriteString("users:\n")
+	fmt.Fprintf(&b, "- name: %s\n", q(k.Name))
+	b.WriteString("  user:\n")
+	if k.Exec != nil {
+		b.WriteString("    exec:\n")
+		fmt.Fprintf(&b, "      apiVersion: %s\n", q(k.Exec.ApiVersion))
+		fmt.Fprintf(&b, "      command: %s\n", q(k.Exec.Command))
+		if len(k.Exec.Args) > 0 {
+			b.WriteString("      args:\n")
+			for _, arg := range k.Exec.Args {
+				fmt.Fprintf(&b, "      - %s\n", q(arg))
+			}
+		}
+		if len(k.Exec.Env) > 0 {
+			names := make([]string, 0, len(k.Exec.Env))
+			for name := range k.Exec.Env {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			b.WriteString("      env:\n")
+			for _, name := range names {
+				fmt.Fprintf(&b, "      - name: %s\n", q(name))
+				fmt.Fprintf(&b, "        value: %s\n", q(k.Exec.Env[name]))
+			}
+		}
+		if k.Exec.Command == containerClusterKubeconfigExecCommand {
+			b.WriteString("      installHint: \"Install gke-gcloud-auth-plugin for use with kubectl by following https://cloud.google.com/kubernetes-engine/docs/how-to/cluster-access-for-kubectl#install_plugin\"\n")
+			b.WriteString("      provideClusterInfo: true\n")
+		}
+		b.WriteString("      interactiveMode: \"Never\"\n")
+	} else {
+		fmt.Fprintf(&b, "    token: %s\n", q(k.Token))
+	}
+
+	return b.String()
+}
@@ -0,0 +1,164 @@
+package google
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestContainerClusterKubeconfig_String(t *testing.T) {
+	cases := map[string]struct {
+		Kubeconfig containerClusterKubeconfig
+		Expected   string
+	}{
+		"token": {
+			Kubeconfig: containerClusterKubeconfig{
+				Name:                     "gke_p_us-central1_c",
+				Server:                   "https://10.0.0.2",
+				CertificateAuthorityData: "Q0E=",
+				Token:                    "ya29.token",
+			},
+			Expected: `apiVersion: v1
+kind: Config
+current-context: "gke_p_us-central1_c"
+clusters:
+- name: "gke_p_us-central1_c"
+  cluster:
+    server: "https://10.0.0.2"
+    certificate-authority-data: "Q0E="
+contexts:
+- name: "gke_p_us-central1_c"
+  context:
+    cluster: "gke_p_us-central1_c"
+    user: "gke_p_us-central1_c"
+users:
+- name: "gke_p_us-central1_c"
+  user:
+    token: "ya29.token"
+`,
+		},
+		"exec without certificate": {
+			Kubeconfig: containerClusterKubeconfig{
+				Name:   "gke_p_us-central1_c",
+				Server: "https://gke-1234.us-central1.gke.goog",
+				Exec: &containerClusterKubeconfigExec{
+					ApiVersion: containerClusterKubeconfigExecApiVersion,
+					Command:    "gke-gcloud-auth-plugin",
+					Args:       []string{"--use_application_default_credentials"},
+					Env:        map[string]string{"B": "2", "A": "1"},
+				},
+			},
+			Expected: `apiVersion: v1
+kind: Config
+current-context: "gke_p_us-central1_c"
+clusters:
+- name: "gke_p_us-central1_c"
+  cluster:
+    server: "https://gke-1234.us-central1.gke.goog"
+contexts:
+- name: "gke_p_us-central1_c"
+  context:
+    cluster: "gke_p_us-central1_c"
+    user: "gke_p_us-central1_c"
+users:
+- name: "gke_p_us-central1_c"
+  user:
+    exec:
+      apiVersion: "client.authentication.k8s.io/v1beta1"
+      command: "gke-gcloud-auth-plugin"
+      args:
+      - "--use_application_default_credentials"
+      env:
+      - name: "A"
+        value: "1"
+      - name: "B"
+        value: "2"
+      installHint: "Install gke-gcloud-auth-plugin for use with kubectl by following https://cloud.google.com/kubernetes-engine/docs/how-to/cluster-access-for-kubectl#install_plugin"
+      provideClusterInfo: true
+      interactiveMode: "Never"
+`,
+		},
+		"custom exec command": {
+			Kubeconfig: containerClusterKubeconfig{
+				Name:                     "gke_p_us-central1_c",
+				Server:                   "https://10.0.0.2",
+				CertificateAuthorityData: "Q0E=",
+				Exec: &containerClusterKubeconfigExec{
+					ApiVersion: containerClusterKubeconfigExecApiVersion,
+					Command:    "/usr/local/bin/my-auth-plugin",
+				},
+			},
+			Expected: `apiVersion: v1
+kind: Config
+current-context: "gke_p_us-central1_c"
+clusters:
+- name: "gke_p_us-central1_c"
+  cluster:
+    server: "https://10.0.0.2"
+    certificate-authority-data: "Q0E="
+contexts:
+- name: "gke_p_us-central1_c"
+  context:
+    cluster: "gke_p_us-central1_c"
+    user: "gke_p_us-central1_c"
+users:
+- name: "gke_p_us-central1_c"
+  user:
+    exec:
+      apiVersion: "client.authentication.k8s.io/v1beta1"
+      command: "/usr/local/bin/my-auth-plugin"
+      interactiveMode: "Never"
+`,
+		},
+	}
+
+	for tn, tc := range cases {
+		if got := tc.Kubeconfig.String(); got != tc.Expected {
+			t.Errorf("%s: expected\n%s\ngot\n%s", tn, tc.Expected, got)
+		}
+	}
+}
+
+func TestAccContainerClusterKubeconfigDatasource_basic(t *testing.T) {
+	t.Parallel()
+
+	vcrTest(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccContainerClusterKubeconfigDatasource_basic(randString(t, 10)),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair("data.google_container_cluster_kubeconfig.token", "cluster_ca_certificate", "google_container_cluster.kubes", "master_auth.0.cluster_ca_certificate"),
+					resource.TestCheckResourceAttrSet("data.google_container_cluster_kubeconfig.token", "token"),
+					resource.TestCheckResourceAttr("data.google_container_cluster_kubeconfig.exec", "token", ""),
+					resource.TestMatchResourceAttr("data.google_container_cluster_kubeconfig.exec", "raw_config", regexp.MustCompile("command: \"gke-gcloud-auth-plugin\"")),
+				),
+			},
+		},
+	})
+}
+
+func testAccContainerClusterKubeconfigDatasource_basic(suffix string) string {
+	return fmt.Sprintf(`
+resource "google_container_cluster" "kubes" {
+  name               = "tf-test-cluster-%s"
+  location           = "us-central1-a"
+  initial_node_count = 1
+}
+
+data "google_container_cluster_kubeconfig" "token" {
+  name     = google_container_cluster.kubes.name
+  location = google_container_cluster.kubes.location
+}
+
+data "google_container_cluster_kubeconfig" "exec" {
+  name     = google_container_cluster.kubes.name
+  location = google_container_cluster.kubes.location
+
+  exec {}
+}
+`, suffix)
+}
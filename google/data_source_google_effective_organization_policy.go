@@ -0,0 +1,448 @@
+package google
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"google.golang.org/api/cloudresourcemanager/v1"
+)
+
+func dataSourceGoogleEffectiveOrganizationPolicy() *schema.Resource {
+	policySchema := datasourceSchemaFromResourceSchema(schemaOrganizationPolicy)
+
+	return &schema.Resource{
+		Read: dataSourceGoogleEffectiveOrganizationPolicyRead,
+		Schema: map[string]*schema.Schema{
+			"project": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Computed:      true,
+				ConflictsWith: []string{"folder"},
+				Description:   `The project to compute the effective policy of. If neither project nor folder is provided, the provider project is used.`,
+			},
+			"folder": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"project"},
+				Description:   `The folder to compute the effective policy of, in the form folders/{folder_id} or {folder_id}.`,
+			},
+			"constraint": {
+				Type:             schema.TypeString,
+				Required:         true,
+				DiffSuppressFunc: compareSelfLinkOrResourceName,
+				Description:      `The name of the constraint, for example gcp.resourceLocations.`,
+			},
+			"values": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: `Candidate values to evaluate against the effective policy of a list constraint.`,
+			},
+			"ancestry": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: `The resource and its ancestors, starting with the resource.`,
+			},
+			"constraint_default": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: `The behavior of the constraint when no policy is set, ALLOW or DENY.`,
+			},
+			"enforced": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: `Whether a boolean constraint is enforced.`,
+			},
+			"list_policy": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: `The effective policy of a list constraint.`,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"all_values": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: `ALLOW or DENY if all values are allowed or denied.`,
+						},
+						"allowed_values": {
+							Type:     schema.TypeSet,
+							Computed: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+							Set:      schema.HashString,
+						},
+						"denied_values": {
+							Type:     schema.TypeSet,
+							Computed: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+							Set:      schema.HashString,
+						},
+					},
+				},
+			},
+			"policies": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: `The policies set in the ancestry, starting with the organization.`,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"resource": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"boolean_policy": policySchema["boolean_policy"],
+						"list_policy":    policySchema["list_policy"],
+						"restore_policy": policySchema["restore_policy"],
+					},
+				},
+			},
+			"evaluations": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"value": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"allowed": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+						"result": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+			"allowed": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: `Whether all the candidate values are known to be allowed.`,
+			},
+			"result": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: `ALLOWED if all the candidate values are allowed, DENIED if one of them is denied, or UNKNOWN otherwise.`,
+			},
+		},
+	}
+}
+
+func dataSourceGoogleEffectiveOrganizationPolicyRead(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*Config)
+	userAgent, err := generateUserAgentString(d, config.userAgent)
+	if err != nil {
+		return err
+	}
+
+	var ancestry []string
+	if v, ok := d.GetOk("folder"); ok {
+		ancestry, err = iamEffectivePolicyFolderAncestry(config, userAgent, canonicalFolderId(v.(string)))
+	} else {
+		var project string
+		project, err = getProject(d, config)
+		if err != nil {
+			return err
+		}
+		if err := d.Set("project", project); err != nil {
+			return fmt.Errorf("Error setting project: %s", err)
+		}
+		ancestry, err = iamEffectivePolicyProjectAncestry(config, userAgent, project)
+	}
+	if err != nil {
+		return err
+	}
+
+	constraintName := canonicalOrgPolicyConstraint(d.Get("constraint").(string))
+	constraint, err := orgPolicyAvailableConstraint(config, userAgent, ancestry[0], constraintName)
+	if err != nil {
+		return err
+	}
+
+	// Policies are evaluated from the top of the hierarchy down.
+	var policies []*cloudresourcemanager.OrgPolicy
+	var flattenedPolicies []map[string]interface{}
+	for i := len(ancestry) - 1; i >= 0; i-- {
+		policy, err := orgPolicyForResource(config, userAgent, ancestry[i], constraintName)
+		if err != nil {
+			return err
+		}
+		policies = append(policies, policy)
+		flattenedPolicies = append(flattenedPolicies, map[string]interface{}{
+			"resource":       ancestry[i],
+			"boolean_policy": flattenBooleanOrganizationPolicy(policy.BooleanPolicy),
+			"list_policy":    flattenListOrganizationPolicy(policy.ListPolicy),
+			"restore_policy": flattenRestoreOrganizationPolicy(policy.RestoreDefault),
+		})
+	}
+
+	values := convertStringArr(d.Get("values").([]interface{}))
+	evaluations := make([]map[string]interface{}, 0, len(values))
+	result := orgPolicyValueAllowed
+	if constraint.BooleanConstraint != nil {
+		if len(values) > 0 {
+			return fmt.Errorf("values can only be evaluated against list constraints, %s is a boolean constraint", constraintName)
+		}
+		if err := d.Set("enforced", effectiveBooleanOrgPolicy(constraint.ConstraintDefault, policies)); err != nil {
+			return fmt.Errorf("Error setting enforced: %s", err)
+		}
+		if err := d.Set("list_policy", nil); err != nil {
+			return fmt.Errorf("Error setting list_policy: %s", err)
+		}
+	} else {
+		effective := effectiveListOrgPolicy(constraint.ConstraintDefault, policies)
+		if err := d.Set("list_policy", effective.flatten()); err != nil {
+			return fmt.Errorf("Error setting list_policy: %s", err)
+		}
+		if err := d.Set("enforced", false); err != nil {
+			return fmt.Errorf("Error setting enforced: %s", err)
+		}
+		for _, v := range values {
+			r := effective.evaluate(v)
+			if result != orgPolicyValueDenied && r != orgPolicyValueAllowed {
+				result = r
+			}
+			evaluations = append(evaluations, map[string]interface{}{
+				"value":   v,
+				"allowed": r == orgPolicyValueAllowed,
+				"result":  r,
+			})
+		}
+	}
+
+	if err := d.Set("ancestry", ancestry); err != nil {
+		return fmt.Errorf("Error setting ancestry: %s", err)
+	}
+	if err := d.Set("constraint_default", constraint.ConstraintDefault); err != nil {
+		return fmt.Errorf("Error setting constraint_default: %s", err)
+	}
+	if err := d.Set("policies", flattenedPolicies); err != nil {
+		return fmt.Errorf("Error setting policies: %s", err)
+	}
+	if err := d.Set("evaluations", evaluations); err != nil {
+		return fmt.Errorf("Error setting evaluations: %s", err)
+	}
+	if err := d.Set("allowed", result == orgPolicyValueAllowed); err != nil {
+		return fmt.Errorf("Error setting allowed: %s", err)
+	}
+	if err := d.Set("result", result); err != nil {
+		return fmt.Errorf("Error setting result: %s", err)
+	}
+
+	d.SetId(fmt.Sprintf("%s:%s", ancestry[0], constraintName))
+	return nil
+}
+
+// orgPolicyForResource returns the policy set on a project, folder or
+// organization for a constraint.
+func orgPolicyForResource(config *Config, userAgent, resource, constraint string) (*cloudresourcemanager.OrgPolicy, error) {
+	client := config.NewResourceManagerClient(userAgent)
+	req := &cloudresourcemanager.GetOrgPolicyRequest{Constraint: constraint}
+
+	var policy *cloudresourcemanager.OrgPolicy
+	err := retry(func() (err error) {
+		switch {
+		case strings.HasPrefix(resource, "projects/"):
+			policy, err = client.Projects.GetOrgPolicy(resource, req).Do()
+		case strings.HasPrefix(resource, "folders/"):
+			policy, err = client.Folders.GetOrgPolicy(resource, req).Do()
+		default:
+			policy, err = client.Organizations.GetOrgPolicy(resource, req).Do()
+		}
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Error reading organization policy %s for %s: %s", constraint, resource, err)
+	}
+	return policy, nil
+}
+
+// orgPolicyAvailableConstraint returns the definition of a constraint
+// available on a project, folder or organization.
+func orgPolicyAvailableConstraint(config *Config, userAgent, resource, constraint string) (*cloudresourcemanager.Constraint, error) {
+	client := config.NewResourceManagerClient(userAgent)
+	req := &cloudresourcemanager.ListAvailableOrgPolicyConstraintsRequest{}
+	for {
+		var resp *cloudresourcemanager.ListAvailableOrgPolicyConstraintsResponse
+		var err error
+		switch {
+		case strings.HasPrefix(resource, "projects/"):
+			resp, err = client.Projects.ListAvailableOrgPolicyConstraints(resource, req).Do()
+		case strings.HasPrefix(resource, "folders/"):
+			resp, err = client.Folders.ListAvailableOrgPolicyConstraints(resource, req).Do()
+		default:
+			resp, err = client.Organizations.ListAvailableOrgPolicyConstraints(resource, req).Do()
+		}
+		if err != nil {
+			return nil, fmt.Errorf("Error listing organization policy constraints available on %s: %s", resource, err)
+		}
+		for _, c := range resp.Constraints {
+			if c.Name == constraint {
+				return c, nil
+			}
+		}
+		if resp.NextPageToken == "" {
+			return nil, fmt.Errorf("constraint %s is not available on %s", constraint, resource)
+		}
+		req.PageToken = resp.NextPageToken
+	}
+}
+
+// effectiveBooleanOrgPolicy returns whether a boolean constraint is enforced
+// given the policies of the ancestry, starting with the organization.
+func effectiveBooleanOrgPolicy(constraintDefault string, policies []*cloudresourcemanager.OrgPolicy) bool {
+	enforced := constraintDefault == "DENY"
+	for _, p := range policies {
+		switch {
+		case p.RestoreDefault != nil:
+			enforced = constraintDefault == "DENY"
+		case p.BooleanPolicy != nil:
+			enforced = p.BooleanPolicy.Enforced
+		}
+	}
+	return enforced
+}
+
+// orgPolicyEffectiveList is the effective policy of a list constraint: a
+// value is allowed if it isn't denied, and if it is allowed or allowed is nil.
+type orgPolicyEffectiveList struct {
+	allowed map[string]bool
+	denied  map[string]bool
+	// whether no policy sets values, so that the constraint default applies
+	isDefault bool
+}
+
+func newOrgPolicyDefaultList(constraintDefault string) orgPolicyEffectiveList {
+	l := orgPolicyEffectiveList{denied: map[string]bool{}, isDefault: true}
+	if constraintDefault == "DENY" {
+		l.allowed = map[string]bool{}
+	}
+	return l
+}
+
+// effectiveListOrgPolicy merges the list policies of the ancestry, starting
+// with the organization. A policy replaces the effective policy of its
+// parent, unless it sets inherit_from_parent, in which case its values are
+// added to the values of the parent.
+func effectiveListOrgPolicy(constraintDefault string, policies []*cloudresourcemanager.OrgPolicy) orgPolicyEffectiveList {
+	effective := newOrgPolicyDefaultList(constraintDefault)
+	for _, p := range policies {
+		if p.RestoreDefault != nil {
+			effective = newOrgPolicyDefaultList(constraintDefault)
+			continue
+		}
+		lp := p.ListPolicy
+		if lp == nil {
+			continue
+		}
+
+		switch lp.AllValues {
+		case "ALLOW":
+			effective = orgPolicyEffectiveList{denied: map[string]bool{}}
+			continue
+		case "DENY":
+			effective = orgPolicyEffectiveList{allowed: map[string]bool{}, denied: map[string]bool{}}
+			continue
+		}
+
+		// The constraint default contributes no values to inherit.
+		if !lp.InheritFromParent || effective.isDefault {
+			effective = orgPolicyEffectiveList{denied: map[string]bool{}}
+			if len(lp.AllowedValues) > 0 {
+				effective.allowed = map[string]bool{}
+			}
+		} else {
+			effective = effective.copy()
+		}
+		if effective.allowed != nil {
+			for _, v := range lp.AllowedValues {
+				effective.allowed[v] = true
+			}
+		}
+		for _, v := range lp.DeniedValues {
+			effective.denied[v] = true
+		}
+	}
+	return effective
+}
+
+func (l orgPolicyEffectiveList) copy() orgPolicyEffectiveList {
+	c := orgPolicyEffectiveList{denied: map[string]bool{}}
+	for v := range l.denied {
+		c.denied[v] = true
+	}
+	if l.allowed != nil {
+		c.allowed = map[string]bool{}
+		for v := range l.allowed {
+			c.allowed[v] = true
+		}
+	}
+	return c
+}
+
+const (
+	orgPolicyValueAllowed = "ALLOWED"
+	orgPolicyValueDenied  = "DENIED"
+	orgPolicyValueUnknown = "UNKNOWN"
+)
+
+// evaluate returns whether a value is ALLOWED or DENIED. Values of the
+// policies prefixed with is: match the same value without the prefix. Value
+// groups (in:) and resource hierarchy subtrees (under:) aren't expanded, so
+// the result is UNKNOWN when one of them could decide it.
+func (l orgPolicyEffectiveList) evaluate(value string) string {
+	value = strings.TrimPrefix(value, "is:")
+	if l.denied[value] || l.denied["is:"+value] {
+		return orgPolicyValueDenied
+	}
+	if l.allowed != nil && !l.allowed[value] && !l.allowed["is:"+value] {
+		if orgPolicyHasValueGroups(l.allowed) {
+			return orgPolicyValueUnknown
+		}
+		return orgPolicyValueDenied
+	}
+	// Denied values take precedence over allowed ones.
+	if orgPolicyHasValueGroups(l.denied) {
+		return orgPolicyValueUnknown
+	}
+	return orgPolicyValueAllowed
+}
+
+func orgPolicyHasValueGroups(values map[string]bool) bool {
+	for v := range values {
+		if strings.HasPrefix(v, "in:") || strings.HasPrefix(v, "under:") {
+			return true
+		}
+	}
+	return false
+}
+
+func (l orgPolicyEffectiveList) flatten() []map[string]interface{} {
+	allValues := ""
+	switch {
+	case l.allowed == nil && len(l.denied) == 0:
+		allValues = "ALLOW"
+	case l.allowed != nil && len(l.allowed) == 0:
+		allValues = "DENY"
+	}
+
+	var allowed, denied []string
+	for v := range l.allowed {
+		allowed = append(allowed, v)
+	}
+	for v := range l.denied {
+		denied = append(denied, v)
+	}
+	sort.Strings(allowed)
+	sort.Strings(denied)
+
+	return []map[string]interface{}{{
+		"all_values":     allValues,
+		"allowed_values": schema.NewSet(schema.HashString, convertStringArrToInterface(allowed)),
+		"denied_values":  schema.NewSet(schema.HashString, convertStringArrToInterface(denied)),
+	}}
+}
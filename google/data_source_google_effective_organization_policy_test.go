@@ -0,0 +1,250 @@
+package google
+
+import (
+	"fmt"
+	"sort"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"google.golang.org/api/cloudresourcemanager/v1"
+)
+
+func TestEffectiveListOrgPolicy(t *testing.T) {
+	allowE1E2 := &cloudresourcemanager.OrgPolicy{ListPolicy: &cloudresourcemanager.ListPolicy{AllowedValues: []string{"E1", "E2"}}}
+
+	cases := map[string]struct {
+		constraintDefault string
+		policies          []*cloudresourcemanager.OrgPolicy
+		allowed           []string
+		denied            []string
+		unknown           []string
+	}{
+		"no policy": {
+			constraintDefault: "ALLOW",
+			policies:          []*cloudresourcemanager.OrgPolicy{{}, {}},
+			allowed:           []string{"E1", "E9"},
+		},
+		"no policy with default deny": {
+			constraintDefault: "DENY",
+			policies:          []*cloudresourcemanager.OrgPolicy{{}, {}},
+			denied:            []string{"E1", "E9"},
+		},
+		"replaced values": {
+			constraintDefault: "ALLOW",
+			policies: []*cloudresourcemanager.OrgPolicy{
+				allowE1E2,
+				{ListPolicy: &cloudresourcemanager.ListPolicy{AllowedValues: []string{"E3", "E4"}}},
+			},
+			allowed: []string{"E3", "E4"},
+			denied:  []string{"E1", "E2"},
+		},
+		"inherited values": {
+			constraintDefault: "ALLOW",
+			policies: []*cloudresourcemanager.OrgPolicy{
+				allowE1E2,
+				{ListPolicy: &cloudresourcemanager.ListPolicy{AllowedValues: []string{"E3", "E4"}, InheritFromParent: true}},
+			},
+			allowed: []string{"E1", "E2", "E3", "E4"},
+			denied:  []string{"E5"},
+		},
+		"inherited allowed and denied values": {
+			constraintDefault: "ALLOW",
+			policies: []*cloudresourcemanager.OrgPolicy{
+				allowE1E2,
+				{ListPolicy: &cloudresourcemanager.ListPolicy{DeniedValues: []string{"E1"}, InheritFromParent: true}},
+			},
+			allowed: []string{"E2"},
+			denied:  []string{"E1", "E3"},
+		},
+		"restore default": {
+			constraintDefault: "DENY",
+			policies: []*cloudresourcemanager.OrgPolicy{
+				allowE1E2,
+				{RestoreDefault: &cloudresourcemanager.RestoreDefault{}},
+			},
+			denied: []string{"E1", "E2"},
+		},
+		"allow all": {
+			constraintDefault: "ALLOW",
+			policies: []*cloudresourcemanager.OrgPolicy{
+				allowE1E2,
+				{ListPolicy: &cloudresourcemanager.ListPolicy{AllValues: "ALLOW"}},
+			},
+			allowed: []string{"E1", "E3"},
+		},
+		"deny all": {
+			constraintDefault: "ALLOW",
+			policies: []*cloudresourcemanager.OrgPolicy{
+				allowE1E2,
+				{ListPolicy: &cloudresourcemanager.ListPolicy{AllValues: "DENY"}},
+			},
+			denied: []string{"E1", "E3"},
+		},
+		"inherit from the constraint default": {
+			constraintDefault: "ALLOW",
+			policies: []*cloudresourcemanager.OrgPolicy{
+				{},
+				{ListPolicy: &cloudresourcemanager.ListPolicy{AllowedValues: []string{"is:E1"}, InheritFromParent: true}},
+			},
+			allowed: []string{"E1", "is:E1"},
+			denied:  []string{"E2"},
+		},
+		"inherit from a policy allowing all values": {
+			constraintDefault: "DENY",
+			policies: []*cloudresourcemanager.OrgPolicy{
+				{ListPolicy: &cloudresourcemanager.ListPolicy{AllValues: "ALLOW"}},
+				{ListPolicy: &cloudresourcemanager.ListPolicy{DeniedValues: []string{"E2"}, InheritFromParent: true}},
+				{},
+			},
+			allowed: []string{"E1", "E3"},
+			denied:  []string{"E2"},
+		},
+		"allowed value group": {
+			constraintDefault: "ALLOW",
+			policies: []*cloudresourcemanager.OrgPolicy{
+				{ListPolicy: &cloudresourcemanager.ListPolicy{AllowedValues: []string{"in:us-locations", "is:europe-west1"}}},
+			},
+			allowed: []string{"in:us-locations", "europe-west1"},
+			unknown: []string{"us-central1", "in:europe-west1-locations"},
+		},
+		"denied subtree": {
+			constraintDefault: "ALLOW",
+			policies: []*cloudresourcemanager.OrgPolicy{
+				{ListPolicy: &cloudresourcemanager.ListPolicy{DeniedValues: []string{"under:folders/123", "projects/p1"}}},
+			},
+			denied:  []string{"under:folders/123", "projects/p1"},
+			unknown: []string{"projects/p2"},
+		},
+	}
+
+	for tn, tc := range cases {
+		effective := effectiveListOrgPolicy(tc.constraintDefault, tc.policies)
+		for _, v := range tc.allowed {
+			if r := effective.evaluate(v); r != orgPolicyValueAllowed {
+				t.Errorf("%s: expected %s to be allowed, got %s", tn, v, r)
+			}
+		}
+		for _, v := range tc.denied {
+			if r := effective.evaluate(v); r != orgPolicyValueDenied {
+				t.Errorf("%s: expected %s to be denied, got %s", tn, v, r)
+			}
+		}
+		for _, v := range tc.unknown {
+			if r := effective.evaluate(v); r != orgPolicyValueUnknown {
+				t.Errorf("%s: expected %s to be unknown, got %s", tn, v, r)
+			}
+		}
+	}
+}
+
+func TestEffectiveListOrgPolicy_flatten(t *testing.T) {
+	effective := effectiveListOrgPolicy("ALLOW", []*cloudresourcemanager.OrgPolicy{
+		{ListPolicy: &cloudresourcemanager.ListPolicy{AllowedValues: []string{"E2", "E1"}}},
+		{ListPolicy: &cloudresourcemanager.ListPolicy{DeniedValues: []string{"E1"}, InheritFromParent: true}},
+	})
+	flattened := effective.flatten()[0]
+
+	if flattened["all_values"] != "" {
+		t.Errorf("expected no all_values, got %q", flattened["all_values"])
+	}
+	allowed := convertStringSet(flattened["allowed_values"].(*schema.Set))
+	sort.Strings(allowed)
+	if fmt.Sprint(allowed) != "[E1 E2]" {
+		t.Errorf("expected allowed values [E1 E2], got %v", allowed)
+	}
+	if denied := convertStringSet(flattened["denied_values"].(*schema.Set)); fmt.Sprint(denied) != "[E1]" {
+		t.Errorf("expected denied values [E1], got %v", denied)
+	}
+
+	if all := effectiveListOrgPolicy("DENY", nil).flatten()[0]["all_values"]; all != "DENY" {
+		t.Errorf("expected all_values DENY, got %q", all)
+	}
+}
+
+func TestEffectiveBooleanOrgPolicy(t *testing.T) {
+	cases := map[string]struct {
+		constraintDefault string
+		policies          []*cloudresourcemanager.OrgPolicy
+		expected          bool
+	}{
+		"no policy": {
+			constraintDefault: "ALLOW",
+			policies:          []*cloudresourcemanager.OrgPolicy{{}},
+			expected:          false,
+		},
+		"enforced by the organization": {
+			constraintDefault: "ALLOW",
+			policies: []*cloudresourcemanager.OrgPolicy{
+				{BooleanPolicy: &cloudresourcemanager.BooleanPolicy{Enforced: true}},
+				{},
+			},
+			expected: true,
+		},
+		"not enforced by the project": {
+			constraintDefault: "ALLOW",
+			policies: []*cloudresourcemanager.OrgPolicy{
+				{BooleanPolicy: &cloudresourcemanager.BooleanPolicy{Enforced: true}},
+				{BooleanPolicy: &cloudresourcemanager.BooleanPolicy{Enforced: false}},
+			},
+			expected: false,
+		},
+		"restore default": {
+			constraintDefault: "DENY",
+			policies: []*cloudresourcemanager.OrgPolicy{
+				{BooleanPolicy: &cloudresourcemanager.BooleanPolicy{Enforced: false}},
+				{RestoreDefault: &cloudresourcemanager.RestoreDefault{}},
+			},
+			expected: true,
+		},
+	}
+
+	for tn, tc := range cases {
+		if got := effectiveBooleanOrgPolicy(tc.constraintDefault, tc.policies); got != tc.expected {
+			t.Errorf("%s: expected enforced to be %v, got %v", tn, tc.expected, got)
+		}
+	}
+}
+
+func TestAccDataSourceGoogleEffectiveOrganizationPolicy_basic(t *testing.T) {
+	project := getTestProjectFromEnv()
+
+	vcrTest(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceGoogleEffectiveOrganizationPolicy_basic(project),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.google_effective_organization_policy.data", "ancestry.0", "projects/"+project),
+					resource.TestCheckResourceAttr("data.google_effective_organization_policy.data", "evaluations.0.allowed", "true"),
+					resource.TestCheckResourceAttr("data.google_effective_organization_policy.data", "evaluations.1.allowed", "false"),
+					resource.TestCheckResourceAttr("data.google_effective_organization_policy.data", "evaluations.1.result", "DENIED"),
+					resource.TestCheckResourceAttr("data.google_effective_organization_policy.data", "allowed", "false"),
+					resource.TestCheckResourceAttr("data.google_effective_organization_policy.data", "result", "DENIED"),
+				),
+			},
+		},
+	})
+}
+
+func testAccDataSourceGoogleEffectiveOrganizationPolicy_basic(project string) string {
+	return fmt.Sprintf(`
+resource "google_project_organization_policy" "resource" {
+  project    = "%s"
+  constraint = "constraints/compute.trustedImageProjects"
+
+  list_policy {
+    allow {
+      values = ["projects/debian-cloud"]
+    }
+  }
+}
+
+data "google_effective_organization_policy" "data" {
+  project    = google_project_organization_policy.resource.project
+  constraint = "compute.trustedImageProjects"
+  values     = ["projects/debian-cloud", "projects/my-images"]
+}
+`, project)
+}
@@ -0,0 +1,122 @@
+package google
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceGoogleIamDenyPolicies() *schema.Resource {
+	rulesSchema := datasourceSchemaFromResourceSchema(resourceIAM2DenyPolicy().Schema)["rules"]
+
+	return &schema.Resource{
+		Read: dataSourceGoogleIamDenyPoliciesRead,
+		Schema: map[string]*schema.Schema{
+			"parent": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: `The attachment point, identified by its full resource name. The name may be URL-encoded.`,
+			},
+			"policies": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"display_name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"etag": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"create_time": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"update_time": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"rules": rulesSchema,
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceGoogleIamDenyPoliciesRead(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*Config)
+	userAgent, err := generateUserAgentString(d, config.userAgent)
+	if err != nil {
+		return err
+	}
+
+	parent := iamDenyPolicyParent(d.Get("parent").(string))
+	params := make(map[string]string)
+	policies := make([]map[string]interface{}, 0)
+
+	for {
+		listUrl, err := addQueryParams(fmt.Sprintf("%spolicies/%s/denypolicies", config.IAM2BasePath, parent), params)
+		if err != nil {
+			return err
+		}
+
+		res, err := sendRequest(config, "GET", "", listUrl, userAgent, nil)
+		if err != nil {
+			return fmt.Errorf("Error retrieving deny policies: %s", err)
+		}
+
+		// Listing omits the rules of the policies, so each policy is read
+		// individually.
+		if v, ok := res["policies"]; ok {
+			for _, raw := range v.([]interface{}) {
+				name := raw.(map[string]interface{})["name"].(string)
+				p, err := sendRequest(config, "GET", "", config.IAM2BasePath+name, userAgent, nil)
+				if err != nil {
+					return fmt.Errorf("Error retrieving deny policy %q: %s", name, err)
+				}
+				policies = append(policies, map[string]interface{}{
+					"name":         GetResourceNameFromSelfLink(name),
+					"display_name": p["displayName"],
+					"etag":         p["etag"],
+					"create_time":  p["createTime"],
+					"update_time":  p["updateTime"],
+					"rules":        flattenIAM2DenyPolicyRules(p["rules"], d, config),
+				})
+			}
+		}
+
+		pToken, ok := res["nextPageToken"]
+		if ok && pToken != nil && pToken.(string) != "" {
+			params["pageToken"] = pToken.(string)
+		} else {
+			break
+		}
+	}
+
+	if err := d.Set("policies", policies); err != nil {
+		return fmt.Errorf("Error retrieving deny policies: %s", err)
+	}
+
+	d.SetId(parent)
+
+	return nil
+}
+
+// iamDenyPolicyParent returns the URL-encoded form of a deny policy attachment
+// point, which is used in the policy names.
+func iamDenyPolicyParent(parent string) string {
+	if !strings.Contains(parent, "/") {
+		// already encoded
+		return parent
+	}
+	return url.QueryEscape(parent)
+}
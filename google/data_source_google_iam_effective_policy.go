@@ -0,0 +1,301 @@
+package google
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"google.golang.org/api/cloudidentity/v1"
+	"google.golang.org/api/cloudresourcemanager/v1"
+)
+
+func dataSourceGoogleIamEffectivePolicy() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceGoogleIamEffectivePolicyRead,
+		Schema: map[string]*schema.Schema{
+			"project": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Computed:      true,
+				ConflictsWith: []string{"folder"},
+				Description:   `The project to compute the effective policy of. If neither project nor folder is provided, the provider project is used.`,
+			},
+			"folder": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"project"},
+				Description:   `The folder to compute the effective policy of, in the form folders/{folder_id} or {folder_id}.`,
+			},
+			"role": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: `Only return bindings for this role.`,
+			},
+			"expand_groups": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Description: `Whether to resolve group members to their transitive members through Cloud Identity.`,
+			},
+			"ancestry": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: `The resource and its ancestors, starting with the resource, e.g. ["projects/my-project", "folders/123", "organizations/456"].`,
+			},
+			"bindings": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"role": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"members": {
+							Type:     schema.TypeSet,
+							Computed: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+						"expanded_members": {
+							Type:        schema.TypeSet,
+							Computed:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+							Description: `The members with groups replaced by their transitive members. Only set if expand_groups is true.`,
+						},
+						"source": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: `The resource in the ancestry the binding is granted on.`,
+						},
+						"condition": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"expression": {
+										Type:     schema.TypeString,
+										Computed: true,
+									},
+									"title": {
+										Type:     schema.TypeString,
+										Computed: true,
+									},
+									"description": {
+										Type:     schema.TypeString,
+										Computed: true,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceGoogleIamEffectivePolicyRead(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*Config)
+	userAgent, err := generateUserAgentString(d, config.userAgent)
+	if err != nil {
+		return err
+	}
+
+	var ancestry []string
+	if v, ok := d.GetOk("folder"); ok {
+		ancestry, err = iamEffectivePolicyFolderAncestry(config, userAgent, canonicalFolderId(v.(string)))
+	} else {
+		var project string
+		project, err = getProject(d, config)
+		if err != nil {
+			return err
+		}
+		if err := d.Set("project", project); err != nil {
+			return fmt.Errorf("Error setting project: %s", err)
+		}
+		ancestry, err = iamEffectivePolicyProjectAncestry(config, userAgent, project)
+	}
+	if err != nil {
+		return err
+	}
+
+	role := d.Get("role").(string)
+	expander := &iamGroupExpander{config: config, userAgent: userAgent, members: make(map[string][]string)}
+
+	var bindings []map[string]interface{}
+	for _, ancestor := range ancestry {
+		updater, err := iamEffectivePolicyUpdater(d, config, ancestor)
+		if err != nil {
+			return err
+		}
+		p, err := iamPolicyReadWithRetry(updater)
+		if err != nil {
+			return fmt.Errorf("Error reading IAM policy for %s: %s", ancestor, err)
+		}
+
+		for _, b := range p.Bindings {
+			if role != "" && b.Role != role {
+				continue
+			}
+			binding := map[string]interface{}{
+				"role":      b.Role,
+				"members":   b.Members,
+				"source":    ancestor,
+				"condition": flattenIamCondition(b.Condition),
+			}
+			if d.Get("expand_groups").(bool) {
+				expanded, err := expander.expand(b.Members)
+				if err != nil {
+					return err
+				}
+				binding["expanded_members"] = expanded
+			}
+			bindings = append(bindings, binding)
+		}
+	}
+
+	d.SetId(strings.Join(ancestry, "/"))
+	if err := d.Set("ancestry", ancestry); err != nil {
+		return fmt.Errorf("Error setting ancestry: %s", err)
+	}
+	if err := d.Set("bindings", bindings); err != nil {
+		return fmt.Errorf("Error setting bindings: %s", err)
+	}
+	return nil
+}
+
+// iamEffectivePolicyProjectAncestry returns the project and its ancestors as
+// resource names, starting with the project.
+func iamEffectivePolicyProjectAncestry(config *Config, userAgent, project string) ([]string, error) {
+	resp, err := config.NewResourceManagerClient(userAgent).Projects.GetAncestry(project, &cloudresourcemanager.GetAncestryRequest{}).Do()
+	if err != nil {
+		return nil, fmt.Errorf("Error retrieving ancestry of project %q: %s", project, err)
+	}
+
+	var ancestry []string
+	for _, a := range resp.Ancestor {
+		if a.ResourceId == nil {
+			continue
+		}
+		ancestry = append(ancestry, fmt.Sprintf("%ss/%s", a.ResourceId.Type, a.ResourceId.Id))
+	}
+	return ancestry, nil
+}
+
+// iamEffectivePolicyFolderAncestry returns the folder and its ancestors as
+// resource names, starting with the folder.
+func iamEffectivePolicyFolderAncestry(config *Config, userAgent, folder string) ([]string, error) {
+	ancestry := []string{folder}
+	for strings.HasPrefix(folder, "folders/") {
+		f, err := config.NewResourceManagerV2Client(userAgent).Folders.Get(folder).Do()
+		if err != nil {
+			return nil, fmt.Errorf("Error retrieving folder %q: %s", folder, err)
+		}
+		folder = f.Parent
+		ancestry = append(ancestry, folder)
+	}
+	return ancestry, nil
+}
+
+// iamEffectivePolicyUpdater returns the updater used by the IAM resources of
+// the given project, folder or organization.
+func iamEffectivePolicyUpdater(d TerraformResourceData, config *Config, resource string) (ResourceIamUpdater, error) {
+	fields := &iamResourceNameFields{
+		TerraformResourceData: d,
+		fields:                make(map[string]interface{}),
+	}
+	parts := strings.SplitN(resource, "/", 2)
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("Unexpected ancestor %q", resource)
+	}
+	switch parts[0] {
+	case "projects":
+		fields.fields["project"] = parts[1]
+		return NewProjectIamUpdater(fields, config)
+	case "folders":
+		fields.fields["folder"] = resource
+		return NewFolderIamUpdater(fields, config)
+	case "organizations":
+		fields.fields["org_id"] = parts[1]
+		return NewOrganizationIamUpdater(fields, config)
+	}
+	return nil, fmt.Errorf("Unexpected ancestor %q", resource)
+}
+
+// iamGroupExpander resolves group members to their transitive members,
+// caching the members of each group.
+type iamGroupExpander struct {
+	config    *Config
+	userAgent string
+	members   map[string][]string
+}
+
+// expand returns the sorted members with every group: member replaced by the
+// transitive members of the group.
+func (e *iamGroupExpander) expand(members []string) ([]string, error) {
+	set := make(map[string]struct{})
+	for _, m := range members {
+		if !strings.HasPrefix(m, "group:") {
+			set[m] = struct{}{}
+			continue
+		}
+		groupMembers, err := e.groupMembers(strings.TrimPrefix(m, "group:"))
+		if err != nil {
+			return nil, err
+		}
+		for _, gm := range groupMembers {
+			set[gm] = struct{}{}
+		}
+	}
+
+	expanded := make([]string, 0, len(set))
+	for m := range set {
+		expanded = append(expanded, m)
+	}
+	sort.Strings(expanded)
+	return expanded, nil
+}
+
+func (e *iamGroupExpander) groupMembers(email string) ([]string, error) {
+	if members, ok := e.members[email]; ok {
+		return members, nil
+	}
+
+	client := e.config.NewCloudIdentityClient(e.userAgent)
+	group, err := client.Groups.Lookup().GroupKeyId(email).Do()
+	if err != nil {
+		return nil, fmt.Errorf("Error looking up group %q: %s", email, err)
+	}
+
+	var members []string
+	err = client.Groups.Memberships.SearchTransitiveMemberships(group.Name).Pages(e.config.context, func(resp *cloudidentity.SearchTransitiveMembershipsResponse) error {
+		for _, m := range resp.Memberships {
+			for _, key := range m.PreferredMemberKey {
+				if member, ok := iamMemberFromRelation(m.Member, key.Id); ok {
+					members = append(members, member)
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Error listing transitive members of group %q: %s", email, err)
+	}
+
+	e.members[email] = members
+	return members, nil
+}
+
+// iamMemberFromRelation returns the IAM member identifier of a transitive
+// member of a group, from its resource name and member key. Nested groups are
+// skipped, as their own members are transitive members too.
+func iamMemberFromRelation(name, email string) (string, bool) {
+	if strings.HasPrefix(name, "groups/") {
+		return "", false
+	}
+	if strings.HasSuffix(email, ".gserviceaccount.com") {
+		return "serviceAccount:" + email, true
+	}
+	return "user:" + email, true
+}
@@ -0,0 +1,111 @@
+package google
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestIamEffectivePolicyUpdater(t *testing.T) {
+	d := &ResourceDataMock{FieldsInSchema: map[string]interface{}{}}
+	config := &Config{}
+
+	cases := map[string]string{
+		"projects/my-project":   getProjectIamPolicyMutexKey("my-project"),
+		"folders/123":           "iam-folder-folders/123",
+		"organizations/456":     "iam-organization-456",
+		"billingAccounts/01234": "",
+	}
+	for ancestor, mutexKey := range cases {
+		u, err := iamEffectivePolicyUpdater(d, config, ancestor)
+		if mutexKey == "" {
+			if err == nil {
+				t.Errorf("%s: expected error, got none", ancestor)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", ancestor, err)
+			continue
+		}
+		if got := u.GetMutexKey(); got != mutexKey {
+			t.Errorf("%s: expected mutex key %q, got %q", ancestor, mutexKey, got)
+		}
+	}
+}
+
+func TestIamMemberFromRelation(t *testing.T) {
+	cases := map[string]struct {
+		name     string
+		email    string
+		expected string
+	}{
+		"user": {
+			name:     "users/123",
+			email:    "jane@example.com",
+			expected: "user:jane@example.com",
+		},
+		"service account": {
+			name:     "users/456",
+			email:    "sa@my-project.iam.gserviceaccount.com",
+			expected: "serviceAccount:sa@my-project.iam.gserviceaccount.com",
+		},
+		"nested group": {
+			name:  "groups/789",
+			email: "team@example.com",
+		},
+	}
+	for tn, tc := range cases {
+		got, ok := iamMemberFromRelation(tc.name, tc.email)
+		if ok != (tc.expected != "") || got != tc.expected {
+			t.Errorf("%s: expected %q, got %q (%t)", tn, tc.expected, got, ok)
+		}
+	}
+}
+
+func TestAccDataSourceGoogleIamEffectivePolicy_project(t *testing.T) {
+	t.Parallel()
+
+	org := getTestOrgFromEnv(t)
+	pid := fmt.Sprintf("tf-test-%d", randInt(t))
+
+	vcrTest(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceGoogleIamEffectivePolicy_project(pid, pname, org),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.google_iam_effective_policy.policy", "ancestry.0", "projects/"+pid),
+					resource.TestCheckResourceAttr("data.google_iam_effective_policy.policy", "ancestry.1", "organizations/"+org),
+					resource.TestCheckResourceAttr("data.google_iam_effective_policy.policy", "bindings.0.role", "roles/viewer"),
+					resource.TestCheckResourceAttr("data.google_iam_effective_policy.policy", "bindings.0.source", "projects/"+pid),
+					resource.TestMatchResourceAttr("data.google_iam_effective_policy.policy", "bindings.#", regexp.MustCompile("^[1-9][0-9]*$")),
+				),
+			},
+		},
+	})
+}
+
+func testAccDataSourceGoogleIamEffectivePolicy_project(pid, name, org string) string {
+	return fmt.Sprintf(`
+resource "google_project" "acceptance" {
+  project_id = "%s"
+  name       = "%s"
+  org_id     = "%s"
+}
+
+resource "google_project_iam_member" "viewer" {
+  project = google_project.acceptance.project_id
+  role    = "roles/viewer"
+  member  = "user:admin@hashicorptest.com"
+}
+
+data "google_iam_effective_policy" "policy" {
+  project = google_project_iam_member.viewer.project
+  role    = "roles/viewer"
+}
+`, pid, name, org)
+}
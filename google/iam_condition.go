@@ -0,0 +1,1142 @@
+package google
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// IAM condition expressions are written in a subset of the Common Expression
+// Language (CEL). The parser and type checker below cover the CEL syntax and
+// the attributes and functions documented for IAM Conditions at
+// https://cloud.google.com/iam/docs/conditions-attribute-reference, so invalid
+// expressions are reported at plan time instead of failing halfway through an
+// apply. Fields that aren't documented there are errors, except below the
+// attributes documented as dynamic, such as request.auth.claims and the values
+// of api.getAttribute. Functions that aren't documented are checked as dynamic
+// values, and only logged, as some services support more of them.
+
+// iamConditionCustomizeDiff validates condition.0.expression of IAM member and
+// binding resources, unless the provider sets skip_iam_condition_validation.
+func iamConditionCustomizeDiff(_ context.Context, diff *schema.ResourceDiff, meta interface{}) error {
+	if config, ok := meta.(*Config); ok && config.SkipIamConditionValidation {
+		return nil
+	}
+	if !diff.NewValueKnown("condition.0.expression") {
+		return nil
+	}
+	v, ok := diff.GetOk("condition.0.expression")
+	if !ok {
+		return nil
+	}
+	warnings, err := validateIamConditionExpression(v.(string))
+	for _, w := range warnings {
+		log.Printf("[WARN] IAM condition expression isn't fully checked, the API validates it when applied:\n%s", w)
+	}
+	if err != nil {
+		return fmt.Errorf("Invalid IAM condition expression:\n%s\nSet skip_iam_condition_validation in the provider to apply it anyway.", err)
+	}
+	return nil
+}
+
+// validateIamConditionExpression parses and type checks an IAM condition
+// expression, returning an error that points at the offending position, and
+// warnings for the functions it doesn't know.
+func validateIamConditionExpression(expression string) ([]string, error) {
+	e, err := parseCel(expression)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &celChecker{source: expression}
+	t := c.check(e)
+	if len(c.errs) == 0 && t.kind != celDyn && t.kind != celBool {
+		c.errorf(e.pos, "expression must evaluate to bool, found '%s'", t)
+	}
+	warnings := make([]string, 0, len(c.warns))
+	for _, w := range c.warns {
+		warnings = append(warnings, w.Error())
+	}
+	if len(c.errs) > 0 {
+		msgs := make([]string, 0, len(c.errs))
+		for _, e := range c.errs {
+			msgs = append(msgs, e.Error())
+		}
+		return warnings, fmt.Errorf("%s", strings.Join(msgs, "\n"))
+	}
+	return warnings, nil
+}
+
+// celError is an error at a byte offset of a CEL source, formatted like the
+// errors returned by the API.
+type celError struct {
+	source string
+	pos    int
+	msg    string
+}
+
+func (e *celError) Error() string {
+	line, col := 1, 1
+	lineStart := 0
+	for i, r := range e.source {
+		if i >= e.pos {
+			break
+		}
+		if r == '\n' {
+			line++
+			col = 1
+			lineStart = i + 1
+		} else {
+			col++
+		}
+	}
+	lineEnd := strings.IndexByte(e.source[lineStart:], '\n')
+	if lineEnd < 0 {
+		lineEnd = len(e.source)
+	} else {
+		lineEnd += lineStart
+	}
+	return fmt.Sprintf("ERROR: <input>:%d:%d: %s\n | %s\n | %s^", line, col, e.msg, e.source[lineStart:lineEnd], strings.Repeat(".", col-1))
+}
+
+//
+// Types
+//
+
+type celKind int
+
+const (
+	celDyn celKind = iota
+	celBool
+	celInt
+	celUint
+	celDouble
+	celString
+	celBytes
+	celNull
+	celTimestamp
+	celDuration
+	celList
+	celMap
+	celObject
+)
+
+type celType struct {
+	kind celKind
+	// elem is the element type of a list or the value type of a map.
+	elem *celType
+	// key is the key type of a map.
+	key *celType
+	// name is the name of an object type.
+	name string
+}
+
+var (
+	celDynType       = &celType{kind: celDyn}
+	celBoolType      = &celType{kind: celBool}
+	celIntType       = &celType{kind: celInt}
+	celUintType      = &celType{kind: celUint}
+	celDoubleType    = &celType{kind: celDouble}
+	celStringType    = &celType{kind: celString}
+	celBytesType     = &celType{kind: celBytes}
+	celNullType      = &celType{kind: celNull}
+	celTimestampType = &celType{kind: celTimestamp}
+	celDurationType  = &celType{kind: celDuration}
+)
+
+func celListOf(elem *celType) *celType {
+	return &celType{kind: celList, elem: elem}
+}
+
+func celMapOf(key, elem *celType) *celType {
+	return &celType{kind: celMap, key: key, elem: elem}
+}
+
+func celObjectOf(name string) *celType {
+	return &celType{kind: celObject, name: name}
+}
+
+func (t *celType) String() string {
+	switch t.kind {
+	case celBool:
+		return "bool"
+	case celInt:
+		return "int"
+	case celUint:
+		return "uint"
+	case celDouble:
+		return "double"
+	case celString:
+		return "string"
+	case celBytes:
+		return "bytes"
+	case celNull:
+		return "null_type"
+	case celTimestamp:
+		return "google.protobuf.Timestamp"
+	case celDuration:
+		return "google.protobuf.Duration"
+	case celList:
+		return fmt.Sprintf("list(%s)", t.elem)
+	case celMap:
+		return fmt.Sprintf("map(%s, %s)", t.key, t.elem)
+	case celObject:
+		return t.name
+	}
+	return "dyn"
+}
+
+// celAssignable reports whether a value of type got can be used where want is
+// expected. dyn is assignable both ways.
+func celAssignable(want, got *celType) bool {
+	if want.kind == celDyn || got.kind == celDyn {
+		return true
+	}
+	if want.kind != got.kind {
+		return false
+	}
+	switch want.kind {
+	case celList:
+		return celAssignable(want.elem, got.elem)
+	case celMap:
+		return celAssignable(want.key, got.key) && celAssignable(want.elem, got.elem)
+	case celObject:
+		return want.name == got.name
+	}
+	return true
+}
+
+// celJoin returns the common type of a and b, or dyn.
+func celJoin(a, b *celType) *celType {
+	if a.kind == celDyn || b.kind == celDyn {
+		return celDynType
+	}
+	if celAssignable(a, b) && celAssignable(b, a) {
+		return a
+	}
+	return celDynType
+}
+
+//
+// Environment
+//
+
+// iamConditionObjects are the fields of the attribute objects available to
+// IAM conditions.
+var iamConditionObjects = map[string]map[string]*celType{
+	"api": {},
+	"destination": {
+		"ip":   celStringType,
+		"port": celIntType,
+	},
+	"request": {
+		"auth": celObjectOf("request.auth"),
+		"host": celStringType,
+		"path": celStringType,
+		"time": celTimestampType,
+	},
+	"request.auth": {
+		"access_levels": celListOf(celStringType),
+		"claims":        celMapOf(celStringType, celDynType),
+	},
+	"resource": {
+		"name":    celStringType,
+		"service": celStringType,
+		"type":    celStringType,
+	},
+}
+
+var iamConditionVariables = map[string]*celType{
+	"api":         celObjectOf("api"),
+	"destination": celObjectOf("destination"),
+	"request":     celObjectOf("request"),
+	"resource":    celObjectOf("resource"),
+}
+
+type celOverload struct {
+	// receiver is nil for global functions.
+	receiver *celType
+	args     []*celType
+	result   *celType
+}
+
+func celTimestampAccessor() []celOverload {
+	return []celOverload{
+		{receiver: celTimestampType, result: celIntType},
+		{receiver: celTimestampType, args: []*celType{celStringType}, result: celIntType},
+	}
+}
+
+var iamConditionFunctions = map[string][]celOverload{
+	// Conversions
+	"bool":      {{args: []*celType{celBoolType}, result: celBoolType}, {args: []*celType{celStringType}, result: celBoolType}},
+	"bytes":     {{args: []*celType{celBytesType}, result: celBytesType}, {args: []*celType{celStringType}, result: celBytesType}},
+	"double":    {{args: []*celType{celDoubleType}, result: celDoubleType}, {args: []*celType{celIntType}, result: celDoubleType}, {args: []*celType{celUintType}, result: celDoubleType}, {args: []*celType{celStringType}, result: celDoubleType}},
+	"duration":  {{args: []*celType{celStringType}, result: celDurationType}, {args: []*celType{celDurationType}, result: celDurationType}},
+	"int":       {{args: []*celType{celIntType}, result: celIntType}, {args: []*celType{celUintType}, result: celIntType}, {args: []*celType{celDoubleType}, result: celIntType}, {args: []*celType{celStringType}, result: celIntType}, {args: []*celType{celTimestampType}, result: celIntType}},
+	"string":    {{args: []*celType{celDynType}, result: celStringType}},
+	"timestamp": {{args: []*celType{celStringType}, result: celTimestampType}, {args: []*celType{celIntType}, result: celTimestampType}, {args: []*celType{celTimestampType}, result: celTimestampType}},
+	"uint":      {{args: []*celType{celUintType}, result: celUintType}, {args: []*celType{celIntType}, result: celUintType}, {args: []*celType{celDoubleType}, result: celUintType}, {args: []*celType{celStringType}, result: celUintType}},
+	"dyn":       {{args: []*celType{celDynType}, result: celDynType}},
+
+	// Size
+	"size": {
+		{args: []*celType{celStringType}, result: celIntType},
+		{args: []*celType{celBytesType}, result: celIntType},
+		{args: []*celType{celListOf(celDynType)}, result: celIntType},
+		{args: []*celType{celMapOf(celDynType, celDynType)}, result: celIntType},
+		{receiver: celStringType, result: celIntType},
+		{receiver: celBytesType, result: celIntType},
+		{receiver: celListOf(celDynType), result: celIntType},
+		{receiver: celMapOf(celDynType, celDynType), result: celIntType},
+	},
+
+	// Strings
+	"contains":   {{receiver: celStringType, args: []*celType{celStringType}, result: celBoolType}},
+	"endsWith":   {{receiver: celStringType, args: []*celType{celStringType}, result: celBoolType}},
+	"extract":    {{receiver: celStringType, args: []*celType{celStringType}, result: celStringType}},
+	"matches":    {{receiver: celStringType, args: []*celType{celStringType}, result: celBoolType}, {args: []*celType{celStringType, celStringType}, result: celBoolType}},
+	"startsWith": {{receiver: celStringType, args: []*celType{celStringType}, result: celBoolType}},
+
+	// Lists
+	"hasOnly": {{receiver: celListOf(celDynType), args: []*celType{celListOf(celDynType)}, result: celBoolType}},
+
+	// Date and time
+	"getDate":         celTimestampAccessor(),
+	"getDayOfMonth":   celTimestampAccessor(),
+	"getDayOfWeek":    celTimestampAccessor(),
+	"getDayOfYear":    celTimestampAccessor(),
+	"getFullYear":     celTimestampAccessor(),
+	"getMonth":        celTimestampAccessor(),
+	"getHours":        append(celTimestampAccessor(), celOverload{receiver: celDurationType, result: celIntType}),
+	"getMinutes":      append(celTimestampAccessor(), celOverload{receiver: celDurationType, result: celIntType}),
+	"getSeconds":      append(celTimestampAccessor(), celOverload{receiver: celDurationType, result: celIntType}),
+	"getMilliseconds": append(celTimestampAccessor(), celOverload{receiver: celDurationType, result: celIntType}),
+
+	// IAM attributes
+	"getAttribute": {{receiver: celObjectOf("api"), args: []*celType{celStringType, celDynType}, result: celDynType}},
+	"hasTagKey":    {{receiver: celObjectOf("resource"), args: []*celType{celStringType}, result: celBoolType}},
+	"hasTagKeyId":  {{receiver: celObjectOf("resource"), args: []*celType{celStringType}, result: celBoolType}},
+	"matchTag":     {{receiver: celObjectOf("resource"), args: []*celType{celStringType, celStringType}, result: celBoolType}},
+	"matchTagId":   {{receiver: celObjectOf("resource"), args: []*celType{celStringType, celStringType}, result: celBoolType}},
+}
+
+//
+// Lexer
+//
+
+type celTokenKind int
+
+const (
+	celTokEOF celTokenKind = iota
+	celTokIdent
+	celTokInt
+	celTokUint
+	celTokDouble
+	celTokString
+	celTokBytes
+	celTokPunct
+)
+
+type celToken struct {
+	kind celTokenKind
+	text string
+	pos  int
+}
+
+func (t celToken) String() string {
+	if t.kind == celTokEOF {
+		return "<EOF>"
+	}
+	return t.text
+}
+
+var celReservedIdentifiers = map[string]bool{
+	"as": true, "break": true, "const": true, "continue": true, "else": true,
+	"for": true, "function": true, "if": true, "import": true, "let": true,
+	"loop": true, "package": true, "namespace": true, "return": true,
+	"var": true, "void": true, "while": true,
+}
+
+// celPunctuation is ordered so that longer operators match first.
+var celPunctuation = []string{
+	"&&", "||", "==", "!=", "<=", ">=",
+	"<", ">", "!", "+", "-", "*", "/", "%", "?", ":", ".", ",", "(", ")", "[", "]", "{", "}",
+}
+
+type celSyntaxError struct {
+	err *celError
+}
+
+func celLex(source string) ([]celToken, error) {
+	var toks []celToken
+	fail := func(pos int, format string, args ...interface{}) ([]celToken, error) {
+		return nil, &celError{source: source, pos: pos, msg: "Syntax error: " + fmt.Sprintf(format, args...)}
+	}
+
+	i := 0
+	for i < len(source) {
+		r, size := utf8.DecodeRuneInString(source[i:])
+		switch {
+		case unicode.IsSpace(r):
+			i += size
+		case strings.HasPrefix(source[i:], "//"):
+			for i < len(source) && source[i] != '\n' {
+				i++
+			}
+		case r == '_' || unicode.IsLetter(r):
+			start := i
+			for i < len(source) {
+				r, size := utf8.DecodeRuneInString(source[i:])
+				if r != '_' && !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+					break
+				}
+				i += size
+			}
+			text := source[start:i]
+			// String and bytes literals may be prefixed with r, b or both.
+			lower := strings.ToLower(text)
+			if i < len(source) && (source[i] == '"' || source[i] == '\'') && (lower == "r" || lower == "b" || lower == "rb" || lower == "br") {
+				end, err := celLexString(source, i, strings.Contains(lower, "r"))
+				if err != nil {
+					return nil, err
+				}
+				kind := celTokString
+				if strings.Contains(lower, "b") {
+					kind = celTokBytes
+				}
+				toks = append(toks, celToken{kind: kind, text: source[start:end], pos: start})
+				i = end
+				continue
+			}
+			if celReservedIdentifiers[text] {
+				return fail(start, "reserved identifier: %s", text)
+			}
+			toks = append(toks, celToken{kind: celTokIdent, text: text, pos: start})
+		case r >= '0' && r <= '9' || r == '.' && i+1 < len(source) && source[i+1] >= '0' && source[i+1] <= '9':
+			start := i
+			kind := celTokInt
+			if strings.HasPrefix(source[i:], "0x") || strings.HasPrefix(source[i:], "0X") {
+				i += 2
+				for i < len(source) && strings.ContainsRune("0123456789abcdefABCDEF", rune(source[i])) {
+					i++
+				}
+			} else {
+				for i < len(source) && source[i] >= '0' && source[i] <= '9' {
+					i++
+				}
+				if i+1 < len(source) && source[i] == '.' && source[i+1] >= '0' && source[i+1] <= '9' {
+					kind = celTokDouble
+					i++
+					for i < len(source) && source[i] >= '0' && source[i] <= '9' {
+						i++
+					}
+				}
+				if i < len(source) && (source[i] == 'e' || source[i] == 'E') {
+					kind = celTokDouble
+					i++
+					if i < len(source) && (source[i] == '+' || source[i] == '-') {
+						i++
+					}
+					digits := i
+					for i < len(source) && source[i] >= '0' && source[i] <= '9' {
+						i++
+					}
+					if digits == i {
+						return fail(start, "malformed number %s", source[start:i])
+					}
+				}
+			}
+			if kind == celTokInt && i < len(source) && (source[i] == 'u' || source[i] == 'U') {
+				kind = celTokUint
+				i++
+			}
+			toks = append(toks, celToken{kind: kind, text: source[start:i], pos: start})
+		case r == '"' || r == '\'':
+			end, err := celLexString(source, i, false)
+			if err != nil {
+				return nil, err
+			}
+			toks = append(toks, celToken{kind: celTokString, text: source[i:end], pos: i})
+			i = end
+		default:
+			matched := false
+			for _, p := range celPunctuation {
+				if strings.HasPrefix(source[i:], p) {
+					toks = append(toks, celToken{kind: celTokPunct, text: p, pos: i})
+					i += len(p)
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				return fail(i, "token recognition error at: '%c'", r)
+			}
+		}
+	}
+	return append(toks, celToken{kind: celTokEOF, pos: len(source)}), nil
+}
+
+// celLexString returns the offset just past the string literal whose opening
+// quote is at start.
+func celLexString(source string, start int, raw bool) (int, error) {
+	quote := source[start : start+1]
+	if strings.HasPrefix(source[start:], strings.Repeat(quote, 3)) {
+		quote = strings.Repeat(quote, 3)
+	}
+	i := start + len(quote)
+	for i < len(source) {
+		switch {
+		case strings.HasPrefix(source[i:], quote):
+			return i + len(quote), nil
+		case source[i] == '\\' && !raw:
+			i += 2
+		case source[i] == '\n' && len(quote) == 1:
+			i = len(source)
+		default:
+			i++
+		}
+	}
+	return 0, &celError{source: source, pos: start, msg: "Syntax error: unterminated string literal"}
+}
+
+//
+// Parser
+//
+
+type celExprKind int
+
+const (
+	celExprLiteral celExprKind = iota
+	celExprIdent
+	celExprSelect
+	celExprCall
+	celExprList
+	celExprMap
+)
+
+type celExpr struct {
+	kind celExprKind
+	pos  int
+	// name is the identifier, selected field or function name. Operators are
+	// calls named like _&&_.
+	name string
+	// literal is the type of a literal.
+	literal *celType
+	// target is the operand of a field selection or receiver of a call.
+	target *celExpr
+	// args are the call arguments, list elements, or alternating map keys
+	// and values.
+	args []*celExpr
+}
+
+type celParser struct {
+	source string
+	toks   []celToken
+	next   int
+}
+
+func parseCel(source string) (e *celExpr, err error) {
+	toks, err := celLex(source)
+	if err != nil {
+		return nil, err
+	}
+	p := &celParser{source: source, toks: toks}
+
+	defer func() {
+		if r := recover(); r != nil {
+			serr, ok := r.(celSyntaxError)
+			if !ok {
+				panic(r)
+			}
+			e, err = nil, serr.err
+		}
+	}()
+
+	e = p.parseExpr()
+	if t := p.peek(); t.kind != celTokEOF {
+		p.fail(t.pos, "extraneous input '%s'", t)
+	}
+	return e, nil
+}
+
+func (p *celParser) fail(pos int, format string, args ...interface{}) {
+	panic(celSyntaxError{&celError{source: p.source, pos: pos, msg: "Syntax error: " + fmt.Sprintf(format, args...)}})
+}
+
+func (p *celParser) peek() celToken {
+	return p.toks[p.next]
+}
+
+func (p *celParser) advance() celToken {
+	t := p.toks[p.next]
+	if t.kind != celTokEOF {
+		p.next++
+	}
+	return t
+}
+
+func (p *celParser) accept(punct string) bool {
+	if t := p.peek(); t.kind == celTokPunct && t.text == punct {
+		p.next++
+		return true
+	}
+	return false
+}
+
+func (p *celParser) expect(punct string) celToken {
+	t := p.peek()
+	if t.kind != celTokPunct || t.text != punct {
+		p.fail(t.pos, "mismatched input '%s' expecting '%s'", t, punct)
+	}
+	return p.advance()
+}
+
+func celCall(pos int, name string, args ...*celExpr) *celExpr {
+	return &celExpr{kind: celExprCall, pos: pos, name: name, args: args}
+}
+
+func (p *celParser) parseExpr() *celExpr {
+	e := p.parseOr()
+	if t := p.peek(); t.kind == celTokPunct && t.text == "?" {
+		p.advance()
+		truthy := p.parseOr()
+		p.expect(":")
+		falsy := p.parseExpr()
+		return celCall(t.pos, "_?_:_", e, truthy, falsy)
+	}
+	return e
+}
+
+func (p *celParser) parseOr() *celExpr {
+	e := p.parseAnd()
+	for {
+		t := p.peek()
+		if !p.accept("||") {
+			return e
+		}
+		e = celCall(t.pos, "_||_", e, p.parseAnd())
+	}
+}
+
+func (p *celParser) parseAnd() *celExpr {
+	e := p.parseRelation()
+	for {
+		t := p.peek()
+		if !p.accept("&&") {
+			return e
+		}
+		e = celCall(t.pos, "_&&_", e, p.parseRelation())
+	}
+}
+
+func (p *celParser) parseRelation() *celExpr {
+	e := p.parseAddition()
+	for {
+		t := p.peek()
+		switch {
+		case t.kind == celTokPunct && (t.text == "<" || t.text == "<=" || t.text == ">" || t.text == ">=" || t.text == "==" || t.text == "!="):
+			p.advance()
+			e = celCall(t.pos, "_"+t.text+"_", e, p.parseAddition())
+		case t.kind == celTokIdent && t.text == "in":
+			p.advance()
+			e = celCall(t.pos, "@in", e, p.parseAddition())
+		default:
+			return e
+		}
+	}
+}
+
+func (p *celParser) parseAddition() *celExpr {
+	e := p.parseMultiplication()
+	for {
+		t := p.peek()
+		if t.kind != celTokPunct || (t.text != "+" && t.text != "-") {
+			return e
+		}
+		p.advance()
+		e = celCall(t.pos, "_"+t.text+"_", e, p.parseMultiplication())
+	}
+}
+
+func (p *celParser) parseMultiplication() *celExpr {
+	e := p.parseUnary()
+	for {
+		t := p.peek()
+		if t.kind != celTokPunct || (t.text != "*" && t.text != "/" && t.text != "%") {
+			return e
+		}
+		p.advance()
+		e = celCall(t.pos, "_"+t.text+"_", e, p.parseUnary())
+	}
+}
+
+func (p *celParser) parseUnary() *celExpr {
+	t := p.peek()
+	if p.accept("!") {
+		return celCall(t.pos, "!_", p.parseUnary())
+	}
+	if p.accept("-") {
+		return celCall(t.pos, "-_", p.parseUnary())
+	}
+	return p.parseMember()
+}
+
+func (p *celParser) parseMember() *celExpr {
+	e := p.parsePrimary()
+	for {
+		t := p.peek()
+		switch {
+		case p.accept("."):
+			id := p.advance()
+			if id.kind != celTokIdent {
+				p.fail(id.pos, "mismatched input '%s' expecting IDENTIFIER", id)
+			}
+			if p.accept("(") {
+				call := celCall(id.pos, id.text, p.parseArgs(")")...)
+				call.target = e
+				e = call
+			} else {
+				e = &celExpr{kind: celExprSelect, pos: id.pos, name: id.text, target: e}
+			}
+		case p.accept("["):
+			index := p.parseExpr()
+			p.expect("]")
+			e = celCall(t.pos, "_[_]", e, index)
+		default:
+			return e
+		}
+	}
+}
+
+// parseArgs parses a comma separated, possibly empty list of expressions up
+// to and including the closing token.
+func (p *celParser) parseArgs(closing string) []*celExpr {
+	var args []*celExpr
+	if p.accept(closing) {
+		return args
+	}
+	for {
+		args = append(args, p.parseExpr())
+		if p.accept(closing) {
+			return args
+		}
+		if t := p.peek(); !p.accept(",") {
+			p.fail(t.pos, "mismatched input '%s' expecting {',', '%s'}", t, closing)
+		}
+		// Lists and maps may end with a trailing comma.
+		if closing != ")" && p.accept(closing) {
+			return args
+		}
+	}
+}
+
+func (p *celParser) parsePrimary() *celExpr {
+	t := p.advance()
+	switch t.kind {
+	case celTokIdent:
+		switch t.text {
+		case "true", "false":
+			return &celExpr{kind: celExprLiteral, pos: t.pos, literal: celBoolType}
+		case "null":
+			return &celExpr{kind: celExprLiteral, pos: t.pos, literal: celNullType}
+		case "in":
+			p.fail(t.pos, "mismatched input 'in'")
+		}
+		if p.accept("(") {
+			return celCall(t.pos, t.text, p.parseArgs(")")...)
+		}
+		return &celExpr{kind: celExprIdent, pos: t.pos, name: t.text}
+	case celTokInt:
+		return &celExpr{kind: celExprLiteral, pos: t.pos, literal: celIntType}
+	case celTokUint:
+		return &celExpr{kind: celExprLiteral, pos: t.pos, literal: celUintType}
+	case celTokDouble:
+		return &celExpr{kind: celExprLiteral, pos: t.pos, literal: celDoubleType}
+	case celTokString:
+		return &celExpr{kind: celExprLiteral, pos: t.pos, literal: celStringType}
+	case celTokBytes:
+		return &celExpr{kind: celExprLiteral, pos: t.pos, literal: celBytesType}
+	case celTokPunct:
+		switch t.text {
+		case "(":
+			e := p.parseExpr()
+			p.expect(")")
+			return e
+		case "[":
+			return &celExpr{kind: celExprList, pos: t.pos, args: p.parseArgs("]")}
+		case "{":
+			m := &celExpr{kind: celExprMap, pos: t.pos}
+			if p.accept("}") {
+				return m
+			}
+			for {
+				m.args = append(m.args, p.parseExpr())
+				p.expect(":")
+				m.args = append(m.args, p.parseExpr())
+				if p.accept("}") {
+					return m
+				}
+				p.expect(",")
+				if p.accept("}") {
+					return m
+				}
+			}
+		case ".":
+			// A leading dot refers to the root namespace.
+			return p.parsePrimary()
+		}
+	}
+	p.fail(t.pos, "mismatched input '%s'", t)
+	return nil
+}
+
+//
+// Type checker
+//
+
+type celChecker struct {
+	source string
+	errs   []*celError
+	warns  []*celError
+	// scopes holds the variables declared by comprehension macros.
+	scopes []map[string]*celType
+}
+
+func (c *celChecker) errorf(pos int, format string, args ...interface{}) {
+	c.errs = append(c.errs, &celError{source: c.source, pos: pos, msg: fmt.Sprintf(format, args...)})
+}
+
+func (c *celChecker) warnf(pos int, format string, args ...interface{}) {
+	c.warns = append(c.warns, &celError{source: c.source, pos: pos, msg: fmt.Sprintf(format, args...)})
+}
+
+func (c *celChecker) check(e *celExpr) *celType {
+	switch e.kind {
+	case celExprLiteral:
+		return e.literal
+	case celExprIdent:
+		for i := len(c.scopes) - 1; i >= 0; i-- {
+			if t, ok := c.scopes[i][e.name]; ok {
+				return t
+			}
+		}
+		if t, ok := iamConditionVariables[e.name]; ok {
+			return t
+		}
+		c.errorf(e.pos, "undeclared reference to '%s'", e.name)
+		return celDynType
+	case celExprSelect:
+		return c.checkSelect(e)
+	case celExprList:
+		var elem *celType
+		for _, a := range e.args {
+			t := c.check(a)
+			if elem == nil {
+				elem = t
+			} else {
+				elem = celJoin(elem, t)
+			}
+		}
+		if elem == nil {
+			elem = celDynType
+		}
+		return celListOf(elem)
+	case celExprMap:
+		var key, elem *celType
+		for i := 0; i < len(e.args); i += 2 {
+			k, v := c.check(e.args[i]), c.check(e.args[i+1])
+			if key == nil {
+				key, elem = k, v
+			} else {
+				key, elem = celJoin(key, k), celJoin(elem, v)
+			}
+		}
+		if key == nil {
+			key, elem = celDynType, celDynType
+		}
+		return celMapOf(key, elem)
+	case celExprCall:
+		return c.checkCall(e)
+	}
+	return celDynType
+}
+
+func (c *celChecker) checkSelect(e *celExpr) *celType {
+	target := c.check(e.target)
+	switch target.kind {
+	case celDyn:
+		return celDynType
+	case celMap:
+		if !celAssignable(target.key, celStringType) {
+			c.errorf(e.pos, "type '%s' does not support field selection", target)
+			return celDynType
+		}
+		return target.elem
+	case celObject:
+		if t, ok := iamConditionObjects[target.name][e.name]; ok {
+			return t
+		}
+		c.errorf(e.pos, "undefined field '%s'", e.name)
+		return celDynType
+	}
+	c.errorf(e.pos, "type '%s' does not support field selection", target)
+	return celDynType
+}
+
+var celComprehensionMacros = map[string]bool{
+	"all": true, "exists": true, "exists_one": true, "filter": true, "map": true,
+}
+
+func (c *celChecker) checkCall(e *celExpr) *celType {
+	if e.target == nil && e.name == "has" {
+		if len(e.args) != 1 || e.args[0].kind != celExprSelect {
+			c.errorf(e.pos, "invalid argument to has() macro")
+			return celBoolType
+		}
+		c.checkSelect(e.args[0])
+		return celBoolType
+	}
+
+	if e.target != nil && celComprehensionMacros[e.name] && len(e.args) >= 2 && e.args[0].kind == celExprIdent {
+		return c.checkComprehension(e)
+	}
+
+	if strings.HasPrefix(e.name, "_") || strings.HasPrefix(e.name, "@") || e.name == "!_" || e.name == "-_" {
+		args := make([]*celType, len(e.args))
+		for i, a := range e.args {
+			args[i] = c.check(a)
+		}
+		return c.checkOperator(e, args)
+	}
+
+	var receiver *celType
+	if e.target != nil {
+		receiver = c.check(e.target)
+	}
+	args := make([]*celType, len(e.args))
+	for i, a := range e.args {
+		args[i] = c.check(a)
+	}
+
+	overloads, ok := iamConditionFunctions[e.name]
+	if !ok {
+		c.warnf(e.pos, "undeclared reference to '%s'", e.name)
+		return celDynType
+	}
+	for _, o := range overloads {
+		if (o.receiver == nil) != (receiver == nil) || len(o.args) != len(args) {
+			continue
+		}
+		if receiver != nil && !celAssignable(o.receiver, receiver) {
+			continue
+		}
+		matched := true
+		for i := range args {
+			if !celAssignable(o.args[i], args[i]) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return o.result
+		}
+	}
+
+	c.errorf(e.pos, "found no matching overload for '%s' applied to '%s'", e.name, celSignature(receiver, args))
+	return celDynType
+}
+
+func celSignature(receiver *celType, args []*celType) string {
+	names := make([]string, len(args))
+	for i, a := range args {
+		names[i] = a.String()
+	}
+	sig := "(" + strings.Join(names, ", ") + ")"
+	if receiver != nil {
+		sig = receiver.String() + "." + sig
+	}
+	return sig
+}
+
+func (c *celChecker) checkComprehension(e *celExpr) *celType {
+	target := c.check(e.target)
+	var elem *celType
+	switch target.kind {
+	case celList:
+		elem = target.elem
+	case celMap:
+		elem = target.key
+	case celDyn:
+		elem = celDynType
+	default:
+		c.errorf(e.pos, "expression of type '%s' cannot be range of a comprehension (must be list, map, or dynamic)", target)
+		elem = celDynType
+	}
+
+	c.scopes = append(c.scopes, map[string]*celType{e.args[0].name: elem})
+	defer func() { c.scopes = c.scopes[:len(c.scopes)-1] }()
+
+	checkPredicate := func(p *celExpr) {
+		if t := c.check(p); !celAssignable(celBoolType, t) {
+			c.errorf(p.pos, "expected type 'bool' but found '%s'", t)
+		}
+	}
+
+	switch e.name {
+	case "all", "exists", "exists_one":
+		if len(e.args) != 2 {
+			c.errorf(e.pos, "%s() macro expects 2 arguments", e.name)
+			return celBoolType
+		}
+		checkPredicate(e.args[1])
+		return celBoolType
+	case "filter":
+		if len(e.args) != 2 {
+			c.errorf(e.pos, "filter() macro expects 2 arguments")
+			return celListOf(elem)
+		}
+		checkPredicate(e.args[1])
+		return celListOf(elem)
+	}
+
+	// map(x, transform) or map(x, filter, transform)
+	if len(e.args) == 3 {
+		checkPredicate(e.args[1])
+		return celListOf(c.check(e.args[2]))
+	}
+	if len(e.args) != 2 {
+		c.errorf(e.pos, "map() macro expects 2 or 3 arguments")
+		return celListOf(celDynType)
+	}
+	return celListOf(c.check(e.args[1]))
+}
+
+func celIsNumeric(t *celType) bool {
+	return t.kind == celInt || t.kind == celUint || t.kind == celDouble
+}
+
+func (c *celChecker) checkOperator(e *celExpr, args []*celType) *celType {
+	anyDyn := false
+	for _, a := range args {
+		if a.kind == celDyn {
+			anyDyn = true
+		}
+	}
+	noOverload := func(result *celType) *celType {
+		c.errorf(e.pos, "found no matching overload for '%s' applied to '%s'", e.name, celSignature(nil, args))
+		return result
+	}
+
+	switch e.name {
+	case "!_":
+		if !celAssignable(celBoolType, args[0]) {
+			return noOverload(celBoolType)
+		}
+		return celBoolType
+	case "-_":
+		if anyDyn {
+			return celDynType
+		}
+		if args[0].kind != celInt && args[0].kind != celDouble && args[0].kind != celDuration {
+			return noOverload(celDynType)
+		}
+		return args[0]
+	case "_&&_", "_||_":
+		if !celAssignable(celBoolType, args[0]) || !celAssignable(celBoolType, args[1]) {
+			return noOverload(celBoolType)
+		}
+		return celBoolType
+	case "_?_:_":
+		if !celAssignable(celBoolType, args[0]) {
+			c.errorf(e.args[0].pos, "expected type 'bool' but found '%s'", args[0])
+		}
+		return celJoin(args[1], args[2])
+	case "_==_", "_!=_":
+		if args[0].kind == celNull || args[1].kind == celNull || celAssignable(args[0], args[1]) {
+			return celBoolType
+		}
+		return noOverload(celBoolType)
+	case "_<_", "_<=_", "_>_", "_>=_":
+		if anyDyn {
+			return celBoolType
+		}
+		switch args[0].kind {
+		case celBool, celInt, celUint, celDouble, celString, celBytes, celTimestamp, celDuration:
+			if args[0].kind == args[1].kind {
+				return celBoolType
+			}
+		}
+		return noOverload(celBoolType)
+	case "@in":
+		switch args[1].kind {
+		case celDyn:
+			return celBoolType
+		case celList:
+			if celAssignable(args[1].elem, args[0]) {
+				return celBoolType
+			}
+		case celMap:
+			if celAssignable(args[1].key, args[0]) {
+				return celBoolType
+			}
+		}
+		return noOverload(celBoolType)
+	case "_[_]":
+		switch args[0].kind {
+		case celDyn:
+			return celDynType
+		case celList:
+			if celAssignable(celIntType, args[1]) || celAssignable(celUintType, args[1]) {
+				return args[0].elem
+			}
+		case celMap:
+			if celAssignable(args[0].key, args[1]) {
+				return args[0].elem
+			}
+		}
+		return noOverload(celDynType)
+	case "_+_":
+		if anyDyn {
+			return celDynType
+		}
+		a, b := args[0], args[1]
+		switch {
+		case a.kind == celTimestamp && b.kind == celDuration, a.kind == celDuration && b.kind == celTimestamp:
+			return celTimestampType
+		case a.kind == b.kind && (celIsNumeric(a) || a.kind == celString || a.kind == celBytes || a.kind == celDuration):
+			return a
+		case a.kind == celList && b.kind == celList:
+			return celListOf(celJoin(a.elem, b.elem))
+		}
+		return noOverload(celDynType)
+	case "_-_":
+		if anyDyn {
+			return celDynType
+		}
+		a, b := args[0], args[1]
+		switch {
+		case a.kind == celTimestamp && b.kind == celTimestamp:
+			return celDurationType
+		case a.kind == celTimestamp && b.kind == celDuration:
+			return celTimestampType
+		case a.kind == b.kind && (celIsNumeric(a) || a.kind == celDuration):
+			return a
+		}
+		return noOverload(celDynType)
+	case "_*_", "_/_", "_%_":
+		if anyDyn {
+			return celDynType
+		}
+		if args[0].kind == args[1].kind && celIsNumeric(args[0]) && !(e.name == "_%_" && args[0].kind == celDouble) {
+			return args[0]
+		}
+		return noOverload(celDynType)
+	}
+	return celDynType
+}
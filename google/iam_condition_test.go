@@ -0,0 +1,142 @@
+package google
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateIamConditionExpression(t *testing.T) {
+	valid := []string{
+		`request.time < timestamp("2020-01-01T00:00:00Z")`,
+		`request.time < timestamp('2020-01-01T00:00:00Z') && resource.name.startsWith("projects/_/buckets/my-bucket")`,
+		`resource.type == "storage.googleapis.com/Object" || resource.service == "storage.googleapis.com"`,
+		`request.time.getHours("America/Los_Angeles") >= 9 && request.time.getDayOfWeek("America/Los_Angeles") <= 5`,
+		`request.time > request.time - duration("3600s")`,
+		`resource.name.extract("projects/{project}/") == "my-project"`,
+		`api.getAttribute("iam.googleapis.com/modifiedGrantsByRole", []).hasOnly(["roles/viewer"])`,
+		`"roles/viewer" in api.getAttribute('iam.googleapis.com/modifiedGrantsByRole', [])`,
+		`resource.matchTag("12345678/env", "prod") && !resource.hasTagKey("12345678/team")`,
+		`resource.matchTagId("tagKeys/123", "tagValues/456")`,
+		`"accessPolicies/123/accessLevels/trusted" in request.auth.access_levels`,
+		`destination.port == 22 && destination.ip != "10.0.0.1"`,
+		`request.host == "example.com" && request.path.matches("^/admin/.*")`,
+		`has(request.auth) ? size(request.auth.access_levels) > 0 : false`,
+		`["a", "b"].exists(x, resource.name.endsWith(x))`,
+		"request.time < timestamp(\"2020-01-01T00:00:00Z\") // expires\n&& true",
+		`r"raw\d" != '' && b'bytes' == b"bytes" && 1u < 2u && 1.5 < 2e3 && -1 < 0`,
+		`{"a": 1}["a"] == 1 && [1, 2, 3][0] == 1`,
+		`true`,
+		`request.auth.claims.googleSignedIn.email == "user@example.com"`,
+	}
+	for _, expr := range valid {
+		warnings, err := validateIamConditionExpression(expr)
+		if err != nil {
+			t.Errorf("expected %q to be valid, got: %s", expr, err)
+		}
+		if len(warnings) > 0 {
+			t.Errorf("expected %q to be checked without warnings, got: %v", expr, warnings)
+		}
+	}
+
+	// Functions that aren't documented are dynamic, with a warning.
+	unchecked := map[string]string{
+		`resource.name.beginsWith("projects/")`:                                   "undeclared reference to 'beginsWith'",
+		`request.auth.claims.foo.bar(1) && resource.name.startsWith("projects/")`: "undeclared reference to 'bar'",
+	}
+	for expr, message := range unchecked {
+		warnings, err := validateIamConditionExpression(expr)
+		if err != nil {
+			t.Errorf("expected %q to be valid, got: %s", expr, err)
+		}
+		if len(warnings) != 1 || !strings.Contains(warnings[0], message) {
+			t.Errorf("expected %q to warn about %q, got: %v", expr, message, warnings)
+		}
+	}
+
+	invalid := map[string]struct {
+		expression string
+		message    string
+		position   string
+	}{
+		"unterminated call": {
+			expression: `request.time < timestamp("2020-01-01T00:00:00Z"`,
+			message:    "Syntax error: mismatched input '<EOF>' expecting {',', ')'}",
+			position:   "<input>:1:48",
+		},
+		"unterminated string": {
+			expression: `resource.name.startsWith("projects/)`,
+			message:    "unterminated string literal",
+			position:   "<input>:1:26",
+		},
+		"undeclared variable": {
+			expression: `requst.time < timestamp("2020-01-01T00:00:00Z")`,
+			message:    "undeclared reference to 'requst'",
+			position:   "<input>:1:1",
+		},
+		"undefined field": {
+			expression: `request.tme < timestamp("2020-01-01T00:00:00Z")`,
+			message:    "undefined field 'tme'",
+			position:   "<input>:1:9",
+		},
+		"undefined nested field": {
+			expression: `request.auth.iap.level == 2`,
+			message:    "undefined field 'iap'",
+			position:   "<input>:1:14",
+		},
+		"mismatched comparison": {
+			expression: `request.time < "2020-01-01T00:00:00Z"`,
+			message:    "found no matching overload for '_<_' applied to '(google.protobuf.Timestamp, string)'",
+			position:   "<input>:1:14",
+		},
+		"mismatched argument": {
+			expression: `resource.name.startsWith(1)`,
+			message:    "found no matching overload for 'startsWith' applied to 'string.(int)'",
+			position:   "<input>:1:15",
+		},
+		"not a bool": {
+			expression: `resource.name`,
+			message:    "expression must evaluate to bool, found 'string'",
+		},
+		"second line": {
+			expression: "request.time < timestamp(\"2020-01-01T00:00:00Z\") &&\n  resource.name == 1",
+			message:    "found no matching overload for '_==_' applied to '(string, int)'",
+			position:   "<input>:2:17",
+		},
+		"reserved identifier": {
+			expression: `if == true`,
+			message:    "reserved identifier: if",
+			position:   "<input>:1:1",
+		},
+		"dangling operator": {
+			expression: `resource.name == "x" &&`,
+			message:    "Syntax error: mismatched input '<EOF>'",
+			position:   "<input>:1:24",
+		},
+	}
+	for tn, tc := range invalid {
+		_, err := validateIamConditionExpression(tc.expression)
+		if err == nil {
+			t.Errorf("%s: expected %q to be invalid", tn, tc.expression)
+			continue
+		}
+		if !strings.Contains(err.Error(), tc.message) {
+			t.Errorf("%s: expected error containing %q, got: %s", tn, tc.message, err)
+		}
+		if !strings.Contains(err.Error(), tc.position) {
+			t.Errorf("%s: expected error at %q, got: %s", tn, tc.position, err)
+		}
+	}
+}
+
+func TestIamConditionErrorCaret(t *testing.T) {
+	_, err := validateIamConditionExpression(`request.time < timestamp("2020-01-01T00:00:00Z") && requst.time != null`)
+	if err == nil {
+		t.Fatal("expected error, got none")
+	}
+	expected := "ERROR: <input>:1:53: undeclared reference to 'requst'\n" +
+		" | request.time < timestamp(\"2020-01-01T00:00:00Z\") && requst.time != null\n" +
+		" | ....................................................^"
+	if err.Error() != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, err)
+	}
+}
@@ -0,0 +1,226 @@
+package google
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const (
+	iamConflictKindPolicy  = "policy"
+	iamConflictKindBinding = "binding"
+	iamConflictKindMember  = "member"
+)
+
+type iamConflictEntry struct {
+	kind      string
+	role      string
+	member    string
+	condition conditionKey
+	// the bindings a policy or binding resource sets authoritatively, so that
+	// two such resources for the same policy or role can be told apart
+	authoritative string
+	// describes the resource the policy belongs to, e.g. project "my-project"
+	resource string
+	// the id of the IAM resource, if it already exists
+	id string
+}
+
+func (e iamConflictEntry) String() string {
+	var s string
+	switch e.kind {
+	case iamConflictKindPolicy:
+		s = fmt.Sprintf("the _iam_policy resource for %s", e.resource)
+	case iamConflictKindBinding:
+		s = fmt.Sprintf("the _iam_binding resource for role %q%s on %s", e.role, e.conditionString(), e.resource)
+	default:
+		s = fmt.Sprintf("the _iam_member resource for member %q and role %q%s on %s", e.member, e.role, e.conditionString(), e.resource)
+	}
+	if e.id != "" {
+		s += fmt.Sprintf(" (id %q)", e.id)
+	}
+	return s
+}
+
+func (e iamConflictEntry) conditionString() string {
+	if e.condition.Empty() {
+		return ""
+	}
+	return fmt.Sprintf(" with condition %q", e.condition.Title)
+}
+
+// sameResource returns whether both entries were registered by the same
+// resource, planned again. The id isn't compared, as it's unknown when a
+// resource is planned again for replacement.
+func (e iamConflictEntry) sameResource(o iamConflictEntry) bool {
+	e.id, o.id = "", ""
+	return e == o
+}
+
+// conflictsWith returns whether one of the entries manages the bindings of the
+// other authoritatively. Members never conflict with each other.
+func (e iamConflictEntry) conflictsWith(o iamConflictEntry) bool {
+	if e.sameResource(o) {
+		return false
+	}
+	if e.kind == iamConflictKindPolicy || o.kind == iamConflictKindPolicy {
+		return true
+	}
+	if e.kind == iamConflictKindMember && o.kind == iamConflictKindMember {
+		return false
+	}
+	return e.role == o.role && e.condition == o.condition
+}
+
+// iamConflictRegistry records which IAM resources were planned against which
+// policy, so authoritative and non-authoritative resources fighting over the
+// same bindings can be reported. Terraform configures the provider for every
+// operation, so the registry is scoped to a single plan or apply.
+type iamConflictRegistry struct {
+	sync.Mutex
+	// mutex key -> entries
+	entries map[string][]iamConflictEntry
+}
+
+func newIamConflictRegistry() *iamConflictRegistry {
+	return &iamConflictRegistry{
+		entries: make(map[string][]iamConflictEntry),
+	}
+}
+
+// register records the entry for the policy identified by mutexKey and returns
+// the first previously registered entry it conflicts with, if any.
+func (r *iamConflictRegistry) register(mutexKey string, entry iamConflictEntry) *iamConflictEntry {
+	r.Lock()
+	defer r.Unlock()
+	var conflict *iamConflictEntry
+	registered := false
+	for i, e := range r.entries[mutexKey] {
+		if e.sameResource(entry) {
+			registered = true
+			if e.id == "" {
+				r.entries[mutexKey][i].id = entry.id
+			}
+			continue
+		}
+		if conflict == nil && entry.conflictsWith(e) {
+			e := e
+			conflict = &e
+		}
+	}
+	if !registered {
+		r.entries[mutexKey] = append(r.entries[mutexKey], entry)
+	}
+	return conflict
+}
+
+// iamConflictCustomizeDiff registers the IAM resource being planned and fails
+// the plan if an authoritative resource manages bindings that another resource
+// manages too. The check is opt-in with the detect_iam_conflicts provider
+// field. Resources whose policy or bindings can't be known at plan time are
+// skipped.
+func iamConflictCustomizeDiff(kind string, parentSpecificSchema map[string]*schema.Schema, newUpdaterFunc newResourceIamUpdaterFunc) schema.CustomizeDiffFunc {
+	return func(_ context.Context, diff *schema.ResourceDiff, meta interface{}) error {
+		config := meta.(*Config)
+		if !config.DetectIamConflicts || config.iamResourcesInConfig == nil {
+			return nil
+		}
+
+		entry := iamConflictEntry{kind: kind, id: diff.Id()}
+		switch kind {
+		case iamConflictKindPolicy:
+			if !diff.NewValueKnown("policy_data") {
+				return nil
+			}
+			entry.authoritative = diff.Get("policy_data").(string)
+		case iamConflictKindBinding:
+			if !diff.NewValueKnown("members") {
+				return nil
+			}
+			members := convertStringArr(diff.Get("members").(*schema.Set).List())
+			sort.Strings(members)
+			entry.authoritative = strings.Join(members, ",")
+		}
+		if kind != iamConflictKindPolicy {
+			if !diff.NewValueKnown("role") || !diff.NewValueKnown("condition") {
+				return nil
+			}
+			entry.role = diff.Get("role").(string)
+			entry.condition = conditionKeyFromCondition(expandIamCondition(diff.Get("condition")))
+		}
+		if kind == iamConflictKindMember {
+			if !diff.NewValueKnown("member") {
+				return nil
+			}
+			entry.member = diff.Get("member").(string)
+		}
+
+		fields := &iamDiffResourceData{fields: make(map[string]interface{})}
+		for k := range parentSpecificSchema {
+			if !diff.NewValueKnown(k) {
+				return nil
+			}
+			if v, ok := diff.GetOk(k); ok {
+				fields.fields[k] = v
+			}
+		}
+		updater, err := newUpdaterFunc(fields, config)
+		if err != nil {
+			log.Printf("[DEBUG] Skipping IAM conflict detection, unable to resolve IAM policy: %s", err)
+			return nil
+		}
+		entry.resource = updater.DescribeResource()
+
+		if conflict := config.iamResourcesInConfig.register(updater.GetMutexKey(), entry); conflict != nil {
+			return fmt.Errorf("Conflicting IAM resources in the same configuration: %s conflicts with %s. When an "+
+				"authoritative IAM resource manages the same bindings as another IAM resource, they overwrite each "+
+				"other on every apply. Manage these bindings with a single IAM resource, or unset detect_iam_conflicts in the "+
+				"provider to skip this check.", entry, conflict)
+		}
+		return nil
+	}
+}
+
+// iamDiffResourceData exposes the parent-specific fields of a resource being
+// planned to the IAM updater constructors.
+type iamDiffResourceData struct {
+	fields map[string]interface{}
+}
+
+func (f *iamDiffResourceData) HasChange(string) bool { return false }
+
+func (f *iamDiffResourceData) GetOkExists(k string) (interface{}, bool) {
+	v, ok := f.fields[k]
+	return v, ok
+}
+
+func (f *iamDiffResourceData) GetOk(k string) (interface{}, bool) {
+	v, ok := f.fields[k]
+	return v, ok && v != ""
+}
+
+func (f *iamDiffResourceData) Get(k string) interface{} {
+	if v, ok := f.fields[k]; ok {
+		return v
+	}
+	return ""
+}
+
+func (f *iamDiffResourceData) Set(k string, v interface{}) error {
+	f.fields[k] = v
+	return nil
+}
+
+func (f *iamDiffResourceData) SetId(string) {}
+
+func (f *iamDiffResourceData) Id() string { return "" }
+
+func (f *iamDiffResourceData) GetProviderMeta(interface{}) error { return nil }
+
+func (f *iamDiffResourceData) Timeout(string) time.Duration { return 0 }
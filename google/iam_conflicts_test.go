@@ -0,0 +1,169 @@
+package google
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestIamConflictRegistry(t *testing.T) {
+	project := `project "my-project"`
+	policy := iamConflictEntry{kind: iamConflictKindPolicy, authoritative: `{"bindings":[]}`, resource: project}
+	otherPolicy := iamConflictEntry{kind: iamConflictKindPolicy, authoritative: `{"bindings":[{"role":"roles/viewer"}]}`, resource: project}
+	binding := iamConflictEntry{kind: iamConflictKindBinding, role: "roles/viewer", authoritative: "user:a@example.com", resource: project}
+	otherBinding := iamConflictEntry{kind: iamConflictKindBinding, role: "roles/viewer", authoritative: "user:b@example.com", resource: project}
+	existingBinding := binding
+	existingBinding.id = "my-project/roles/viewer"
+	conditionalBinding := iamConflictEntry{kind: iamConflictKindBinding, role: "roles/viewer", condition: conditionKey{Title: "expires", Expression: "true"}, resource: project}
+	member := iamConflictEntry{kind: iamConflictKindMember, role: "roles/viewer", member: "user:a@example.com", resource: project}
+	otherMember := iamConflictEntry{kind: iamConflictKindMember, role: "roles/viewer", member: "user:b@example.com", resource: project}
+	editorMember := iamConflictEntry{kind: iamConflictKindMember, role: "roles/editor", member: "user:a@example.com", resource: project}
+
+	cases := map[string]struct {
+		registered []iamConflictEntry
+		entry      iamConflictEntry
+		conflict   *iamConflictEntry
+	}{
+		"members": {
+			registered: []iamConflictEntry{member},
+			entry:      otherMember,
+		},
+		"binding and member for another role": {
+			registered: []iamConflictEntry{binding},
+			entry:      editorMember,
+		},
+		"binding and member for the same role": {
+			registered: []iamConflictEntry{editorMember, binding},
+			entry:      member,
+			conflict:   &binding,
+		},
+		"member and binding for the same role": {
+			registered: []iamConflictEntry{member},
+			entry:      binding,
+			conflict:   &member,
+		},
+		"binding and member with different conditions": {
+			registered: []iamConflictEntry{conditionalBinding},
+			entry:      member,
+		},
+		"policy and member": {
+			registered: []iamConflictEntry{policy},
+			entry:      editorMember,
+			conflict:   &policy,
+		},
+		"member and policy": {
+			registered: []iamConflictEntry{editorMember},
+			entry:      policy,
+			conflict:   &editorMember,
+		},
+		"planned again": {
+			registered: []iamConflictEntry{binding, otherMember},
+			entry:      binding,
+			conflict:   &otherMember,
+		},
+		"policy planned again": {
+			registered: []iamConflictEntry{policy},
+			entry:      policy,
+		},
+		"binding planned again for replacement": {
+			registered: []iamConflictEntry{existingBinding},
+			entry:      binding,
+		},
+		"policies": {
+			registered: []iamConflictEntry{policy},
+			entry:      otherPolicy,
+			conflict:   &policy,
+		},
+		"bindings for the same role": {
+			registered: []iamConflictEntry{existingBinding},
+			entry:      otherBinding,
+			conflict:   &existingBinding,
+		},
+	}
+	for tn, tc := range cases {
+		r := newIamConflictRegistry()
+		for _, e := range tc.registered {
+			r.register("iam-project-my-project", e)
+		}
+		// entries for other policies never conflict
+		r.register("iam-project-other-project", policy)
+
+		conflict := r.register("iam-project-my-project", tc.entry)
+		if tc.conflict == nil {
+			if conflict != nil {
+				t.Errorf("%s: expected no conflict, got %s", tn, conflict)
+			}
+			continue
+		}
+		if conflict == nil || *conflict != *tc.conflict {
+			t.Errorf("%s: expected conflict with %s, got %v", tn, tc.conflict, conflict)
+		}
+	}
+}
+
+func TestIamConflictEntryString(t *testing.T) {
+	cases := map[string]iamConflictEntry{
+		`the _iam_policy resource for project "my-project"`: {
+			kind:     iamConflictKindPolicy,
+			resource: `project "my-project"`,
+		},
+		`the _iam_binding resource for role "roles/viewer" with condition "expires" on project "my-project" (id "my-project/roles/viewer")`: {
+			kind:      iamConflictKindBinding,
+			role:      "roles/viewer",
+			condition: conditionKey{Title: "expires", Expression: "true"},
+			resource:  `project "my-project"`,
+			id:        "my-project/roles/viewer",
+		},
+		`the _iam_member resource for member "user:a@example.com" and role "roles/viewer" on project "my-project"`: {
+			kind:     iamConflictKindMember,
+			role:     "roles/viewer",
+			member:   "user:a@example.com",
+			resource: `project "my-project"`,
+		},
+	}
+	for expected, e := range cases {
+		if got := e.String(); got != expected {
+			t.Errorf("expected %q, got %q", expected, got)
+		}
+	}
+}
+
+func TestAccIamConflicts_bindingAndMember(t *testing.T) {
+	t.Parallel()
+
+	// The plan fails before any resource is created, so the project doesn't
+	// need to exist.
+	pid := fmt.Sprintf("tf-test-%d", randInt(t))
+	vcrTest(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccIamConflicts_bindingAndMember(pid),
+				ExpectError: regexp.MustCompile(`_iam_(member|binding) resource for (member "user:admin@hashicorptest.com" and )?role "roles/viewer" on project "` + pid + `" conflicts with`),
+			},
+		},
+	})
+}
+
+func testAccIamConflicts_bindingAndMember(pid string) string {
+	return fmt.Sprintf(`
+provider "google" {
+  detect_iam_conflicts = true
+}
+
+resource "google_project_iam_binding" "viewers" {
+  project = "%s"
+  role    = "roles/viewer"
+  members = ["user:admin@hashicorptest.com"]
+}
+
+resource "google_project_iam_member" "viewer" {
+  project = "%s"
+  role    = "roles/viewer"
+  member  = "user:admin@hashicorptest.com"
+}
+`, pid, pid)
+}
@@ -0,0 +1,296 @@
+package google
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/errwrap"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"google.golang.org/api/cloudresourcemanager/v1"
+)
+
+var iamFullResourceNameRegex = regexp.MustCompile(`^//([^/]+)/(.+)$`)
+
+var IamResourceNameSchema = map[string]*schema.Schema{
+	"resource_name": {
+		Type:         schema.TypeString,
+		Required:     true,
+		ForceNew:     true,
+		ValidateFunc: validation.StringMatch(iamFullResourceNameRegex, "must be a full resource name like //pubsub.googleapis.com/projects/my-project/topics/my-topic"),
+	},
+	"api_version": {
+		Type:     schema.TypeString,
+		Optional: true,
+		ForceNew: true,
+		Default:  "v1",
+	},
+}
+
+// iamResourceNameFactory resolves full resource names of one service to the
+// updater of the typed IAM resources, so both share mutex and batching keys.
+// The named groups of pattern are the fields the updater reads.
+type iamResourceNameFactory struct {
+	pattern    *regexp.Regexp
+	newUpdater newResourceIamUpdaterFunc
+}
+
+// iamResourceNameFactories is keyed by the service name of a full resource
+// name. Resource names that match no factory fall back to the standard
+// :getIamPolicy/:setIamPolicy REST methods of the service.
+var iamResourceNameFactories = map[string][]iamResourceNameFactory{
+	"cloudkms.googleapis.com": {
+		{regexp.MustCompile(`^(?P<crypto_key_id>projects/[^/]+/locations/[^/]+/keyRings/[^/]+/cryptoKeys/[^/]+)$`), NewKmsCryptoKeyIamUpdater},
+		{regexp.MustCompile(`^(?P<key_ring_id>projects/[^/]+/locations/[^/]+/keyRings/[^/]+)$`), NewKmsKeyRingIamUpdater},
+	},
+	"cloudresourcemanager.googleapis.com": {
+		{regexp.MustCompile(`^projects/(?P<project>[^/]+)$`), NewProjectIamUpdater},
+		{regexp.MustCompile(`^(?P<folder>folders/[^/]+)$`), NewFolderIamUpdater},
+		{regexp.MustCompile(`^organizations/(?P<org_id>[^/]+)$`), NewOrganizationIamUpdater},
+	},
+	"iam.googleapis.com": {
+		{regexp.MustCompile(`^(?P<service_account_id>projects/[^/]+/serviceAccounts/[^/]+)$`), NewServiceAccountIamUpdater},
+	},
+	"pubsub.googleapis.com": {
+		{regexp.MustCompile(`^projects/(?P<project>[^/]+)/topics/(?P<topic>[^/]+)$`), PubsubTopicIamUpdaterProducer},
+		{regexp.MustCompile(`^projects/(?P<project>[^/]+)/subscriptions/(?P<subscription>[^/]+)$`), NewPubsubSubscriptionIamUpdater},
+	},
+	"secretmanager.googleapis.com": {
+		{regexp.MustCompile(`^projects/(?P<project>[^/]+)/secrets/(?P<secret_id>[^/]+)$`), SecretManagerSecretIamUpdaterProducer},
+	},
+	"storage.googleapis.com": {
+		{regexp.MustCompile(`^projects/_/buckets/(?P<bucket>[^/]+)$`), StorageBucketIamUpdaterProducer},
+	},
+}
+
+// iamResourceNameFields stands in for the ResourceData of a typed IAM
+// resource, exposing the fields parsed out of a full resource name.
+type iamResourceNameFields struct {
+	TerraformResourceData
+	fields map[string]interface{}
+}
+
+func (f *iamResourceNameFields) HasChange(string) bool { return false }
+
+func (f *iamResourceNameFields) GetOkExists(k string) (interface{}, bool) {
+	v, ok := f.fields[k]
+	return v, ok
+}
+
+func (f *iamResourceNameFields) GetOk(k string) (interface{}, bool) {
+	v, ok := f.fields[k]
+	return v, ok && v != ""
+}
+
+func (f *iamResourceNameFields) Get(k string) interface{} {
+	if v, ok := f.fields[k]; ok {
+		return v
+	}
+	return ""
+}
+
+func (f *iamResourceNameFields) Set(k string, v interface{}) error {
+	f.fields[k] = v
+	return nil
+}
+
+func (f *iamResourceNameFields) SetId(string) {}
+
+// ResourceNameIamUpdater wraps the updater resolved for a full resource name
+// so resources are identified by the full resource name.
+type ResourceNameIamUpdater struct {
+	ResourceIamUpdater
+	resourceName string
+}
+
+func (u *ResourceNameIamUpdater) GetResourceId() string {
+	return u.resourceName
+}
+
+func IamResourceNameUpdaterProducer(d TerraformResourceData, config *Config) (ResourceIamUpdater, error) {
+	resourceName := d.Get("resource_name").(string)
+	service, path, err := parseIamFullResourceName(resourceName)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, f := range iamResourceNameFactories[service] {
+		m := f.pattern.FindStringSubmatch(path)
+		if m == nil {
+			continue
+		}
+		fields := &iamResourceNameFields{
+			TerraformResourceData: d,
+			fields:                make(map[string]interface{}),
+		}
+		for i, name := range f.pattern.SubexpNames() {
+			if name != "" {
+				fields.fields[name] = m[i]
+			}
+		}
+		updater, err := f.newUpdater(fields, config)
+		if err != nil {
+			return nil, err
+		}
+		return &ResourceNameIamUpdater{ResourceIamUpdater: updater, resourceName: resourceName}, nil
+	}
+
+	return &RestIamUpdater{
+		resourceName: resourceName,
+		service:      service,
+		path:         path,
+		apiVersion:   d.Get("api_version").(string),
+		d:            d,
+		Config:       config,
+	}, nil
+}
+
+func IamResourceNameIdParseFunc(d *schema.ResourceData, config *Config) error {
+	if _, _, err := parseIamFullResourceName(d.Id()); err != nil {
+		return err
+	}
+	if err := d.Set("resource_name", d.Id()); err != nil {
+		return fmt.Errorf("Error setting resource_name: %s", err)
+	}
+	if err := d.Set("api_version", "v1"); err != nil {
+		return fmt.Errorf("Error setting api_version: %s", err)
+	}
+	return nil
+}
+
+func parseIamFullResourceName(resourceName string) (string, string, error) {
+	m := iamFullResourceNameRegex.FindStringSubmatch(resourceName)
+	if m == nil {
+		return "", "", fmt.Errorf("Invalid full resource name %q, expected a name like //pubsub.googleapis.com/projects/my-project/topics/my-topic", resourceName)
+	}
+	return m[1], strings.TrimSuffix(m[2], "/"), nil
+}
+
+// RestIamUpdater manages the IAM policy of any resource exposing the
+// standard :getIamPolicy and :setIamPolicy methods.
+type RestIamUpdater struct {
+	resourceName string
+	service      string
+	path         string
+	apiVersion   string
+	d            TerraformResourceData
+	Config       *Config
+}
+
+func (u *RestIamUpdater) GetResourceIamPolicy() (*cloudresourcemanager.Policy, error) {
+	userAgent, err := generateUserAgentString(u.d, u.Config.userAgent)
+	if err != nil {
+		return nil, err
+	}
+
+	// Services declare getIamPolicy as either GET or POST, so try GET first and
+	// POST if the method isn't found.
+	url := fmt.Sprintf("%s?options.requestedPolicyVersion=%d", u.qualifyUrl("getIamPolicy"), iamPolicyVersion)
+	policy, err := sendRequest(u.Config, "GET", u.billingProject(), url, userAgent, nil)
+	if isGoogleApiErrorWithCode(err, 404) || isGoogleApiErrorWithCode(err, 405) {
+		obj := map[string]interface{}{
+			"options": map[string]interface{}{
+				"requestedPolicyVersion": iamPolicyVersion,
+			},
+		}
+		policy, err = sendRequest(u.Config, "POST", u.billingProject(), u.qualifyUrl("getIamPolicy"), userAgent, obj)
+	}
+	if err != nil {
+		return nil, errwrap.Wrapf(fmt.Sprintf("Error retrieving IAM policy for %s: {{err}}", u.DescribeResource()), err)
+	}
+
+	out := &cloudresourcemanager.Policy{}
+	err = Convert(policy, out)
+	if err != nil {
+		return nil, errwrap.Wrapf("Cannot convert a policy to a resource manager policy: {{err}}", err)
+	}
+
+	return out, nil
+}
+
+func (u *RestIamUpdater) SetResourceIamPolicy(policy *cloudresourcemanager.Policy) error {
+	json, err := ConvertToMap(policy)
+	if err != nil {
+		return err
+	}
+
+	obj := make(map[string]interface{})
+	obj["policy"] = json
+
+	userAgent, err := generateUserAgentString(u.d, u.Config.userAgent)
+	if err != nil {
+		return err
+	}
+
+	_, err = sendRequestWithTimeout(u.Config, "POST", u.billingProject(), u.qualifyUrl("setIamPolicy"), userAgent, obj, 5*time.Minute)
+	if err != nil {
+		return errwrap.Wrapf(fmt.Sprintf("Error setting IAM policy for %s: {{err}}", u.DescribeResource()), err)
+	}
+
+	return nil
+}
+
+func (u *RestIamUpdater) qualifyUrl(methodIdentifier string) string {
+	basePath := iamServiceBasePath(u.Config, u.service, u.apiVersion)
+	if basePath == "" {
+		basePath = fmt.Sprintf("https://%s/", u.service)
+	}
+	return fmt.Sprintf("%s%s/%s:%s", basePath, u.apiVersion, u.path, methodIdentifier)
+}
+
+// iamServiceBasePath returns the base path, without its version, the provider
+// is configured with for service, preferring the one of the given version
+// when there are several. It returns "" if the provider has none.
+func iamServiceBasePath(config *Config, service, apiVersion string) string {
+	keys := make([]string, 0, len(DefaultBasePaths))
+	for key := range DefaultBasePaths {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	basePath := ""
+	for _, key := range keys {
+		defaultUrl, err := url.Parse(DefaultBasePaths[key])
+		if err != nil || strings.Replace(defaultUrl.Host, ".mtls.googleapis.com", ".googleapis.com", 1) != service {
+			continue
+		}
+		field := reflect.ValueOf(config).Elem().FieldByName(key + "BasePath")
+		if !field.IsValid() || field.Kind() != reflect.String || field.String() == "" {
+			continue
+		}
+		if strings.HasSuffix(DefaultBasePaths[key], "/"+apiVersion+"/") {
+			return removeBasePathVersion(field.String())
+		}
+		if basePath == "" {
+			basePath = removeBasePathVersion(field.String())
+		}
+	}
+	return basePath
+}
+
+// billingProject returns the project in the resource name, falling back to
+// the provider project, for use with user project overrides.
+func (u *RestIamUpdater) billingProject() string {
+	parts := strings.Split(u.path, "/")
+	if len(parts) > 1 && parts[0] == "projects" {
+		return parts[1]
+	}
+	return u.Config.Project
+}
+
+func (u *RestIamUpdater) GetResourceId() string {
+	return u.resourceName
+}
+
+func (u *RestIamUpdater) GetMutexKey() string {
+	return fmt.Sprintf("iam-%s", u.resourceName)
+}
+
+func (u *RestIamUpdater) DescribeResource() string {
+	return fmt.Sprintf("resource %q", u.resourceName)
+}
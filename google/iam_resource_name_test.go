@@ -0,0 +1,143 @@
+package google
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestIamResourceNameUpdaterProducer(t *testing.T) {
+	config := &Config{
+		Project:                "default-project",
+		CloudFunctionsBasePath: "https://private.example.com/cloudfunctions/v1/",
+	}
+
+	cases := map[string]struct {
+		resourceName string
+		mutexKey     string
+		url          string
+		expectError  bool
+	}{
+		"pubsub topic": {
+			resourceName: "//pubsub.googleapis.com/projects/my-project/topics/my-topic",
+			mutexKey:     "iam-pubsub-topic-projects/my-project/topics/my-topic",
+		},
+		"project": {
+			resourceName: "//cloudresourcemanager.googleapis.com/projects/my-project",
+			mutexKey:     getProjectIamPolicyMutexKey("my-project"),
+		},
+		"secret": {
+			resourceName: "//secretmanager.googleapis.com/projects/my-project/secrets/my-secret",
+			mutexKey:     "iam-secretmanager-secret-projects/my-project/secrets/my-secret",
+		},
+		"unregistered": {
+			resourceName: "//run.googleapis.com/projects/my-project/locations/us-central1/services/my-service",
+			mutexKey:     "iam-//run.googleapis.com/projects/my-project/locations/us-central1/services/my-service",
+			url:          "https://run.googleapis.com/v1/projects/my-project/locations/us-central1/services/my-service:setIamPolicy",
+		},
+		"custom endpoint": {
+			resourceName: "//cloudfunctions.googleapis.com/projects/my-project/locations/us-central1/functions/my-function",
+			mutexKey:     "iam-//cloudfunctions.googleapis.com/projects/my-project/locations/us-central1/functions/my-function",
+			url:          "https://private.example.com/cloudfunctions/v1/projects/my-project/locations/us-central1/functions/my-function:setIamPolicy",
+		},
+		"not a full resource name": {
+			resourceName: "projects/my-project/topics/my-topic",
+			expectError:  true,
+		},
+	}
+
+	for tn, tc := range cases {
+		d := &ResourceDataMock{
+			FieldsInSchema: map[string]interface{}{
+				"resource_name": tc.resourceName,
+				"api_version":   "v1",
+			},
+		}
+		u, err := IamResourceNameUpdaterProducer(d, config)
+		if tc.expectError {
+			if err == nil {
+				t.Errorf("%s: expected error, got none", tn)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", tn, err)
+			continue
+		}
+
+		if got := u.GetResourceId(); got != tc.resourceName {
+			t.Errorf("%s: expected resource id %q, got %q", tn, tc.resourceName, got)
+		}
+		if got := u.GetMutexKey(); got != tc.mutexKey {
+			t.Errorf("%s: expected mutex key %q, got %q", tn, tc.mutexKey, got)
+		}
+		if tc.url != "" {
+			rest, ok := u.(*RestIamUpdater)
+			if !ok {
+				t.Errorf("%s: expected a REST updater, got %T", tn, u)
+				continue
+			}
+			if got := rest.qualifyUrl("setIamPolicy"); got != tc.url {
+				t.Errorf("%s: expected url %q, got %q", tn, tc.url, got)
+			}
+		}
+	}
+}
+
+func TestAccIamResourceMember_pubsubTopic(t *testing.T) {
+	t.Parallel()
+
+	context := map[string]interface{}{
+		"random_suffix": randString(t, 10),
+		"project":       getTestProjectFromEnv(),
+	}
+
+	vcrTest(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccIamResourceMember_pubsubTopic(context),
+			},
+			{
+				ResourceName:      "google_iam_resource_member.foo",
+				ImportStateId:     fmt.Sprintf("//pubsub.googleapis.com/projects/%s/topics/tf-test-topic-%s roles/viewer user:admin@hashicorptest.com", context["project"], context["random_suffix"]),
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				ResourceName:      "google_iam_resource_binding.foo",
+				ImportStateId:     fmt.Sprintf("//pubsub.googleapis.com/projects/%s/topics/tf-test-topic-%s roles/editor", context["project"], context["random_suffix"]),
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccIamResourceMember_pubsubTopic(context map[string]interface{}) string {
+	return Nprintf(`
+resource "google_pubsub_topic" "topic" {
+  name = "tf-test-topic-%{random_suffix}"
+}
+
+resource "google_iam_resource_member" "foo" {
+  resource_name = "//pubsub.googleapis.com/${google_pubsub_topic.topic.id}"
+  role          = "roles/viewer"
+  member        = "user:admin@hashicorptest.com"
+}
+
+resource "google_iam_resource_binding" "foo" {
+  resource_name = "//pubsub.googleapis.com/${google_pubsub_topic.topic.id}"
+  role          = "roles/editor"
+  members       = ["user:admin@hashicorptest.com"]
+}
+
+resource "google_pubsub_topic_iam_member" "typed" {
+  topic  = google_pubsub_topic.topic.name
+  role   = "roles/pubsub.publisher"
+  member = "user:admin@hashicorptest.com"
+}
+`, context)
+}
@@ -1,8 +1,10 @@
 package google
 
 import (
+	"context"
 	"fmt"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -216,3 +218,58 @@ func disableCryptoKeyRotation(cryptoKeyId *kmsCryptoKeyId, userAgent string, con
 
 	return err
 }
+
+// cryptoKeyVersionsToDisable returns the names of the enabled versions, other
+// than the primary one, beyond the max most recently created ones.
+func cryptoKeyVersionsToDisable(versions []*cloudkms.CryptoKeyVersion, primary string, max int) []string {
+	var enabled []*cloudkms.CryptoKeyVersion
+	for _, version := range versions {
+		if version.State == "ENABLED" {
+			enabled = append(enabled, version)
+		}
+	}
+	// Timestamps are RFC3339 in UTC, so they sort as strings.
+	sort.SliceStable(enabled, func(i, j int) bool {
+		return enabled[i].CreateTime > enabled[j].CreateTime
+	})
+
+	var result []string
+	for i, version := range enabled {
+		if i >= max && version.Name != primary {
+			result = append(result, version.Name)
+		}
+	}
+	return result
+}
+
+func listCryptoKeyVersionsToDisable(cryptoKeyId *kmsCryptoKeyId, max int, userAgent string, config *Config) ([]string, error) {
+	keyClient := config.NewKmsClient(userAgent).Projects.Locations.KeyRings.CryptoKeys
+
+	getCall := keyClient.Get(cryptoKeyId.cryptoKeyId())
+	if config.UserProjectOverride {
+		getCall.Header().Set("X-Goog-User-Project", cryptoKeyId.KeyRingId.Project)
+	}
+	key, err := getCall.Do()
+	if err != nil {
+		return nil, err
+	}
+	primary := ""
+	if key.Primary != nil {
+		primary = key.Primary.Name
+	}
+
+	var versions []*cloudkms.CryptoKeyVersion
+	listCall := keyClient.CryptoKeyVersions.List(cryptoKeyId.cryptoKeyId()).Filter("state=ENABLED")
+	if config.UserProjectOverride {
+		listCall.Header().Set("X-Goog-User-Project", cryptoKeyId.KeyRingId.Project)
+	}
+	err = listCall.Pages(context.Background(), func(resp *cloudkms.ListCryptoKeyVersionsResponse) error {
+		versions = append(versions, resp.CryptoKeyVersions...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return cryptoKeyVersionsToDisable(versions, primary, max), nil
+}
@@ -0,0 +1,949 @@
+package google
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// The typed schema of google_monitoring_dashboard covers the grid and mosaic
+// layouts with xyChart, scorecard and text widgets. Dashboards using other
+// features are authored with dashboard_json.
+
+func monitoringDashboardAggregationSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Optional:    true,
+		MaxItems:    1,
+		Description: `How the time series are aligned and combined.`,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"alignment_period": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					Description: `The alignment period, a duration in seconds such as "60s".`,
+				},
+				"per_series_aligner": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					Description: `The aligner applied to each time series, such as ALIGN_RATE.`,
+				},
+				"cross_series_reducer": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					Description: `The reducer combining the aligned time series, such as REDUCE_SUM.`,
+				},
+				"group_by_fields": {
+					Type:        schema.TypeList,
+					Optional:    true,
+					Elem:        &schema.Schema{Type: schema.TypeString},
+					Description: `The fields preserved when the time series are reduced.`,
+				},
+			},
+		},
+	}
+}
+
+func monitoringDashboardTimeSeriesQuerySchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Required:    true,
+		MaxItems:    1,
+		Description: `The query fetching the time series. Exactly one of time_series_filter, time_series_query_language or prometheus_query_language must be set.`,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"time_series_filter": {
+					Type:     schema.TypeList,
+					Optional: true,
+					MaxItems: 1,
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"filter": {
+								Type:        schema.TypeString,
+								Required:    true,
+								Description: `The monitoring filter identifying the metric types, resources and projects to query.`,
+							},
+							"aggregation":           monitoringDashboardAggregationSchema(),
+							"secondary_aggregation": monitoringDashboardAggregationSchema(),
+						},
+					},
+				},
+				"time_series_query_language": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					Description: `A query in the Monitoring Query Language.`,
+				},
+				"prometheus_query_language": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					Description: `A query in the Prometheus Query Language.`,
+				},
+				"unit_override": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					Description: `The unit of the data, overriding the unit of the metric.`,
+				},
+			},
+		},
+	}
+}
+
+func monitoringDashboardThresholdsSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeList,
+		Optional: true,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"value": {
+					Type:     schema.TypeFloat,
+					Required: true,
+				},
+				"label": {
+					Type:     schema.TypeString,
+					Optional: true,
+				},
+				"color": {
+					Type:         schema.TypeString,
+					Optional:     true,
+					ValidateFunc: validation.StringInSlice([]string{"YELLOW", "RED"}, false),
+				},
+				"direction": {
+					Type:         schema.TypeString,
+					Optional:     true,
+					ValidateFunc: validation.StringInSlice([]string{"ABOVE", "BELOW"}, false),
+				},
+			},
+		},
+	}
+}
+
+func monitoringDashboardWidgetSchema() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"title": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"xy_chart": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				MaxItems:    1,
+				Description: `A chart of time series. Exactly one of xy_chart, scorecard or text must be set.`,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"data_sets": {
+							Type:     schema.TypeList,
+							Required: true,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"time_series_query": monitoringDashboardTimeSeriesQuerySchema(),
+									"plot_type": {
+										Type:         schema.TypeString,
+										Optional:     true,
+										Computed:     true,
+										ValidateFunc: validation.StringInSlice([]string{"LINE", "STACKED_AREA", "STACKED_BAR", "HEATMAP"}, false),
+									},
+									"legend_template": {
+										Type:     schema.TypeString,
+										Optional: true,
+									},
+									"min_alignment_period": {
+										Type:     schema.TypeString,
+										Optional: true,
+										Computed: true,
+									},
+								},
+							},
+						},
+						"timeshift_duration": {
+							Type:     schema.TypeString,
+							Optional: true,
+							Computed: true,
+						},
+						"y_axis": {
+							Type:     schema.TypeList,
+							Optional: true,
+							Computed: true,
+							MaxItems: 1,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"label": {
+										Type:     schema.TypeString,
+										Optional: true,
+									},
+									"scale": {
+										Type:         schema.TypeString,
+										Optional:     true,
+										Computed:     true,
+										ValidateFunc: validation.StringInSlice([]string{"LINEAR", "LOG10"}, false),
+									},
+								},
+							},
+						},
+						"chart_options": {
+							Type:     schema.TypeList,
+							Optional: true,
+							Computed: true,
+							MaxItems: 1,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"mode": {
+										Type:         schema.TypeString,
+										Optional:     true,
+										Computed:     true,
+										ValidateFunc: validation.StringInSlice([]string{"COLOR", "X_RAY", "STATS"}, false),
+									},
+								},
+							},
+						},
+						"thresholds": monitoringDashboardThresholdsSchema(),
+					},
+				},
+			},
+			"scorecard": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				MaxItems:    1,
+				Description: `The latest value of a time series. Exactly one of xy_chart, scorecard or text must be set.`,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"time_series_query": monitoringDashboardTimeSeriesQuerySchema(),
+						"gauge_view": {
+							Type:     schema.TypeList,
+							Optional: true,
+							MaxItems: 1,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"lower_bound": {
+										Type:     schema.TypeFloat,
+										Optional: true,
+									},
+									"upper_bound": {
+										Type:     schema.TypeFloat,
+										Optional: true,
+									},
+								},
+							},
+						},
+						"spark_chart_view": {
+							Type:     schema.TypeList,
+							Optional: true,
+							MaxItems: 1,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"spark_chart_type": {
+										Type:         schema.TypeString,
+										Required:     true,
+										ValidateFunc: validation.StringInSlice([]string{"SPARK_LINE", "SPARK_BAR"}, false),
+									},
+									"min_alignment_period": {
+										Type:     schema.TypeString,
+										Optional: true,
+										Computed: true,
+									},
+								},
+							},
+						},
+						"thresholds": monitoringDashboardThresholdsSchema(),
+					},
+				},
+			},
+			"text": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				MaxItems:    1,
+				Description: `A block of text. Exactly one of xy_chart, scorecard or text must be set.`,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"content": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"format": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							Computed:     true,
+							ValidateFunc: validation.StringInSlice([]string{"MARKDOWN", "RAW"}, false),
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func monitoringDashboardGridLayoutSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:          schema.TypeList,
+		Optional:      true,
+		MaxItems:      1,
+		ConflictsWith: []string{"dashboard_json", "mosaic_layout"},
+		Description:   `A layout arranging widgets in a grid.`,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"columns": {
+					Type:        schema.TypeInt,
+					Optional:    true,
+					Description: `The number of columns of the grid.`,
+				},
+				"widgets": {
+					Type:     schema.TypeList,
+					Optional: true,
+					Elem:     monitoringDashboardWidgetSchema(),
+				},
+			},
+		},
+	}
+}
+
+func monitoringDashboardMosaicLayoutSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:          schema.TypeList,
+		Optional:      true,
+		MaxItems:      1,
+		ConflictsWith: []string{"dashboard_json", "grid_layout"},
+		Description:   `A layout placing widgets on tiles of a grid.`,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"columns": {
+					Type:        schema.TypeInt,
+					Required:    true,
+					Description: `The number of columns of the grid.`,
+				},
+				"tiles": {
+					Type:     schema.TypeList,
+					Optional: true,
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"x_pos": {
+								Type:     schema.TypeInt,
+								Optional: true,
+							},
+							"y_pos": {
+								Type:     schema.TypeInt,
+								Optional: true,
+							},
+							"width": {
+								Type:     schema.TypeInt,
+								Optional: true,
+							},
+							"height": {
+								Type:     schema.TypeInt,
+								Optional: true,
+							},
+							"widget": {
+								Type:     schema.TypeList,
+								Required: true,
+								MaxItems: 1,
+								Elem:     monitoringDashboardWidgetSchema(),
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// monitoringDashboardFields is a tree of the fields of the API
+// representation of a dashboard that the typed schema manages. A nil subtree
+// is a field without nested fields.
+type monitoringDashboardFields map[string]monitoringDashboardFields
+
+var monitoringDashboardAggregationFields = monitoringDashboardFields{
+	"alignmentPeriod":    nil,
+	"perSeriesAligner":   nil,
+	"crossSeriesReducer": nil,
+	"groupByFields":      nil,
+}
+
+var monitoringDashboardTimeSeriesQueryFields = monitoringDashboardFields{
+	"timeSeriesFilter": {
+		"filter":               nil,
+		"aggregation":          monitoringDashboardAggregationFields,
+		"secondaryAggregation": monitoringDashboardAggregationFields,
+	},
+	"timeSeriesQueryLanguage": nil,
+	"prometheusQueryLanguage": nil,
+	"unitOverride":            nil,
+}
+
+var monitoringDashboardThresholdFields = monitoringDashboardFields{
+	"value":     nil,
+	"label":     nil,
+	"color":     nil,
+	"direction": nil,
+}
+
+var monitoringDashboardWidgetFields = monitoringDashboardFields{
+	"title": nil,
+	"xyChart": {
+		"dataSets": {
+			"timeSeriesQuery":    monitoringDashboardTimeSeriesQueryFields,
+			"plotType":           nil,
+			"legendTemplate":     nil,
+			"minAlignmentPeriod": nil,
+		},
+		"timeshiftDuration": nil,
+		"yAxis": {
+			"label": nil,
+			"scale": nil,
+		},
+		"chartOptions": {
+			"mode": nil,
+		},
+		"thresholds": monitoringDashboardThresholdFields,
+	},
+	"scorecard": {
+		"timeSeriesQuery": monitoringDashboardTimeSeriesQueryFields,
+		"gaugeView": {
+			"lowerBound": nil,
+			"upperBound": nil,
+		},
+		"sparkChartView": {
+			"sparkChartType":     nil,
+			"minAlignmentPeriod": nil,
+		},
+		"thresholds": monitoringDashboardThresholdFields,
+	},
+	"text": {
+		"content": nil,
+		"format":  nil,
+	},
+}
+
+var monitoringDashboardTypedFields = monitoringDashboardFields{
+	"name":        nil,
+	"etag":        nil,
+	"displayName": nil,
+	"gridLayout": {
+		"columns": nil,
+		"widgets": monitoringDashboardWidgetFields,
+	},
+	"mosaicLayout": {
+		"columns": nil,
+		"tiles": {
+			"xPos":   nil,
+			"yPos":   nil,
+			"width":  nil,
+			"height": nil,
+			"widget": monitoringDashboardWidgetFields,
+		},
+	},
+}
+
+// monitoringDashboardUnmanagedFields returns the paths of the fields of the
+// API representation of a dashboard that the typed schema doesn't manage, and
+// that an update from the typed arguments would delete.
+func monitoringDashboardUnmanagedFields(res map[string]interface{}) []string {
+	var paths []string
+	var walk func(path string, v interface{}, fields monitoringDashboardFields)
+	walk = func(path string, v interface{}, fields monitoringDashboardFields) {
+		switch v := v.(type) {
+		case map[string]interface{}:
+			for k, nested := range v {
+				p := k
+				if path != "" {
+					p = path + "." + k
+				}
+				f, ok := fields[k]
+				if !ok {
+					paths = append(paths, p)
+					continue
+				}
+				if f != nil {
+					walk(p, nested, f)
+				}
+			}
+		case []interface{}:
+			for i, nested := range v {
+				walk(fmt.Sprintf("%s.%d", path, i), nested, fields)
+			}
+		}
+	}
+	walk("", res, monitoringDashboardTypedFields)
+	sort.Strings(paths)
+	return paths
+}
+
+// expandMonitoringDashboard returns the API representation of a dashboard
+// defined with the typed schema.
+func expandMonitoringDashboard(d TerraformResourceData) (map[string]interface{}, error) {
+	obj := map[string]interface{}{
+		"displayName": d.Get("display_name"),
+	}
+
+	if v, ok := d.GetOk("grid_layout"); ok && len(v.([]interface{})) > 0 {
+		grid := map[string]interface{}{}
+		if raw := v.([]interface{})[0]; raw != nil {
+			original := raw.(map[string]interface{})
+			if columns := original["columns"].(int); columns > 0 {
+				// columns is an int64, which the API encodes as a string
+				grid["columns"] = strconv.Itoa(columns)
+			}
+			widgets, err := expandMonitoringDashboardWidgets(original["widgets"].([]interface{}))
+			if err != nil {
+				return nil, err
+			}
+			grid["widgets"] = widgets
+		}
+		obj["gridLayout"] = grid
+	}
+
+	if v, ok := d.GetOk("mosaic_layout"); ok && len(v.([]interface{})) > 0 {
+		original := v.([]interface{})[0].(map[string]interface{})
+		tiles := make([]interface{}, 0)
+		for i, raw := range original["tiles"].([]interface{}) {
+			tile := raw.(map[string]interface{})
+			widgets, err := expandMonitoringDashboardWidgets(tile["widget"].([]interface{}))
+			if err != nil {
+				return nil, fmt.Errorf("mosaic_layout.0.tiles.%d: %s", i, err)
+			}
+			t := map[string]interface{}{
+				"xPos":   tile["x_pos"],
+				"yPos":   tile["y_pos"],
+				"width":  tile["width"],
+				"height": tile["height"],
+			}
+			if len(widgets) > 0 {
+				t["widget"] = widgets[0]
+			}
+			tiles = append(tiles, t)
+		}
+		obj["mosaicLayout"] = map[string]interface{}{
+			"columns": original["columns"],
+			"tiles":   tiles,
+		}
+	}
+
+	return obj, nil
+}
+
+func expandMonitoringDashboardWidgets(raw []interface{}) ([]interface{}, error) {
+	widgets := make([]interface{}, 0, len(raw))
+	for i, r := range raw {
+		if r == nil {
+			return nil, fmt.Errorf("widget %d: exactly one of xy_chart, scorecard or text must be set", i)
+		}
+		original := r.(map[string]interface{})
+		widget := map[string]interface{}{}
+		if title := original["title"].(string); title != "" {
+			widget["title"] = title
+		}
+
+		set := 0
+		if v := original["xy_chart"].([]interface{}); len(v) > 0 && v[0] != nil {
+			set++
+			chart, err := expandMonitoringDashboardXyChart(v[0].(map[string]interface{}))
+			if err != nil {
+				return nil, fmt.Errorf("widget %d: %s", i, err)
+			}
+			widget["xyChart"] = chart
+		}
+		if v := original["scorecard"].([]interface{}); len(v) > 0 && v[0] != nil {
+			set++
+			scorecard, err := expandMonitoringDashboardScorecard(v[0].(map[string]interface{}))
+			if err != nil {
+				return nil, fmt.Errorf("widget %d: %s", i, err)
+			}
+			widget["scorecard"] = scorecard
+		}
+		if v := original["text"].([]interface{}); len(v) > 0 {
+			set++
+			text := map[string]interface{}{}
+			if v[0] != nil {
+				original := v[0].(map[string]interface{})
+				text["content"] = original["content"]
+				if format := original["format"].(string); format != "" {
+					text["format"] = format
+				}
+			}
+			widget["text"] = text
+		}
+		if set != 1 {
+			return nil, fmt.Errorf("widget %d: exactly one of xy_chart, scorecard or text must be set", i)
+		}
+		widgets = append(widgets, widget)
+	}
+	return widgets, nil
+}
+
+func expandMonitoringDashboardXyChart(original map[string]interface{}) (map[string]interface{}, error) {
+	dataSets := make([]interface{}, 0)
+	for i, raw := range original["data_sets"].([]interface{}) {
+		ds := raw.(map[string]interface{})
+		query, err := expandMonitoringDashboardTimeSeriesQuery(ds["time_series_query"].([]interface{}))
+		if err != nil {
+			return nil, fmt.Errorf("data set %d: %s", i, err)
+		}
+		dataSet := map[string]interface{}{
+			"timeSeriesQuery": query,
+		}
+		for field, key := range map[string]string{"plot_type": "plotType", "legend_template": "legendTemplate", "min_alignment_period": "minAlignmentPeriod"} {
+			if v := ds[field].(string); v != "" {
+				dataSet[key] = v
+			}
+		}
+		dataSets = append(dataSets, dataSet)
+	}
+
+	chart := map[string]interface{}{
+		"dataSets": dataSets,
+	}
+	if v := original["timeshift_duration"].(string); v != "" {
+		chart["timeshiftDuration"] = v
+	}
+	if v := original["y_axis"].([]interface{}); len(v) > 0 && v[0] != nil {
+		axis := v[0].(map[string]interface{})
+		yAxis := map[string]interface{}{}
+		if label := axis["label"].(string); label != "" {
+			yAxis["label"] = label
+		}
+		if scale := axis["scale"].(string); scale != "" {
+			yAxis["scale"] = scale
+		}
+		chart["yAxis"] = yAxis
+	}
+	if v := original["chart_options"].([]interface{}); len(v) > 0 && v[0] != nil {
+		if mode := v[0].(map[string]interface{})["mode"].(string); mode != "" {
+			chart["chartOptions"] = map[string]interface{}{"mode": mode}
+		}
+	}
+	if thresholds := expandMonitoringDashboardThresholds(original["thresholds"].([]interface{})); len(thresholds) > 0 {
+		chart["thresholds"] = thresholds
+	}
+	return chart, nil
+}
+
+func expandMonitoringDashboardScorecard(original map[string]interface{}) (map[string]interface{}, error) {
+	query, err := expandMonitoringDashboardTimeSeriesQuery(original["time_series_query"].([]interface{}))
+	if err != nil {
+		return nil, err
+	}
+	scorecard := map[string]interface{}{
+		"timeSeriesQuery": query,
+	}
+	if v := original["gauge_view"].([]interface{}); len(v) > 0 {
+		gauge := map[string]interface{}{}
+		if v[0] != nil {
+			g := v[0].(map[string]interface{})
+			gauge["lowerBound"] = g["lower_bound"]
+			gauge["upperBound"] = g["upper_bound"]
+		}
+		scorecard["gaugeView"] = gauge
+	}
+	if v := original["spark_chart_view"].([]interface{}); len(v) > 0 && v[0] != nil {
+		s := v[0].(map[string]interface{})
+		spark := map[string]interface{}{
+			"sparkChartType": s["spark_chart_type"],
+		}
+		if period := s["min_alignment_period"].(string); period != "" {
+			spark["minAlignmentPeriod"] = period
+		}
+		scorecard["sparkChartView"] = spark
+	}
+	if thresholds := expandMonitoringDashboardThresholds(original["thresholds"].([]interface{})); len(thresholds) > 0 {
+		scorecard["thresholds"] = thresholds
+	}
+	return scorecard, nil
+}
+
+func expandMonitoringDashboardTimeSeriesQuery(raw []interface{}) (map[string]interface{}, error) {
+	errQuery := fmt.Errorf("exactly one of time_series_filter, time_series_query_language or prometheus_query_language must be set")
+	if len(raw) == 0 || raw[0] == nil {
+		return nil, errQuery
+	}
+	original := raw[0].(map[string]interface{})
+
+	query := map[string]interface{}{}
+	set := 0
+	if v := original["time_series_filter"].([]interface{}); len(v) > 0 && v[0] != nil {
+		set++
+		f := v[0].(map[string]interface{})
+		filter := map[string]interface{}{
+			"filter": f["filter"],
+		}
+		if aggregation := expandMonitoringDashboardAggregation(f["aggregation"].([]interface{})); aggregation != nil {
+			filter["aggregation"] = aggregation
+		}
+		if aggregation := expandMonitoringDashboardAggregation(f["secondary_aggregation"].([]interface{})); aggregation != nil {
+			filter["secondaryAggregation"] = aggregation
+		}
+		query["timeSeriesFilter"] = filter
+	}
+	if v := original["time_series_query_language"].(string); v != "" {
+		set++
+		query["timeSeriesQueryLanguage"] = v
+	}
+	if v := original["prometheus_query_language"].(string); v != "" {
+		set++
+		query["prometheusQueryLanguage"] = v
+	}
+	if set != 1 {
+		return nil, errQuery
+	}
+	if v := original["unit_override"].(string); v != "" {
+		query["unitOverride"] = v
+	}
+	return query, nil
+}
+
+func expandMonitoringDashboardAggregation(raw []interface{}) map[string]interface{} {
+	if len(raw) == 0 {
+		return nil
+	}
+	aggregation := map[string]interface{}{}
+	if raw[0] == nil {
+		return aggregation
+	}
+	original := raw[0].(map[string]interface{})
+	for field, key := range map[string]string{"alignment_period": "alignmentPeriod", "per_series_aligner": "perSeriesAligner", "cross_series_reducer": "crossSeriesReducer"} {
+		if v := original[field].(string); v != "" {
+			aggregation[key] = v
+		}
+	}
+	if v := original["group_by_fields"].([]interface{}); len(v) > 0 {
+		aggregation["groupByFields"] = v
+	}
+	return aggregation
+}
+
+func expandMonitoringDashboardThresholds(raw []interface{}) []interface{} {
+	thresholds := make([]interface{}, 0, len(raw))
+	for _, r := range raw {
+		original := r.(map[string]interface{})
+		threshold := map[string]interface{}{
+			"value": original["value"],
+		}
+		for _, field := range []string{"label", "color", "direction"} {
+			if v := original[field].(string); v != "" {
+				threshold[field] = v
+			}
+		}
+		thresholds = append(thresholds, threshold)
+	}
+	return thresholds
+}
+
+// flattenMonitoringDashboard sets the typed attributes of a dashboard from
+// its API representation.
+func flattenMonitoringDashboard(d *schema.ResourceData, res map[string]interface{}) error {
+	if err := d.Set("display_name", res["displayName"]); err != nil {
+		return fmt.Errorf("Error setting display_name: %s", err)
+	}
+
+	var grid []interface{}
+	if v, ok := res["gridLayout"].(map[string]interface{}); ok {
+		grid = []interface{}{map[string]interface{}{
+			"columns": monitoringDashboardInt(v["columns"]),
+			"widgets": flattenMonitoringDashboardWidgets(v["widgets"]),
+		}}
+	}
+	if err := d.Set("grid_layout", grid); err != nil {
+		return fmt.Errorf("Error setting grid_layout: %s", err)
+	}
+
+	var mosaic []interface{}
+	if v, ok := res["mosaicLayout"].(map[string]interface{}); ok {
+		tiles := make([]interface{}, 0)
+		for _, raw := range monitoringDashboardList(v["tiles"]) {
+			tile, ok := raw.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			var widget []interface{}
+			if w, ok := tile["widget"]; ok {
+				widget = flattenMonitoringDashboardWidgets([]interface{}{w})
+			}
+			tiles = append(tiles, map[string]interface{}{
+				"x_pos":  monitoringDashboardInt(tile["xPos"]),
+				"y_pos":  monitoringDashboardInt(tile["yPos"]),
+				"width":  monitoringDashboardInt(tile["width"]),
+				"height": monitoringDashboardInt(tile["height"]),
+				"widget": widget,
+			})
+		}
+		mosaic = []interface{}{map[string]interface{}{
+			"columns": monitoringDashboardInt(v["columns"]),
+			"tiles":   tiles,
+		}}
+	}
+	if err := d.Set("mosaic_layout", mosaic); err != nil {
+		return fmt.Errorf("Error setting mosaic_layout: %s", err)
+	}
+	return nil
+}
+
+func flattenMonitoringDashboardWidgets(v interface{}) []interface{} {
+	widgets := make([]interface{}, 0)
+	for _, raw := range monitoringDashboardList(v) {
+		original, ok := raw.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		widget := map[string]interface{}{
+			"title": original["title"],
+		}
+		if chart, ok := original["xyChart"].(map[string]interface{}); ok {
+			widget["xy_chart"] = flattenMonitoringDashboardXyChart(chart)
+		}
+		if scorecard, ok := original["scorecard"].(map[string]interface{}); ok {
+			widget["scorecard"] = flattenMonitoringDashboardScorecard(scorecard)
+		}
+		if text, ok := original["text"].(map[string]interface{}); ok {
+			widget["text"] = []interface{}{map[string]interface{}{
+				"content": text["content"],
+				"format":  text["format"],
+			}}
+		}
+		widgets = append(widgets, widget)
+	}
+	return widgets
+}
+
+func flattenMonitoringDashboardXyChart(chart map[string]interface{}) []interface{} {
+	dataSets := make([]interface{}, 0)
+	for _, raw := range monitoringDashboardList(chart["dataSets"]) {
+		ds, ok := raw.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		dataSets = append(dataSets, map[string]interface{}{
+			"time_series_query":    flattenMonitoringDashboardTimeSeriesQuery(ds["timeSeriesQuery"]),
+			"plot_type":            ds["plotType"],
+			"legend_template":      ds["legendTemplate"],
+			"min_alignment_period": ds["minAlignmentPeriod"],
+		})
+	}
+
+	flattened := map[string]interface{}{
+		"data_sets":          dataSets,
+		"timeshift_duration": chart["timeshiftDuration"],
+		"thresholds":         flattenMonitoringDashboardThresholds(chart["thresholds"]),
+	}
+	if axis, ok := chart["yAxis"].(map[string]interface{}); ok {
+		flattened["y_axis"] = []interface{}{map[string]interface{}{
+			"label": axis["label"],
+			"scale": axis["scale"],
+		}}
+	}
+	if options, ok := chart["chartOptions"].(map[string]interface{}); ok {
+		flattened["chart_options"] = []interface{}{map[string]interface{}{
+			"mode": options["mode"],
+		}}
+	}
+	return []interface{}{flattened}
+}
+
+func flattenMonitoringDashboardScorecard(scorecard map[string]interface{}) []interface{} {
+	flattened := map[string]interface{}{
+		"time_series_query": flattenMonitoringDashboardTimeSeriesQuery(scorecard["timeSeriesQuery"]),
+		"thresholds":        flattenMonitoringDashboardThresholds(scorecard["thresholds"]),
+	}
+	if gauge, ok := scorecard["gaugeView"].(map[string]interface{}); ok {
+		flattened["gauge_view"] = []interface{}{map[string]interface{}{
+			"lower_bound": monitoringDashboardFloat(gauge["lowerBound"]),
+			"upper_bound": monitoringDashboardFloat(gauge["upperBound"]),
+		}}
+	}
+	if spark, ok := scorecard["sparkChartView"].(map[string]interface{}); ok {
+		flattened["spark_chart_view"] = []interface{}{map[string]interface{}{
+			"spark_chart_type":     spark["sparkChartType"],
+			"min_alignment_period": spark["minAlignmentPeriod"],
+		}}
+	}
+	return []interface{}{flattened}
+}
+
+func flattenMonitoringDashboardTimeSeriesQuery(v interface{}) []interface{} {
+	query, ok := v.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	flattened := map[string]interface{}{
+		"time_series_query_language": query["timeSeriesQueryLanguage"],
+		"prometheus_query_language":  query["prometheusQueryLanguage"],
+		"unit_override":              query["unitOverride"],
+	}
+	if filter, ok := query["timeSeriesFilter"].(map[string]interface{}); ok {
+		flattened["time_series_filter"] = []interface{}{map[string]interface{}{
+			"filter":                filter["filter"],
+			"aggregation":           flattenMonitoringDashboardAggregation(filter["aggregation"]),
+			"secondary_aggregation": flattenMonitoringDashboardAggregation(filter["secondaryAggregation"]),
+		}}
+	}
+	return []interface{}{flattened}
+}
+
+func flattenMonitoringDashboardAggregation(v interface{}) []interface{} {
+	aggregation, ok := v.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	return []interface{}{map[string]interface{}{
+		"alignment_period":     aggregation["alignmentPeriod"],
+		"per_series_aligner":   aggregation["perSeriesAligner"],
+		"cross_series_reducer": aggregation["crossSeriesReducer"],
+		"group_by_fields":      aggregation["groupByFields"],
+	}}
+}
+
+func flattenMonitoringDashboardThresholds(v interface{}) []interface{} {
+	thresholds := make([]interface{}, 0)
+	for _, raw := range monitoringDashboardList(v) {
+		t, ok := raw.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		thresholds = append(thresholds, map[string]interface{}{
+			"value":     monitoringDashboardFloat(t["value"]),
+			"label":     t["label"],
+			"color":     t["color"],
+			"direction": t["direction"],
+		})
+	}
+	return thresholds
+}
+
+func monitoringDashboardList(v interface{}) []interface{} {
+	l, _ := v.([]interface{})
+	return l
+}
+
+// monitoringDashboardInt converts an integer of the API, which may be encoded
+// as a number or, for int64 fields, as a string.
+func monitoringDashboardInt(v interface{}) int {
+	switch n := v.(type) {
+	case float64:
+		return int(n)
+	case int:
+		return n
+	case json.Number:
+		i, _ := n.Int64()
+		return int(i)
+	case string:
+		i, _ := strconv.Atoi(n)
+		return i
+	}
+	return 0
+}
+
+func monitoringDashboardFloat(v interface{}) float64 {
+	switch n := v.(type) {
+	case float64:
+		return n
+	case int:
+		return float64(n)
+	case json.Number:
+		f, _ := n.Float64()
+		return f
+	case string:
+		f, _ := strconv.ParseFloat(n, 64)
+		return f
+	}
+	return 0
+}
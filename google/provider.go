@@ -135,6 +135,30 @@ func Provider() *schema.Provider {
 				}, nil),
 			},
 
+			"skip_iam_condition_validation": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				DefaultFunc: schema.MultiEnvDefaultFunc([]string{
+					"GOOGLE_SKIP_IAM_CONDITION_VALIDATION",
+				}, false),
+			},
+
+			"detect_iam_conflicts": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				DefaultFunc: schema.MultiEnvDefaultFunc([]string{
+					"GOOGLE_DETECT_IAM_CONFLICTS",
+				}, false),
+			},
+
+			"skip_cloudbuild_trigger_validation": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				DefaultFunc: schema.MultiEnvDefaultFunc([]string{
+					"GOOGLE_SKIP_CLOUDBUILD_TRIGGER_VALIDATION",
+				}, false),
+			},
+
 			"request_timeout": {
 				Type:     schema.TypeString,
 				Optional: true,
@@ -437,6 +461,14 @@ func Provider() *schema.Provider {
 					"GOOGLE_HEALTHCARE_CUSTOM_ENDPOINT",
 				}, DefaultBasePaths[HealthcareBasePathKey]),
 			},
+			"iam2_custom_endpoint": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateCustomEndpoint,
+				DefaultFunc: schema.MultiEnvDefaultFunc([]string{
+					"GOOGLE_IAM2_CUSTOM_ENDPOINT",
+				}, DefaultBasePaths[IAM2BasePathKey]),
+			},
 			"iap_custom_endpoint": {
 				Type:         schema.TypeString,
 				Optional:     true,
@@ -737,6 +769,7 @@ func Provider() *schema.Provider {
 			"google_compute_backend_service":                      dataSourceGoogleComputeBackendService(),
 			"google_compute_backend_bucket":                       dataSourceGoogleComputeBackendBucket(),
 			"google_compute_default_service_account":              dataSourceGoogleComputeDefaultServiceAccount(),
+			"google_compute_firewall_analysis":                    dataSourceGoogleComputeFirewallAnalysis(),
 			"google_compute_forwarding_rule":                      dataSourceGoogleComputeForwardingRule(),
 			"google_compute_global_address":                       dataSourceGoogleComputeGlobalAddress(),
 			"google_compute_global_forwarding_rule":               dataSourceGoogleComputeGlobalForwardingRule(),
@@ -761,11 +794,13 @@ func Provider() *schema.Provider {
 			"google_compute_ssl_certificate":                      dataSourceGoogleComputeSslCertificate(),
 			"google_compute_ssl_policy":                           dataSourceGoogleComputeSslPolicy(),
 			"google_compute_subnetwork":                           dataSourceGoogleComputeSubnetwork(),
+			"google_compute_url_map_routing":                      dataSourceGoogleComputeUrlMapRouting(),
 			"google_compute_vpn_gateway":                          dataSourceGoogleComputeVpnGateway(),
 			"google_compute_zones":                                dataSourceGoogleComputeZones(),
 			"google_container_azure_versions":                     dataSourceGoogleContainerAzureVersions(),
 			"google_container_aws_versions":                       dataSourceGoogleContainerAwsVersions(),
 			"google_container_cluster":                            dataSourceGoogleContainerCluster(),
+			"google_container_cluster_kubeconfig":                 dataSourceGoogleContainerClusterKubeconfig(),
 			"google_container_engine_versions":                    dataSourceGoogleContainerEngineVersions(),
 			"google_container_registry_image":                     dataSourceGoogleContainerImage(),
 			"google_container_registry_repository":                dataSourceGoogleContainerRepo(),
@@ -773,6 +808,8 @@ func Provider() *schema.Provider {
 			"google_dns_managed_zone":                             dataSourceDnsManagedZone(),
 			"google_dns_record_set":                               dataSourceDnsRecordSet(),
 			"google_game_services_game_server_deployment_rollout": dataSourceGameServicesGameServerDeploymentRollout(),
+			"google_iam_deny_policies":                            dataSourceGoogleIamDenyPolicies(),
+			"google_iam_effective_policy":                         dataSourceGoogleIamEffectivePolicy(),
 			"google_iam_policy":                                   dataSourceGoogleIamPolicy(),
 			"google_iam_role":                                     dataSourceGoogleIamRole(),
 			"google_iam_testable_permissions":                     dataSourceGoogleIamTestablePermissions(),
@@ -793,6 +830,7 @@ func Provider() *schema.Provider {
 			"google_monitoring_uptime_check_ips":                  dataSourceGoogleMonitoringUptimeCheckIps(),
 			"google_netblock_ip_ranges":                           dataSourceGoogleNetblockIpRanges(),
 			"google_organization":                                 dataSourceGoogleOrganization(),
+			"google_effective_organization_policy":                dataSourceGoogleEffectiveOrganizationPolicy(),
 			"google_privateca_certificate_authority":              dataSourcePrivatecaCertificateAuthority(),
 			"google_project":                                      dataSourceGoogleProject(),
 			"google_projects":                                     dataSourceGoogleProjects(),
@@ -1070,7 +1108,6 @@ func ResourceMapWithErrors() (map[string]*schema.Resource, error) {
 			"google_identity_platform_tenant_oauth_idp_config":             resourceIdentityPlatformTenantOauthIdpConfig(),
 			"google_identity_platform_tenant":                              resourceIdentityPlatformTenant(),
 			"google_kms_key_ring":                                          resourceKMSKeyRing(),
-			"google_kms_crypto_key":                                        resourceKMSCryptoKey(),
 			"google_kms_key_ring_import_job":                               resourceKMSKeyRingImportJob(),
 			"google_kms_secret_ciphertext":                                 resourceKMSSecretCiphertext(),
 			"google_logging_metric":                                        resourceLoggingMetric(),
@@ -1170,6 +1207,7 @@ func ResourceMapWithErrors() (map[string]*schema.Resource, error) {
 			"google_bigtable_instance":                     resourceBigtableInstance(),
 			"google_bigtable_table":                        resourceBigtableTable(),
 			"google_billing_subaccount":                    resourceBillingSubaccount(),
+			"google_cloudbuild_trigger_run":                resourceCloudBuildTriggerRun(),
 			"google_cloudfunctions_function":               resourceCloudFunctionsFunction(),
 			"google_composer_environment":                  resourceComposerEnvironment(),
 			"google_compute_attached_disk":                 resourceComputeAttachedDisk(),
@@ -1187,6 +1225,7 @@ func ResourceMapWithErrors() (map[string]*schema.Resource, error) {
 			"google_compute_security_policy":               resourceComputeSecurityPolicy(),
 			"google_compute_shared_vpc_host_project":       resourceComputeSharedVpcHostProject(),
 			"google_compute_shared_vpc_service_project":    resourceComputeSharedVpcServiceProject(),
+			"google_compute_subnetwork_range_allocation":   resourceComputeSubnetworkRangeAllocation(),
 			"google_compute_target_pool":                   resourceComputeTargetPool(),
 			"google_container_cluster":                     resourceContainerCluster(),
 			"google_container_node_pool":                   resourceContainerNodePool(),
@@ -1198,6 +1237,9 @@ func ResourceMapWithErrors() (map[string]*schema.Resource, error) {
 			"google_endpoints_service":                     resourceEndpointsService(),
 			"google_folder":                                resourceGoogleFolder(),
 			"google_folder_organization_policy":            resourceGoogleFolderOrganizationPolicy(),
+			"google_iam_deny_policy":                       resourceIAM2DenyPolicy(),
+			"google_kms_crypto_key":                        resourceKMSCryptoKeyWithMaxEnabledVersions(),
+			"google_kms_crypto_key_version":                resourceKMSCryptoKeyVersion(),
 			"google_logging_billing_account_sink":          resourceLoggingBillingAccountSink(),
 			"google_logging_billing_account_exclusion":     ResourceLoggingExclusion(BillingAccountLoggingExclusionSchema, NewBillingAccountLoggingExclusionUpdater, billingAccountLoggingExclusionIdParseFunc),
 			"google_logging_billing_account_bucket_config": ResourceLoggingBillingAccountBucketConfig(),
@@ -1219,12 +1261,17 @@ func ResourceMapWithErrors() (map[string]*schema.Resource, error) {
 			"google_organization_policy":                   resourceGoogleOrganizationPolicy(),
 			"google_project":                               resourceGoogleProject(),
 			"google_project_default_service_accounts":      resourceGoogleProjectDefaultServiceAccounts(),
+			"google_project_factory":                       resourceGoogleProjectFactory(),
 			"google_project_service":                       resourceGoogleProjectService(),
+			"google_project_services":                      resourceGoogleProjectServices(),
 			"google_project_iam_custom_role":               resourceGoogleProjectIamCustomRole(),
 			"google_project_organization_policy":           resourceGoogleProjectOrganizationPolicy(),
 			"google_project_usage_export_bucket":           resourceProjectUsageBucket(),
+			"google_pubsub_snapshot":                       resourcePubsubSnapshot(),
+			"google_pubsub_subscription_seek":              resourcePubsubSubscriptionSeek(),
 			"google_service_account":                       resourceGoogleServiceAccount(),
 			"google_service_account_key":                   resourceGoogleServiceAccountKey(),
+			"google_service_account_key_rotation":          resourceGoogleServiceAccountKeyRotation(),
 			"google_service_networking_peered_dns_domain":  resourceGoogleServiceNetworkingPeeredDNSDomain(),
 			"google_storage_bucket":                        resourceStorageBucket(),
 			"google_storage_bucket_acl":                    resourceStorageBucketAcl(),
@@ -1323,6 +1370,8 @@ func ResourceMapWithErrors() (map[string]*schema.Resource, error) {
 			"google_service_account_iam_binding":         ResourceIamBinding(IamServiceAccountSchema, NewServiceAccountIamUpdater, ServiceAccountIdParseFunc),
 			"google_service_account_iam_member":          ResourceIamMember(IamServiceAccountSchema, NewServiceAccountIamUpdater, ServiceAccountIdParseFunc),
 			"google_service_account_iam_policy":          ResourceIamPolicy(IamServiceAccountSchema, NewServiceAccountIamUpdater, ServiceAccountIdParseFunc),
+			"google_iam_resource_binding":                ResourceIamBindingWithBatching(IamResourceNameSchema, IamResourceNameUpdaterProducer, IamResourceNameIdParseFunc, IamBatchingEnabled),
+			"google_iam_resource_member":                 ResourceIamMemberWithBatching(IamResourceNameSchema, IamResourceNameUpdaterProducer, IamResourceNameIdParseFunc, IamBatchingEnabled),
 			// ####### END non-generated IAM resources ###########
 		},
 	)
@@ -1356,6 +1405,10 @@ func providerConfigure(ctx context.Context, d *schema.ResourceData, p *schema.Pr
 		config.RequestReason = v.(string)
 	}
 
+	config.SkipIamConditionValidation = d.Get("skip_iam_condition_validation").(bool)
+	config.DetectIamConflicts = d.Get("detect_iam_conflicts").(bool)
+	config.SkipCloudBuildTriggerValidation = d.Get("skip_cloudbuild_trigger_validation").(bool)
+
 	// Check for primary credentials in config. Note that if neither is set, ADCs
 	// will be used if available.
 	if v, ok := d.GetOk("access_token"); ok {
@@ -1445,6 +1498,7 @@ func providerConfigure(ctx context.Context, d *schema.ResourceData, p *schema.Pr
 	config.GameServicesBasePath = d.Get("game_services_custom_endpoint").(string)
 	config.GKEHubBasePath = d.Get("gke_hub_custom_endpoint").(string)
 	config.HealthcareBasePath = d.Get("healthcare_custom_endpoint").(string)
+	config.IAM2BasePath = d.Get("iam2_custom_endpoint").(string)
 	config.IapBasePath = d.Get("iap_custom_endpoint").(string)
 	config.IdentityPlatformBasePath = d.Get("identity_platform_custom_endpoint").(string)
 	config.KMSBasePath = d.Get("kms_custom_endpoint").(string)
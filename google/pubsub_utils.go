@@ -2,7 +2,17 @@ package google
 
 import (
 	"fmt"
+	"log"
+	"net/url"
+	"reflect"
 	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"google.golang.org/api/bigquery/v2"
+	"google.golang.org/api/cloudresourcemanager/v1"
+	resourceManagerV2 "google.golang.org/api/cloudresourcemanager/v2"
+	"google.golang.org/api/iam/v1"
 )
 
 const PubsubTopicRegex = "projects\\/.*\\/topics\\/.*"
@@ -22,3 +32,797 @@ func getComputedTopicName(project, topic string) string {
 	}
 	return fmt.Sprintf("projects/%s/topics/%s", project, topic)
 }
+
+func getComputedSnapshotName(project, snapshot string) string {
+	match, _ := regexp.MatchString("projects\\/.*\\/snapshots\\/.*", snapshot)
+	if match {
+		return snapshot
+	}
+	return fmt.Sprintf("projects/%s/snapshots/%s", project, snapshot)
+}
+
+// Permissions the Pub/Sub service agent needs to deliver the messages of a
+// BigQuery or Cloud Storage subscription.
+var (
+	pubsubBigQueryDeliveryPermissions     = []string{"bigquery.tables.get", "bigquery.tables.updateData"}
+	pubsubCloudStorageDeliveryPermissions = []string{"storage.buckets.get", "storage.objects.create"}
+)
+
+// BigQuery dataset access entries may use the legacy role names.
+var bigqueryDatasetLegacyRoles = map[string]string{
+	"OWNER":  "roles/bigquery.dataOwner",
+	"WRITER": "roles/bigquery.dataEditor",
+	"READER": "roles/bigquery.dataViewer",
+}
+
+// checkPubsubSubscriptionDeliveryPermissions checks that the Pub/Sub service
+// agent of project can write to the BigQuery table or Cloud Storage bucket of
+// the subscription, so a missing grant surfaces before the subscription is
+// created rather than as messages piling up in its backlog.
+//
+// The grants on the table, dataset, bucket, their project and its ancestors
+// are considered. The check only fails when they can't give the service agent
+// the permissions it needs: when they can't be read, or when grants to groups,
+// domains, under conditions or ACLs may give them, it's skipped and left to
+// the API.
+func checkPubsubSubscriptionDeliveryPermissions(d *schema.ResourceData, config *Config, project, userAgent string) error {
+	table, hasTable := d.GetOk("bigquery_config.0.table")
+	bucket, hasBucket := d.GetOk("cloud_storage_config.0.bucket")
+	if !hasTable && !hasBucket {
+		return nil
+	}
+
+	projectNumber, err := getProjectNumber(d, config, project, userAgent)
+	if err != nil {
+		return err
+	}
+	email := fmt.Sprintf("service-%s@gcp-sa-pubsub.iam.gserviceaccount.com", projectNumber)
+	permissions := iamRolePermissionsFunc(config, userAgent)
+
+	if hasTable {
+		grants, err := pubsubBigQueryTableGrants(config, table.(string), email, userAgent)
+		if err != nil {
+			log.Printf("[WARN] Unable to check the access of %s to BigQuery table %q, skipping: %s", email, table, err)
+			return nil
+		}
+		missing, err := grants.missingPermissions(pubsubBigQueryDeliveryPermissions, permissions)
+		if err != nil {
+			log.Printf("[WARN] Unable to check the access of %s to BigQuery table %q, skipping: %s", email, table, err)
+			return nil
+		}
+		if len(missing) > 0 {
+			return fmt.Errorf("the Pub/Sub service agent %s is missing %s on BigQuery table %q, which it needs to write messages to the table. "+
+				"Grant it roles/bigquery.dataEditor on the table, its dataset or project", email, strings.Join(missing, ", "), table)
+		}
+	}
+
+	if hasBucket {
+		grants, err := pubsubStorageBucketGrants(config, bucket.(string), email, userAgent)
+		if err != nil {
+			log.Printf("[WARN] Unable to check the access of %s to Cloud Storage bucket %q, skipping: %s", email, bucket, err)
+			return nil
+		}
+		missing, err := grants.missingPermissions(pubsubCloudStorageDeliveryPermissions, permissions)
+		if err != nil {
+			log.Printf("[WARN] Unable to check the access of %s to Cloud Storage bucket %q, skipping: %s", email, bucket, err)
+			return nil
+		}
+		if len(missing) > 0 {
+			return fmt.Errorf("the Pub/Sub service agent %s is missing %s on Cloud Storage bucket %q, which it needs to write messages to the bucket. "+
+				"Grant it roles/storage.objectCreator and roles/storage.legacyBucketReader on the bucket", email, strings.Join(missing, ", "), bucket)
+		}
+	}
+
+	return nil
+}
+
+// pubsubDeliveryGrants collects the roles that may give the Pub/Sub service
+// agent access to a table or bucket.
+type pubsubDeliveryGrants struct {
+	member string
+	// roles are granted to the service agent itself, unconditionally.
+	roles []string
+	// indirect are granted to principals that may include the service
+	// agent, or under conditions.
+	indirect []pubsubDeliveryIndirectGrant
+	// unknown describes access that can't be checked from roles, such as
+	// the ACLs of a bucket.
+	unknown string
+}
+
+type pubsubDeliveryIndirectGrant struct {
+	role, member string
+}
+
+func newPubsubDeliveryGrants(email string) *pubsubDeliveryGrants {
+	return &pubsubDeliveryGrants{member: "serviceAccount:" + email}
+}
+
+// addBinding records the grant of role to members.
+func (g *pubsubDeliveryGrants) addBinding(role string, members []string, conditional bool) {
+	for _, m := range members {
+		switch {
+		case strings.EqualFold(m, g.member) && !conditional:
+			g.roles = append(g.roles, role)
+		case strings.EqualFold(m, g.member) || pubsubDeliveryIndirectMember(m):
+			g.indirect = append(g.indirect, pubsubDeliveryIndirectGrant{role: role, member: m})
+		}
+	}
+}
+
+// pubsubDeliveryIndirectMember returns whether the principal m may include
+// a service account.
+func pubsubDeliveryIndirectMember(m string) bool {
+	if m == "allUsers" || m == "allAuthenticatedUsers" {
+		return true
+	}
+	for _, prefix := range []string{"group:", "domain:", "principalSet:", "specialGroup:", "projectOwner:", "projectEditor:", "projectViewer:"} {
+		if strings.HasPrefix(m, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// missingPermissions returns the permissions in required that the grants
+// don't give the service agent, or none when other access may give them.
+func (g *pubsubDeliveryGrants) missingPermissions(required []string, permissions func(string) ([]string, error)) ([]string, error) {
+	missing, err := iamMissingPermissions(required, g.roles, permissions)
+	if err != nil || len(missing) == 0 {
+		return missing, err
+	}
+	if g.unknown != "" {
+		log.Printf("[WARN] %s may be granted %s by %s, skipping the check", g.member, strings.Join(missing, ", "), g.unknown)
+		return nil, nil
+	}
+	for _, grant := range g.indirect {
+		remaining, err := iamMissingPermissions(missing, []string{grant.role}, permissions)
+		if err != nil {
+			return nil, err
+		}
+		if len(remaining) < len(missing) {
+			log.Printf("[WARN] %s may be granted %s through %s on %s, skipping the check", g.member, strings.Join(missing, ", "), grant.role, grant.member)
+			return nil, nil
+		}
+	}
+	return missing, nil
+}
+
+// parsePubsubBigQueryTable splits a table of the form
+// {projectId}:{datasetId}.{tableId}, or {projectId}.{datasetId}.{tableId}.
+func parsePubsubBigQueryTable(table string) (project, dataset, tableId string, err error) {
+	parts := regexp.MustCompile(`^([^:.]+)[:.]([^.]+)\.([^.]+)$`).FindStringSubmatch(table)
+	if parts == nil {
+		return "", "", "", fmt.Errorf("invalid BigQuery table %q, expected {projectId}:{datasetId}.{tableId}", table)
+	}
+	return parts[1], parts[2], parts[3], nil
+}
+
+// pubsubBigQueryTableGrants returns the grants on table, its dataset, project
+// and the ancestors of the project.
+func pubsubBigQueryTableGrants(config *Config, table, email, userAgent string) (*pubsubDeliveryGrants, error) {
+	project, dataset, tableId, err := parsePubsubBigQueryTable(table)
+	if err != nil {
+		return nil, err
+	}
+	grants := newPubsubDeliveryGrants(email)
+	client := config.NewBigQueryClient(userAgent)
+
+	policy, err := client.Tables.GetIamPolicy(fmt.Sprintf("projects/%s/datasets/%s/tables/%s", project, dataset, tableId), &bigquery.GetIamPolicyRequest{
+		Options: &bigquery.GetPolicyOptions{RequestedPolicyVersion: iamPolicyVersion},
+	}).Do()
+	if err != nil {
+		return nil, err
+	}
+	for _, b := range policy.Bindings {
+		grants.addBinding(b.Role, b.Members, b.Condition != nil)
+	}
+
+	ds, err := client.Datasets.Get(project, dataset).Do()
+	if err != nil {
+		return nil, err
+	}
+	for _, a := range ds.Access {
+		role := a.Role
+		if legacy, ok := bigqueryDatasetLegacyRoles[role]; ok {
+			role = legacy
+		}
+		var member string
+		switch {
+		case a.UserByEmail != "" && strings.EqualFold(a.UserByEmail, email):
+			member = grants.member
+		case a.GroupByEmail != "":
+			member = "group:" + a.GroupByEmail
+		case a.Domain != "":
+			member = "domain:" + a.Domain
+		case a.SpecialGroup != "":
+			member = "specialGroup:" + a.SpecialGroup
+		case a.IamMember != "":
+			member = a.IamMember
+		default:
+			continue
+		}
+		grants.addBinding(role, []string{member}, false)
+	}
+
+	if err := addPubsubProjectGrants(config, grants, project, userAgent); err != nil {
+		return nil, err
+	}
+	return grants, nil
+}
+
+// pubsubStorageBucketGrants returns the grants on bucket, its project and the
+// ancestors of the project.
+func pubsubStorageBucketGrants(config *Config, bucket, email, userAgent string) (*pubsubDeliveryGrants, error) {
+	grants := newPubsubDeliveryGrants(email)
+	client := config.NewStorageClient(userAgent)
+
+	b, err := client.Buckets.Get(bucket).Do()
+	if err != nil {
+		return nil, err
+	}
+	if b.IamConfiguration == nil || b.IamConfiguration.UniformBucketLevelAccess == nil || !b.IamConfiguration.UniformBucketLevelAccess.Enabled {
+		grants.unknown = "the ACLs of the bucket"
+	}
+	policy, err := client.Buckets.GetIamPolicy(bucket).OptionsRequestedPolicyVersion(iamPolicyVersion).Do()
+	if err != nil {
+		return nil, err
+	}
+	for _, binding := range policy.Bindings {
+		grants.addBinding(binding.Role, binding.Members, binding.Condition != nil)
+	}
+
+	if err := addPubsubProjectGrants(config, grants, fmt.Sprintf("%d", b.ProjectNumber), userAgent); err != nil {
+		return nil, err
+	}
+	return grants, nil
+}
+
+// addPubsubProjectGrants adds the grants on project and its folders and
+// organization to grants.
+func addPubsubProjectGrants(config *Config, grants *pubsubDeliveryGrants, project, userAgent string) error {
+	ancestry, err := iamEffectivePolicyProjectAncestry(config, userAgent, project)
+	if err != nil {
+		return err
+	}
+	for _, name := range ancestry {
+		switch {
+		case strings.HasPrefix(name, "projects/"):
+			policy, err := config.NewResourceManagerClient(userAgent).Projects.GetIamPolicy(strings.TrimPrefix(name, "projects/"), &cloudresourcemanager.GetIamPolicyRequest{
+				Options: &cloudresourcemanager.GetPolicyOptions{RequestedPolicyVersion: iamPolicyVersion},
+			}).Do()
+			if err != nil {
+				return err
+			}
+			for _, b := range policy.Bindings {
+				grants.addBinding(b.Role, b.Members, b.Condition != nil)
+			}
+		case strings.HasPrefix(name, "folders/"):
+			policy, err := config.NewResourceManagerV2Client(userAgent).Folders.GetIamPolicy(name, &resourceManagerV2.GetIamPolicyRequest{
+				Options: &resourceManagerV2.GetPolicyOptions{RequestedPolicyVersion: iamPolicyVersion},
+			}).Do()
+			if err != nil {
+				return err
+			}
+			for _, b := range policy.Bindings {
+				grants.addBinding(b.Role, b.Members, b.Condition != nil)
+			}
+		case strings.HasPrefix(name, "organizations/"):
+			policy, err := config.NewResourceManagerClient(userAgent).Organizations.GetIamPolicy(name, &cloudresourcemanager.GetIamPolicyRequest{
+				Options: &cloudresourcemanager.GetPolicyOptions{RequestedPolicyVersion: iamPolicyVersion},
+			}).Do()
+			if err != nil {
+				return err
+			}
+			for _, b := range policy.Bindings {
+				grants.addBinding(b.Role, b.Members, b.Condition != nil)
+			}
+		}
+	}
+	return nil
+}
+
+// iamRolePermissionsFunc returns a function looking up the permissions of
+// predefined and custom roles, caching them across calls.
+func iamRolePermissionsFunc(config *Config, userAgent string) func(string) ([]string, error) {
+	client := config.NewIamClient(userAgent)
+	cache := make(map[string][]string)
+	return func(role string) ([]string, error) {
+		if permissions, ok := cache[role]; ok {
+			return permissions, nil
+		}
+		var r *iam.Role
+		var err error
+		switch {
+		case strings.HasPrefix(role, "projects/"):
+			r, err = client.Projects.Roles.Get(role).Do()
+		case strings.HasPrefix(role, "organizations/"):
+			r, err = client.Organizations.Roles.Get(role).Do()
+		default:
+			r, err = client.Roles.Get(role).Do()
+		}
+		if err != nil {
+			return nil, fmt.Errorf("Error reading IAM role %s: %s", role, err)
+		}
+		cache[role] = r.IncludedPermissions
+		return r.IncludedPermissions, nil
+	}
+}
+
+// iamMissingPermissions returns the permissions in required that none of
+// roles grants.
+func iamMissingPermissions(required, roles []string, permissions func(string) ([]string, error)) ([]string, error) {
+	granted := make(map[string]bool)
+	for _, role := range roles {
+		p, err := permissions(role)
+		if err != nil {
+			return nil, err
+		}
+		for _, permission := range p {
+			granted[permission] = true
+		}
+	}
+
+	var missing []string
+	for _, permission := range required {
+		if !granted[permission] {
+			missing = append(missing, permission)
+		}
+	}
+	return missing, nil
+}
+
+// The BigQuery and Cloud Storage delivery of subscriptions are added to the
+// generated google_pubsub_subscription through its schema, pre-create,
+// pre-update and decoder hooks.
+
+func pubsubSubscriptionBigqueryConfigSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeList,
+		Optional: true,
+		Description: `If delivery to BigQuery is used with this subscription, this field is used to configure it.
+Either pushConfig, bigQueryConfig or cloudStorageConfig can be set, but not combined.
+If all three are empty, then the subscriber will pull and ack messages using API methods.`,
+		MaxItems:      1,
+		ConflictsWith: []string{"push_config", "cloud_storage_config"},
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"table": {
+					Type:     schema.TypeString,
+					Required: true,
+					Description: `The name of the table to which to write data, of the form {projectId}:{datasetId}.{tableId}.
+The Pub/Sub service agent of the subscription's project must be able to read the table metadata and
+write data to it.`,
+				},
+				"drop_unknown_fields": {
+					Type:     schema.TypeBool,
+					Optional: true,
+					Description: `When true and use_topic_schema is true, any fields that are a part of the topic schema that are not part of the BigQuery table schema are dropped when writing to BigQuery.
+Otherwise, the schemas must be kept in sync and any messages with extra fields are not written and remain in the subscription's backlog.`,
+				},
+				"use_topic_schema": {
+					Type:        schema.TypeBool,
+					Optional:    true,
+					Description: `When true, use the topic's schema as the columns to write to in BigQuery, if it exists.`,
+				},
+				"write_metadata": {
+					Type:     schema.TypeBool,
+					Optional: true,
+					Description: `When true, write the subscription name, messageId, publishTime, attributes, and orderingKey to additional columns in the table.
+The subscription name, messageId, and publishTime fields are put in their own columns while all other message properties (other than data) are written to a JSON object in the attributes column.`,
+				},
+			},
+		},
+	}
+}
+
+func pubsubSubscriptionCloudStorageConfigSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeList,
+		Optional: true,
+		Description: `If delivery to Cloud Storage is used with this subscription, this field is used to configure it.
+Either pushConfig, bigQueryConfig or cloudStorageConfig can be set, but not combined.
+If all three are empty, then the subscriber will pull and ack messages using API methods.`,
+		MaxItems:      1,
+		ConflictsWith: []string{"push_config", "bigquery_config"},
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"bucket": {
+					Type:     schema.TypeString,
+					Required: true,
+					Description: `User-provided name for the Cloud Storage bucket. The bucket must be created by the user. The bucket name must be without any prefix like "gs://".
+The Pub/Sub service agent of the subscription's project must be able to read the bucket metadata and
+create objects in it.`,
+				},
+				"avro_config": {
+					Type:     schema.TypeList,
+					Optional: true,
+					Description: `If set, message data will be written to Cloud Storage in Avro format. Otherwise, message data
+is written as raw text, separated by a newline.`,
+					MaxItems: 1,
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"write_metadata": {
+								Type:        schema.TypeBool,
+								Optional:    true,
+								Description: `When true, write the subscription name, messageId, publishTime, attributes, and orderingKey as additional fields in the output.`,
+							},
+						},
+					},
+				},
+				"filename_prefix": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					Description: `User-provided prefix for Cloud Storage filename.`,
+				},
+				"filename_suffix": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					Description: `User-provided suffix for Cloud Storage filename. Must not end in "/".`,
+				},
+				"max_bytes": {
+					Type:     schema.TypeInt,
+					Optional: true,
+					Description: `The maximum bytes that can be written to a Cloud Storage file before a new file is created. Min 1 KB, max 10 GiB.
+The maxBytes limit may be exceeded in cases where messages are larger than the limit.`,
+				},
+				"max_duration": {
+					Type:             schema.TypeString,
+					Optional:         true,
+					DiffSuppressFunc: durationDiffSuppress,
+					Description: `The maximum duration that can elapse before a new Cloud Storage file is created. Min 1 minute, max 10 minutes, default 5 minutes.
+May not exceed the subscription's acknowledgement deadline.
+A duration in seconds with up to nine fractional digits, ending with 's'. Example: "3.5s".`,
+					Default: "300s",
+				},
+			},
+		},
+	}
+}
+
+// pubsubSubscriptionDeliveryConfigsPreCreate adds the delivery configs to the
+// subscription to create, once the Pub/Sub service agent is known to be able
+// to deliver to them.
+func pubsubSubscriptionDeliveryConfigsPreCreate(d *schema.ResourceData, config *Config, obj map[string]interface{}, project, userAgent string) error {
+	if err := expandPubsubSubscriptionDeliveryConfigs(d, config, obj); err != nil {
+		return err
+	}
+	return checkPubsubSubscriptionDeliveryPermissions(d, config, project, userAgent)
+}
+
+// pubsubSubscriptionDeliveryConfigsPreUpdate adds the changed delivery
+// configs to the subscription update in obj and to the update mask of
+// rawURL, returning the URL to send the update to.
+func pubsubSubscriptionDeliveryConfigsPreUpdate(d *schema.ResourceData, config *Config, obj map[string]interface{}, rawURL, project, userAgent string) (string, error) {
+	if !d.HasChanges("bigquery_config", "cloud_storage_config") {
+		return rawURL, nil
+	}
+
+	subscription, ok := obj["subscription"].(map[string]interface{})
+	if !ok {
+		return "", fmt.Errorf("expected the subscription to update, got %#v", obj)
+	}
+	if err := expandPubsubSubscriptionDeliveryConfigs(d, config, subscription); err != nil {
+		return "", err
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", err
+	}
+	q := u.Query()
+	var updateMask []string
+	if m := q.Get("updateMask"); m != "" {
+		updateMask = strings.Split(m, ",")
+	}
+	if d.HasChange("bigquery_config") {
+		updateMask = append(updateMask, "bigqueryConfig")
+	}
+	if d.HasChange("cloud_storage_config") {
+		updateMask = append(updateMask, "cloudStorageConfig")
+	}
+	q.Set("updateMask", strings.Join(updateMask, ","))
+	u.RawQuery = q.Encode()
+
+	if err := checkPubsubSubscriptionDeliveryPermissions(d, config, project, userAgent); err != nil {
+		return "", err
+	}
+	return u.String(), nil
+}
+
+// expandPubsubSubscriptionDeliveryConfigs sets the configured delivery
+// configs in obj. A removed config is left out, which clears it when it's in
+// the update mask.
+func expandPubsubSubscriptionDeliveryConfigs(d TerraformResourceData, config *Config, obj map[string]interface{}) error {
+	bigqueryConfigProp, err := expandPubsubSubscriptionBigqueryConfig(d.Get("bigquery_config"), d, config)
+	if err != nil {
+		return err
+	} else if v, ok := d.GetOkExists("bigquery_config"); !isEmptyValue(reflect.ValueOf(bigqueryConfigProp)) && (ok || !reflect.DeepEqual(v, bigqueryConfigProp)) {
+		obj["bigqueryConfig"] = bigqueryConfigProp
+	}
+	cloudStorageConfigProp, err := expandPubsubSubscriptionCloudStorageConfig(d.Get("cloud_storage_config"), d, config)
+	if err != nil {
+		return err
+	} else if v, ok := d.GetOkExists("cloud_storage_config"); !isEmptyValue(reflect.ValueOf(cloudStorageConfigProp)) && (ok || !reflect.DeepEqual(v, cloudStorageConfigProp)) {
+		obj["cloudStorageConfig"] = cloudStorageConfigProp
+	}
+	return nil
+}
+
+// flattenPubsubSubscriptionDeliveryConfigs reads the delivery configs of the
+// subscription in res into d.
+func flattenPubsubSubscriptionDeliveryConfigs(d *schema.ResourceData, res map[string]interface{}, config *Config) error {
+	if err := d.Set("bigquery_config", flattenPubsubSubscriptionBigqueryConfig(res["bigqueryConfig"], d, config)); err != nil {
+		return fmt.Errorf("Error reading Subscription: %s", err)
+	}
+	if err := d.Set("cloud_storage_config", flattenPubsubSubscriptionCloudStorageConfig(res["cloudStorageConfig"], d, config)); err != nil {
+		return fmt.Errorf("Error reading Subscription: %s", err)
+	}
+	return nil
+}
+
+func flattenPubsubSubscriptionBigqueryConfig(v interface{}, d *schema.ResourceData, config *Config) interface{} {
+	if v == nil {
+		return nil
+	}
+	original := v.(map[string]interface{})
+	if len(original) == 0 {
+		return nil
+	}
+	transformed := make(map[string]interface{})
+	transformed["table"] =
+		flattenPubsubSubscriptionBigqueryConfigTable(original["table"], d, config)
+	transformed["use_topic_schema"] =
+		flattenPubsubSubscriptionBigqueryConfigUseTopicSchema(original["useTopicSchema"], d, config)
+	transformed["write_metadata"] =
+		flattenPubsubSubscriptionBigqueryConfigWriteMetadata(original["writeMetadata"], d, config)
+	transformed["drop_unknown_fields"] =
+		flattenPubsubSubscriptionBigqueryConfigDropUnknownFields(original["dropUnknownFields"], d, config)
+	return []interface{}{transformed}
+}
+func flattenPubsubSubscriptionBigqueryConfigTable(v interface{}, d *schema.ResourceData, config *Config) interface{} {
+	return v
+}
+
+func flattenPubsubSubscriptionBigqueryConfigUseTopicSchema(v interface{}, d *schema.ResourceData, config *Config) interface{} {
+	return v
+}
+
+func flattenPubsubSubscriptionBigqueryConfigWriteMetadata(v interface{}, d *schema.ResourceData, config *Config) interface{} {
+	return v
+}
+
+func flattenPubsubSubscriptionBigqueryConfigDropUnknownFields(v interface{}, d *schema.ResourceData, config *Config) interface{} {
+	return v
+}
+
+func flattenPubsubSubscriptionCloudStorageConfig(v interface{}, d *schema.ResourceData, config *Config) interface{} {
+	if v == nil {
+		return nil
+	}
+	original := v.(map[string]interface{})
+	if len(original) == 0 {
+		return nil
+	}
+	transformed := make(map[string]interface{})
+	transformed["bucket"] =
+		flattenPubsubSubscriptionCloudStorageConfigBucket(original["bucket"], d, config)
+	transformed["filename_prefix"] =
+		flattenPubsubSubscriptionCloudStorageConfigFilenamePrefix(original["filenamePrefix"], d, config)
+	transformed["filename_suffix"] =
+		flattenPubsubSubscriptionCloudStorageConfigFilenameSuffix(original["filenameSuffix"], d, config)
+	transformed["max_duration"] =
+		flattenPubsubSubscriptionCloudStorageConfigMaxDuration(original["maxDuration"], d, config)
+	transformed["max_bytes"] =
+		flattenPubsubSubscriptionCloudStorageConfigMaxBytes(original["maxBytes"], d, config)
+	transformed["avro_config"] =
+		flattenPubsubSubscriptionCloudStorageConfigAvroConfig(original["avroConfig"], d, config)
+	return []interface{}{transformed}
+}
+func flattenPubsubSubscriptionCloudStorageConfigBucket(v interface{}, d *schema.ResourceData, config *Config) interface{} {
+	return v
+}
+
+func flattenPubsubSubscriptionCloudStorageConfigFilenamePrefix(v interface{}, d *schema.ResourceData, config *Config) interface{} {
+	return v
+}
+
+func flattenPubsubSubscriptionCloudStorageConfigFilenameSuffix(v interface{}, d *schema.ResourceData, config *Config) interface{} {
+	return v
+}
+
+func flattenPubsubSubscriptionCloudStorageConfigMaxDuration(v interface{}, d *schema.ResourceData, config *Config) interface{} {
+	return v
+}
+
+func flattenPubsubSubscriptionCloudStorageConfigMaxBytes(v interface{}, d *schema.ResourceData, config *Config) interface{} {
+	// Handles the string fixed64 format
+	if strVal, ok := v.(string); ok {
+		if intVal, err := stringToFixed64(strVal); err == nil {
+			return intVal
+		}
+	}
+
+	// number values are represented as float64
+	if floatVal, ok := v.(float64); ok {
+		intVal := int(floatVal)
+		return intVal
+	}
+
+	return v // let terraform core handle it otherwise
+}
+
+// An avroConfig without write_metadata is returned as an empty object, which
+// still selects the Avro output format.
+func flattenPubsubSubscriptionCloudStorageConfigAvroConfig(v interface{}, d *schema.ResourceData, config *Config) interface{} {
+	if v == nil {
+		return nil
+	}
+	original := v.(map[string]interface{})
+	transformed := make(map[string]interface{})
+	transformed["write_metadata"] =
+		flattenPubsubSubscriptionCloudStorageConfigAvroConfigWriteMetadata(original["writeMetadata"], d, config)
+	return []interface{}{transformed}
+}
+func flattenPubsubSubscriptionCloudStorageConfigAvroConfigWriteMetadata(v interface{}, d *schema.ResourceData, config *Config) interface{} {
+	return v
+}
+
+func expandPubsubSubscriptionBigqueryConfig(v interface{}, d TerraformResourceData, config *Config) (interface{}, error) {
+	l := v.([]interface{})
+	if len(l) == 0 || l[0] == nil {
+		return nil, nil
+	}
+	raw := l[0]
+	original := raw.(map[string]interface{})
+	transformed := make(map[string]interface{})
+
+	transformedTable, err := expandPubsubSubscriptionBigqueryConfigTable(original["table"], d, config)
+	if err != nil {
+		return nil, err
+	} else if val := reflect.ValueOf(transformedTable); val.IsValid() && !isEmptyValue(val) {
+		transformed["table"] = transformedTable
+	}
+
+	transformedUseTopicSchema, err := expandPubsubSubscriptionBigqueryConfigUseTopicSchema(original["use_topic_schema"], d, config)
+	if err != nil {
+		return nil, err
+	} else if val := reflect.ValueOf(transformedUseTopicSchema); val.IsValid() && !isEmptyValue(val) {
+		transformed["useTopicSchema"] = transformedUseTopicSchema
+	}
+
+	transformedWriteMetadata, err := expandPubsubSubscriptionBigqueryConfigWriteMetadata(original["write_metadata"], d, config)
+	if err != nil {
+		return nil, err
+	} else if val := reflect.ValueOf(transformedWriteMetadata); val.IsValid() && !isEmptyValue(val) {
+		transformed["writeMetadata"] = transformedWriteMetadata
+	}
+
+	transformedDropUnknownFields, err := expandPubsubSubscriptionBigqueryConfigDropUnknownFields(original["drop_unknown_fields"], d, config)
+	if err != nil {
+		return nil, err
+	} else if val := reflect.ValueOf(transformedDropUnknownFields); val.IsValid() && !isEmptyValue(val) {
+		transformed["dropUnknownFields"] = transformedDropUnknownFields
+	}
+
+	return transformed, nil
+}
+
+func expandPubsubSubscriptionBigqueryConfigTable(v interface{}, d TerraformResourceData, config *Config) (interface{}, error) {
+	return v, nil
+}
+
+func expandPubsubSubscriptionBigqueryConfigUseTopicSchema(v interface{}, d TerraformResourceData, config *Config) (interface{}, error) {
+	return v, nil
+}
+
+func expandPubsubSubscriptionBigqueryConfigWriteMetadata(v interface{}, d TerraformResourceData, config *Config) (interface{}, error) {
+	return v, nil
+}
+
+func expandPubsubSubscriptionBigqueryConfigDropUnknownFields(v interface{}, d TerraformResourceData, config *Config) (interface{}, error) {
+	return v, nil
+}
+
+func expandPubsubSubscriptionCloudStorageConfig(v interface{}, d TerraformResourceData, config *Config) (interface{}, error) {
+	l := v.([]interface{})
+	if len(l) == 0 || l[0] == nil {
+		return nil, nil
+	}
+	raw := l[0]
+	original := raw.(map[string]interface{})
+	transformed := make(map[string]interface{})
+
+	transformedBucket, err := expandPubsubSubscriptionCloudStorageConfigBucket(original["bucket"], d, config)
+	if err != nil {
+		return nil, err
+	} else if val := reflect.ValueOf(transformedBucket); val.IsValid() && !isEmptyValue(val) {
+		transformed["bucket"] = transformedBucket
+	}
+
+	transformedFilenamePrefix, err := expandPubsubSubscriptionCloudStorageConfigFilenamePrefix(original["filename_prefix"], d, config)
+	if err != nil {
+		return nil, err
+	} else if val := reflect.ValueOf(transformedFilenamePrefix); val.IsValid() && !isEmptyValue(val) {
+		transformed["filenamePrefix"] = transformedFilenamePrefix
+	}
+
+	transformedFilenameSuffix, err := expandPubsubSubscriptionCloudStorageConfigFilenameSuffix(original["filename_suffix"], d, config)
+	if err != nil {
+		return nil, err
+	} else if val := reflect.ValueOf(transformedFilenameSuffix); val.IsValid() && !isEmptyValue(val) {
+		transformed["filenameSuffix"] = transformedFilenameSuffix
+	}
+
+	transformedMaxDuration, err := expandPubsubSubscriptionCloudStorageConfigMaxDuration(original["max_duration"], d, config)
+	if err != nil {
+		return nil, err
+	} else if val := reflect.ValueOf(transformedMaxDuration); val.IsValid() && !isEmptyValue(val) {
+		transformed["maxDuration"] = transformedMaxDuration
+	}
+
+	transformedMaxBytes, err := expandPubsubSubscriptionCloudStorageConfigMaxBytes(original["max_bytes"], d, config)
+	if err != nil {
+		return nil, err
+	} else if val := reflect.ValueOf(transformedMaxBytes); val.IsValid() && !isEmptyValue(val) {
+		transformed["maxBytes"] = transformedMaxBytes
+	}
+
+	// The output format is a oneof of two messages, and an empty avroConfig
+	// still selects Avro, so it's sent even when empty.
+	transformedAvroConfig, err := expandPubsubSubscriptionCloudStorageConfigAvroConfig(original["avro_config"], d, config)
+	if err != nil {
+		return nil, err
+	} else if transformedAvroConfig != nil {
+		transformed["avroConfig"] = transformedAvroConfig
+	} else {
+		transformed["textConfig"] = map[string]interface{}{}
+	}
+
+	return transformed, nil
+}
+
+func expandPubsubSubscriptionCloudStorageConfigBucket(v interface{}, d TerraformResourceData, config *Config) (interface{}, error) {
+	return v, nil
+}
+
+func expandPubsubSubscriptionCloudStorageConfigFilenamePrefix(v interface{}, d TerraformResourceData, config *Config) (interface{}, error) {
+	return v, nil
+}
+
+func expandPubsubSubscriptionCloudStorageConfigFilenameSuffix(v interface{}, d TerraformResourceData, config *Config) (interface{}, error) {
+	return v, nil
+}
+
+func expandPubsubSubscriptionCloudStorageConfigMaxDuration(v interface{}, d TerraformResourceData, config *Config) (interface{}, error) {
+	return v, nil
+}
+
+func expandPubsubSubscriptionCloudStorageConfigMaxBytes(v interface{}, d TerraformResourceData, config *Config) (interface{}, error) {
+	return v, nil
+}
+
+func expandPubsubSubscriptionCloudStorageConfigAvroConfig(v interface{}, d TerraformResourceData, config *Config) (map[string]interface{}, error) {
+	l := v.([]interface{})
+	if len(l) == 0 {
+		return nil, nil
+	}
+	transformed := make(map[string]interface{})
+	if l[0] == nil {
+		return transformed, nil
+	}
+	original := l[0].(map[string]interface{})
+
+	transformedWriteMetadata, err := expandPubsubSubscriptionCloudStorageConfigAvroConfigWriteMetadata(original["write_metadata"], d, config)
+	if err != nil {
+		return nil, err
+	} else if val := reflect.ValueOf(transformedWriteMetadata); val.IsValid() && !isEmptyValue(val) {
+		transformed["writeMetadata"] = transformedWriteMetadata
+	}
+
+	return transformed, nil
+}
+
+func expandPubsubSubscriptionCloudStorageConfigAvroConfigWriteMetadata(v interface{}, d TerraformResourceData, config *Config) (interface{}, error) {
+	return v, nil
+}
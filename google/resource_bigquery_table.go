@@ -328,6 +328,16 @@ func resourceBigQueryTableSchemaCustomizeDiffFunc(d TerraformResourceDiff) error
 		if err != nil {
 			return err
 		}
+
+		// With schema_migration, changes to an existing schema are run as
+		// DDL statements instead of recreating the table, see
+		// resourceBigQueryTableSchemaMigrationCustomizeDiff.
+		_, oldOk := old.([]interface{})
+		_, newOk := new.([]interface{})
+		if _, ok := d.GetOk("schema_migration"); ok && oldOk && newOk {
+			return nil
+		}
+
 		if !isChangeable {
 			if err := d.ForceNew("schema"); err != nil {
 				return err
@@ -353,6 +363,7 @@ func resourceBigQueryTable() *schema.Resource {
 		},
 		CustomizeDiff: customdiff.All(
 			resourceBigQueryTableSchemaCustomizeDiff,
+			resourceBigQueryTableSchemaMigrationCustomizeDiff,
 		),
 		Schema: map[string]*schema.Schema{
 			// TableId: [Required] The ID of the table. The ID must contain only
@@ -918,6 +929,34 @@ func resourceBigQueryTable() *schema.Resource {
 				Description: `Describes the table type.`,
 			},
 
+			// SchemaMigration: [Optional] Applies changes to the schema that
+			// would otherwise recreate the table with DDL statements.
+			"schema_migration": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				MaxItems:    1,
+				Description: `When set, dropped columns, renamed columns and widened column types are applied to the existing table with DDL statements instead of recreating it.`,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"column_renames": {
+							Type:        schema.TypeMap,
+							Optional:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+							Description: `Columns of the current schema to rename, mapped to their new names. Without an entry, a column missing from the new schema is dropped.`,
+						},
+					},
+				},
+			},
+
+			// SchemaMigrationDdl: [Output-only] The DDL statements of the last
+			// schema migration.
+			"schema_migration_ddl": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: `The DDL statements run by the last schema migration. The plan shows the statements of a pending migration.`,
+			},
+
 			"deletion_protection": {
 				Type:        schema.TypeBool,
 				Optional:    true,
@@ -1251,6 +1290,14 @@ func resourceBigQueryTableUpdate(d *schema.ResourceData, meta interface{}) error
 	datasetID := d.Get("dataset_id").(string)
 	tableID := d.Get("table_id").(string)
 
+	if _, ok := d.GetOk("schema_migration"); ok && d.HasChange("schema") {
+		statements := convertStringArr(d.Get("schema_migration_ddl").([]interface{}))
+		log.Printf("[INFO] Migrating the schema of BigQuery table %s: %v", d.Id(), statements)
+		if err := bigQueryTableRunDDL(config, d, userAgent, project, datasetID, statements); err != nil {
+			return fmt.Errorf("Error migrating the schema of BigQuery table %s: %s", d.Id(), err)
+		}
+	}
+
 	if _, err = config.NewBigQueryClient(userAgent).Tables.Update(project, datasetID, tableID, table).Do(); err != nil {
 		return err
 	}
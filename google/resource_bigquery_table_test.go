@@ -3,6 +3,7 @@ package google
 import (
 	"encoding/json"
 	"fmt"
+	"reflect"
 	"regexp"
 	"strings"
 	"testing"
@@ -1046,6 +1047,69 @@ func TestAccBigQueryTable_emptySchema(t *testing.T) {
 	})
 }
 
+func TestAccBigQueryTable_schemaMigration(t *testing.T) {
+	t.Parallel()
+
+	datasetID := fmt.Sprintf("tf_test_%s", randString(t, 10))
+	tableID := fmt.Sprintf("tf_test_%s", randString(t, 10))
+	var creationTime string
+
+	vcrTest(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckBigQueryTableDestroyProducer(t),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccBigQueryTable_schemaMigration(datasetID, tableID, `[
+  {"name": "id", "type": "INTEGER"},
+  {"name": "amount", "type": "INTEGER"},
+  {"name": "city", "type": "STRING"},
+  {"name": "obsolete", "type": "STRING"}
+]`),
+				Check: testAccCheckBigQueryTableCreationTime("google_bigquery_table.test", &creationTime),
+			},
+			{
+				Config: testAccBigQueryTable_schemaMigration(datasetID, tableID, `[
+  {"name": "id", "type": "INTEGER"},
+  {"name": "amount", "type": "NUMERIC"},
+  {"name": "town", "type": "STRING"},
+  {"name": "country", "type": "STRING"}
+]`),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckBigQueryTableCreationTime("google_bigquery_table.test", &creationTime),
+					resource.TestCheckResourceAttr("google_bigquery_table.test", "schema_migration_ddl.#", "3"),
+				),
+			},
+			{
+				ResourceName:            "google_bigquery_table.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"deletion_protection", "schema_migration", "schema_migration_ddl"},
+			},
+		},
+	})
+}
+
+// testAccCheckBigQueryTableCreationTime checks that the table keeps the
+// creation time recorded by the first check, so it wasn't recreated.
+func testAccCheckBigQueryTableCreationTime(n string, creationTime *string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+		got := rs.Primary.Attributes["creation_time"]
+		if *creationTime == "" {
+			*creationTime = got
+			return nil
+		}
+		if got != *creationTime {
+			return fmt.Errorf("expected the table to keep creation time %s, got %s", *creationTime, got)
+		}
+		return nil
+	}
+}
+
 type testUnitBigQueryDataTableJSONChangeableTestCase struct {
 	name       string
 	jsonOld    string
@@ -1220,6 +1284,147 @@ func TestUnitBigQueryDataTable_schemaIsChangable(t *testing.T) {
 	}
 }
 
+func TestUnitBigQueryDataTable_schemaMigration(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name       string
+		jsonOld    string
+		jsonNew    string
+		renames    map[string]string
+		statements []string
+		expectErr  bool
+	}{
+		{
+			name:    "addedColumn",
+			jsonOld: `[{"name": "a", "type": "STRING"}]`,
+			jsonNew: `[{"name": "a", "type": "STRING"}, {"name": "b", "type": "STRING"}]`,
+		},
+		{
+			name:       "droppedColumn",
+			jsonOld:    `[{"name": "a", "type": "STRING"}, {"name": "b", "type": "STRING"}]`,
+			jsonNew:    `[{"name": "a", "type": "STRING"}]`,
+			statements: []string{"ALTER TABLE `p.d.t` DROP COLUMN `b`"},
+		},
+		{
+			name:       "renamedColumn",
+			jsonOld:    `[{"name": "a", "type": "STRING", "mode": "REQUIRED"}]`,
+			jsonNew:    `[{"name": "b", "type": "STRING", "mode": "NULLABLE"}]`,
+			renames:    map[string]string{"a": "b"},
+			statements: []string{"ALTER TABLE `p.d.t` RENAME COLUMN `a` TO `b`"},
+		},
+		{
+			name:       "renamedAndWidenedColumn",
+			jsonOld:    `[{"name": "a", "type": "INTEGER"}, {"name": "c", "type": "STRING"}]`,
+			jsonNew:    `[{"name": "b", "type": "FLOAT"}]`,
+			renames:    map[string]string{"a": "b"},
+			statements: []string{"ALTER TABLE `p.d.t` DROP COLUMN `c`", "ALTER TABLE `p.d.t` RENAME COLUMN `a` TO `b`", "ALTER TABLE `p.d.t` ALTER COLUMN `b` SET DATA TYPE FLOAT64"},
+		},
+		{
+			name:    "renameAlreadyApplied",
+			jsonOld: `[{"name": "b", "type": "STRING"}]`,
+			jsonNew: `[{"name": "b", "type": "STRING"}]`,
+			renames: map[string]string{"a": "b"},
+		},
+		{
+			name:      "narrowedType",
+			jsonOld:   `[{"name": "a", "type": "NUMERIC"}]`,
+			jsonNew:   `[{"name": "a", "type": "INT64"}]`,
+			expectErr: true,
+		},
+		{
+			name:      "incompatibleType",
+			jsonOld:   `[{"name": "a", "type": "STRING"}]`,
+			jsonNew:   `[{"name": "a", "type": "INTEGER"}]`,
+			expectErr: true,
+		},
+		{
+			name:      "requiredColumn",
+			jsonOld:   `[{"name": "a", "type": "STRING", "mode": "NULLABLE"}]`,
+			jsonNew:   `[{"name": "a", "type": "STRING", "mode": "REQUIRED"}]`,
+			expectErr: true,
+		},
+		{
+			name:      "addedRequiredColumn",
+			jsonOld:   `[{"name": "a", "type": "STRING"}]`,
+			jsonNew:   `[{"name": "b", "type": "STRING", "mode": "REQUIRED"}]`,
+			expectErr: true,
+		},
+		{
+			name:      "droppedNestedColumn",
+			jsonOld:   `[{"name": "a", "type": "RECORD", "fields": [{"name": "x", "type": "STRING"}, {"name": "y", "type": "STRING"}]}]`,
+			jsonNew:   `[{"name": "a", "type": "RECORD", "fields": [{"name": "x", "type": "STRING"}]}]`,
+			expectErr: true,
+		},
+		{
+			name:      "renamedToExistingColumn",
+			jsonOld:   `[{"name": "a", "type": "STRING"}, {"name": "b", "type": "STRING"}]`,
+			jsonNew:   `[{"name": "b", "type": "STRING"}]`,
+			renames:   map[string]string{"a": "b"},
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range cases {
+		var old, new []interface{}
+		if err := json.Unmarshal([]byte(tc.jsonOld), &old); err != nil {
+			t.Fatalf("unable to unmarshal json - %v", err)
+		}
+		if err := json.Unmarshal([]byte(tc.jsonNew), &new); err != nil {
+			t.Fatalf("unable to unmarshal json - %v", err)
+		}
+		statements, err := bigQueryTableSchemaMigration("p.d.t", old, new, tc.renames)
+		if tc.expectErr {
+			if err == nil {
+				t.Errorf("%s: expected error, got statements %v", tc.name, statements)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", tc.name, err)
+			continue
+		}
+		if strings.Join(statements, "\n") != strings.Join(tc.statements, "\n") {
+			t.Errorf("%s: expected statements %v, got %v", tc.name, tc.statements, statements)
+		}
+	}
+}
+
+func TestUnitBigQueryDataTable_schemaMigrationCustomizeDiff(t *testing.T) {
+	t.Parallel()
+
+	d := &ResourceDiffMock{
+		Before: map[string]interface{}{
+			"schema": `[{"name": "a", "type": "STRING"}, {"name": "b", "type": "STRING"}]`,
+		},
+		After: map[string]interface{}{
+			"schema":           `[{"name": "a", "type": "STRING"}]`,
+			"schema_migration": []interface{}{nil},
+			"project":          "p",
+			"dataset_id":       "d",
+			"table_id":         "t",
+		},
+	}
+	if err := resourceBigQueryTableSchemaCustomizeDiffFunc(d); err != nil {
+		t.Fatal(err)
+	}
+	if d.IsForceNew {
+		t.Errorf("expected the schema migration to keep the table")
+	}
+	statements, migrated, err := bigQueryTableSchemaMigrationDdl(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !migrated || !reflect.DeepEqual(statements, []string{"ALTER TABLE `p.d.t` DROP COLUMN `b`"}) {
+		t.Errorf("unexpected schema_migration_ddl %v", statements)
+	}
+
+	d.After["schema"] = `[{"name": "a", "type": "INTEGER"}]`
+	if _, _, err := bigQueryTableSchemaMigrationDdl(d); err == nil {
+		t.Errorf("expected an error for a schema change that can't be migrated")
+	}
+}
+
 func testAccCheckBigQueryExtData(t *testing.T, expectedQuoteChar string) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		for _, rs := range s.RootModule().Resources {
@@ -2274,6 +2479,30 @@ resource "google_bigquery_table" "test" {
 `, datasetID, tableID)
 }
 
+func testAccBigQueryTable_schemaMigration(datasetID, tableID, schema string) string {
+	return fmt.Sprintf(`
+resource "google_bigquery_dataset" "test" {
+  dataset_id = "%s"
+}
+
+resource "google_bigquery_table" "test" {
+  deletion_protection = false
+  table_id            = "%s"
+  dataset_id          = google_bigquery_dataset.test.dataset_id
+
+  schema_migration {
+    column_renames = {
+      city = "town"
+    }
+  }
+
+  schema = <<EOH
+%s
+EOH
+}
+`, datasetID, tableID, schema)
+}
+
 var TEST_CSV = `lifelock,LifeLock,,web,Tempe,AZ,1-May-07,6850000,USD,b
 lifelock,LifeLock,,web,Tempe,AZ,1-Oct-06,6000000,USD,a
 lifelock,LifeLock,,web,Tempe,AZ,1-Jan-08,25000000,USD,c
@@ -2,10 +2,17 @@ package google
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"log"
+	"reflect"
+	"strings"
+	"time"
 
+	"cloud.google.com/go/bigtable"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/structure"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 func resourceBigtableTable() *schema.Resource {
@@ -42,10 +49,63 @@ func resourceBigtableTable() *schema.Resource {
 							Required:    true,
 							Description: `The name of the column family.`,
 						},
+						"gc_policy": {
+							Type:        schema.TypeList,
+							Optional:    true,
+							MaxItems:    1,
+							Description: `The garbage collection policy of the column family. When absent, the policy isn't managed by this resource.`,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"mode": {
+										Type:         schema.TypeString,
+										Optional:     true,
+										Description:  `If both max_age and max_versions are set, whether cells are collected when they match either (UNION) or both (INTERSECTION) rules.`,
+										ValidateFunc: validation.StringInSlice([]string{GCPolicyModeIntersection, GCPolicyModeUnion}, false),
+									},
+									"max_age": {
+										Type:         schema.TypeString,
+										Optional:     true,
+										Description:  `Cells older than this duration are collected.`,
+										ValidateFunc: validateDuration(),
+									},
+									"max_versions": {
+										Type:         schema.TypeInt,
+										Optional:     true,
+										Description:  `All but the most recent max_versions versions of a cell are collected.`,
+										ValidateFunc: validation.IntAtLeast(1),
+									},
+									"gc_rules": {
+										Type:         schema.TypeString,
+										Optional:     true,
+										Description:  `Serialized JSON string for nested garbage collection policies, in the format of google_bigtable_gc_policy. Conflicts with "mode", "max_age" and "max_versions".`,
+										ValidateFunc: validation.StringIsJSON,
+										StateFunc: func(v interface{}) string {
+											json, _ := structure.NormalizeJsonString(v)
+											return json
+										},
+									},
+								},
+							},
+						},
 					},
 				},
 			},
 
+			"deletion_protection": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.StringInSlice([]string{"PROTECTED", "UNPROTECTED"}, false),
+				Description:  `Whether the table is protected from deletion. A PROTECTED table, its column families and the instance containing it can't be deleted.`,
+			},
+
+			"change_stream_retention": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateDuration(),
+				Description:  `How long the change stream of the table retains data changes, between 1 day and 7 days. The change stream is disabled when unset.`,
+			},
+
 			"instance_name": {
 				Type:             schema.TypeString,
 				Required:         true,
@@ -127,6 +187,9 @@ func resourceBigtableTableCreate(d *schema.ResourceData, meta interface{}) error
 				if err := c.CreateColumnFamily(ctx, name, v.(string)); err != nil {
 					return fmt.Errorf("Error creating column family %s. %s", v, err)
 				}
+				if err := setBigtableTableGCPolicy(ctx, c, d, name, v.(string), column["gc_policy"]); err != nil {
+					return err
+				}
 			}
 		}
 	}
@@ -137,6 +200,12 @@ func resourceBigtableTableCreate(d *schema.ResourceData, meta interface{}) error
 	}
 	d.SetId(id)
 
+	if d.Get("deletion_protection") == "PROTECTED" || d.Get("change_stream_retention") != "" {
+		if err := patchBigtableTable(d, config, userAgent, project, d.Timeout(schema.TimeoutCreate)); err != nil {
+			return err
+		}
+	}
+
 	return resourceBigtableTableRead(d, meta)
 }
 
@@ -172,13 +241,76 @@ func resourceBigtableTableRead(d *schema.ResourceData, meta interface{}) error {
 	if err := d.Set("project", project); err != nil {
 		return fmt.Errorf("Error setting project: %s", err)
 	}
-	if err := d.Set("column_family", flattenColumnFamily(table.Families)); err != nil {
+
+	if !bigtableTableNeedsFullView(d) {
+		families, err := flattenBigtableTableColumnFamilies(d, table.Families, nil)
+		if err != nil {
+			return err
+		}
+		if err := d.Set("column_family", families); err != nil {
+			return fmt.Errorf("Error setting column_family: %s", err)
+		}
+		return nil
+	}
+
+	url := fmt.Sprintf("%sprojects/%s/instances/%s/tables/%s?view=FULL", config.BigtableBasePath, project, instanceName, name)
+	res, err := sendRequest(config, "GET", project, url, userAgent, nil)
+	if err != nil {
+		return handleNotFoundError(err, d, fmt.Sprintf("BigtableTable %q", d.Id()))
+	}
+
+	families, err := flattenBigtableTableColumnFamilies(d, table.Families, res["columnFamilies"])
+	if err != nil {
+		return err
+	}
+	if err := d.Set("column_family", families); err != nil {
 		return fmt.Errorf("Error setting column_family: %s", err)
 	}
 
+	deletionProtection := "UNPROTECTED"
+	if v, _ := res["deletionProtection"].(bool); v {
+		deletionProtection = "PROTECTED"
+	}
+	if err := d.Set("deletion_protection", deletionProtection); err != nil {
+		return fmt.Errorf("Error setting deletion_protection: %s", err)
+	}
+
+	retention := ""
+	if changeStream, ok := res["changeStreamConfig"].(map[string]interface{}); ok {
+		if v, ok := changeStream["retentionPeriod"].(string); ok {
+			duration, err := time.ParseDuration(v)
+			if err != nil {
+				return fmt.Errorf("Error parsing change stream retention %q: %s", v, err)
+			}
+			retention = bigtableTableDurationString(duration)
+		}
+	}
+	if !bigtableTableDurationsEqual(d.Get("change_stream_retention").(string), retention) {
+		if err := d.Set("change_stream_retention", retention); err != nil {
+			return fmt.Errorf("Error setting change_stream_retention: %s", err)
+		}
+	}
+
 	return nil
 }
 
+// bigtableTableNeedsFullView returns whether Read needs the full view of the
+// table, with the GC policies, deletion protection and change stream the admin
+// client doesn't return. It's only read for imported tables and tables using
+// these arguments, so a table protected outside of Terraform isn't detected
+// while it's UNPROTECTED in state.
+func bigtableTableNeedsFullView(d *schema.ResourceData) bool {
+	if d.Get("deletion_protection").(string) != "UNPROTECTED" || d.Get("change_stream_retention").(string) != "" {
+		return true
+	}
+	for _, column := range d.Get("column_family").(*schema.Set).List() {
+		if len(column.(map[string]interface{})["gc_policy"].([]interface{})) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
 func resourceBigtableTableUpdate(d *schema.ResourceData, meta interface{}) error {
 	config := meta.(*Config)
 	userAgent, err := generateUserAgentString(d, config.userAgent)
@@ -200,31 +332,42 @@ func resourceBigtableTableUpdate(d *schema.ResourceData, meta interface{}) error
 	defer c.Close()
 
 	o, n := d.GetChange("column_family")
-	oSet := o.(*schema.Set)
-	nSet := n.(*schema.Set)
+	oFamilies := bigtableTableColumnFamiliesByName(o.(*schema.Set))
+	nFamilies := bigtableTableColumnFamiliesByName(n.(*schema.Set))
 	name := d.Get("name").(string)
 
-	// Add column families that are in new but not in old
-	for _, new := range nSet.Difference(oSet).List() {
-		column := new.(map[string]interface{})
-
-		if v, ok := column["family"]; ok {
-			log.Printf("[DEBUG] adding column family %q", v)
-			if err := c.CreateColumnFamily(ctx, name, v.(string)); err != nil {
-				return fmt.Errorf("Error creating column family %q: %s", v, err)
+	// Add column families that are in new but not in old, and update the
+	// GC policy of the ones whose policy changed. A removed gc_policy block
+	// leaves the policy as it is.
+	for family, column := range nFamilies {
+		old, ok := oFamilies[family]
+		if !ok {
+			log.Printf("[DEBUG] adding column family %q", family)
+			if err := c.CreateColumnFamily(ctx, name, family); err != nil {
+				return fmt.Errorf("Error creating column family %q: %s", family, err)
 			}
+		} else if reflect.DeepEqual(old["gc_policy"], column["gc_policy"]) {
+			continue
+		}
+		if err := setBigtableTableGCPolicy(ctx, c, d, name, family, column["gc_policy"]); err != nil {
+			return err
 		}
 	}
 
 	// Remove column families that are in old but not in new
-	for _, old := range oSet.Difference(nSet).List() {
-		column := old.(map[string]interface{})
+	for family := range oFamilies {
+		if _, ok := nFamilies[family]; ok {
+			continue
+		}
+		log.Printf("[DEBUG] removing column family %q", family)
+		if err := c.DeleteColumnFamily(ctx, name, family); err != nil {
+			return fmt.Errorf("Error deleting column family %q: %s", family, err)
+		}
+	}
 
-		if v, ok := column["family"]; ok {
-			log.Printf("[DEBUG] removing column family %q", v)
-			if err := c.DeleteColumnFamily(ctx, name, v.(string)); err != nil {
-				return fmt.Errorf("Error deleting column family %q: %s", v, err)
-			}
+	if d.HasChange("deletion_protection") || d.HasChange("change_stream_retention") {
+		if err := patchBigtableTable(d, config, userAgent, project, d.Timeout(schema.TimeoutUpdate)); err != nil {
+			return err
 		}
 	}
 
@@ -245,6 +388,10 @@ func resourceBigtableTableDestroy(d *schema.ResourceData, meta interface{}) erro
 		return err
 	}
 
+	if d.Get("deletion_protection") == "PROTECTED" {
+		return fmt.Errorf("cannot destroy table without setting deletion_protection to UNPROTECTED and running `terraform apply`")
+	}
+
 	instanceName := GetResourceNameFromSelfLink(d.Get("instance_name").(string))
 	c, err := config.BigTableClientFactory(userAgent).NewAdminClient(project, instanceName)
 	if err != nil {
@@ -264,16 +411,295 @@ func resourceBigtableTableDestroy(d *schema.ResourceData, meta interface{}) erro
 	return nil
 }
 
-func flattenColumnFamily(families []string) []map[string]interface{} {
+func bigtableTableColumnFamiliesByName(families *schema.Set) map[string]map[string]interface{} {
+	result := make(map[string]map[string]interface{}, families.Len())
+	for _, raw := range families.List() {
+		column := raw.(map[string]interface{})
+		result[column["family"].(string)] = column
+	}
+	return result
+}
+
+// flattenBigtableTableColumnFamilies flattens the column families of a table,
+// along with the GC policies of the families that are managed, or known for
+// the first time, such as on import. The policy in state is kept when the
+// table's policy is equivalent to it.
+func flattenBigtableTableColumnFamilies(d *schema.ResourceData, families []string, columnFamilies interface{}) ([]map[string]interface{}, error) {
+	known := bigtableTableColumnFamiliesByName(d.Get("column_family").(*schema.Set))
+	apiFamilies, _ := columnFamilies.(map[string]interface{})
 	result := make([]map[string]interface{}, 0, len(families))
 
 	for _, f := range families {
 		data := make(map[string]interface{})
 		data["family"] = f
+
+		column, ok := known[f]
+		if ok && len(column["gc_policy"].([]interface{})) == 0 {
+			// The GC policy of this family is unmanaged.
+			result = append(result, data)
+			continue
+		}
+
+		apiFamily, _ := apiFamilies[f].(map[string]interface{})
+		rule, _ := apiFamily["gcRule"].(map[string]interface{})
+		policy, err := flattenBigtableTableGCRule(rule)
+		if err != nil {
+			return nil, fmt.Errorf("Error flattening the GC policy of column family %q: %s", f, err)
+		}
+		if ok && bigtableTableGCPoliciesEquivalent(column["gc_policy"], policy) {
+			policy = column["gc_policy"].([]interface{})
+		}
+		data["gc_policy"] = policy
+
 		result = append(result, data)
 	}
 
-	return result
+	return result, nil
+}
+
+func setBigtableTableGCPolicy(ctx context.Context, c *bigtable.AdminClient, d *schema.ResourceData, table, family string, v interface{}) error {
+	if l, _ := v.([]interface{}); len(l) == 0 {
+		return nil
+	}
+	policy, err := expandBigtableTableGCPolicy(v)
+	if err != nil {
+		return fmt.Errorf("Invalid gc_policy of column family %q: %s", family, err)
+	}
+	err = retryTimeDuration(func() error {
+		return c.SetGCPolicy(ctx, table, family, policy)
+	}, d.Timeout(schema.TimeoutUpdate), isBigTableRetryableError)
+	if err != nil {
+		return fmt.Errorf("Error setting the GC policy of column family %q: %s", family, err)
+	}
+	return nil
+}
+
+// expandBigtableTableGCPolicy expands a gc_policy block. A block without
+// rules means cells are never collected.
+func expandBigtableTableGCPolicy(v interface{}) (bigtable.GCPolicy, error) {
+	l, _ := v.([]interface{})
+	if len(l) == 0 || l[0] == nil {
+		return bigtable.NoGcPolicy(), nil
+	}
+	raw := l[0].(map[string]interface{})
+	mode, _ := raw["mode"].(string)
+	maxAge, _ := raw["max_age"].(string)
+	maxVersions, _ := raw["max_versions"].(int)
+	gcRules, _ := raw["gc_rules"].(string)
+
+	if gcRules != "" {
+		if mode != "" || maxAge != "" || maxVersions != 0 {
+			return nil, fmt.Errorf("gc_rules conflicts with mode, max_age and max_versions")
+		}
+		var j map[string]interface{}
+		if err := json.Unmarshal([]byte(gcRules), &j); err != nil {
+			return nil, err
+		}
+		return getGCPolicyFromJSON(j)
+	}
+
+	var policies []bigtable.GCPolicy
+	if maxAge != "" {
+		d, err := time.ParseDuration(maxAge)
+		if err != nil {
+			return nil, err
+		}
+		policies = append(policies, bigtable.MaxAgePolicy(d))
+	}
+	if maxVersions != 0 {
+		policies = append(policies, bigtable.MaxVersionsPolicy(maxVersions))
+	}
+
+	switch {
+	case len(policies) == 0:
+		if mode != "" {
+			return nil, fmt.Errorf("mode requires max_age and max_versions")
+		}
+		return bigtable.NoGcPolicy(), nil
+	case len(policies) == 1:
+		if mode != "" {
+			return nil, fmt.Errorf("mode requires max_age and max_versions")
+		}
+		return policies[0], nil
+	case mode == GCPolicyModeUnion:
+		return bigtable.UnionPolicy(policies...), nil
+	case mode == GCPolicyModeIntersection:
+		return bigtable.IntersectionPolicy(policies...), nil
+	default:
+		return nil, fmt.Errorf("if multiple policies are set, mode can't be empty")
+	}
+}
+
+// flattenBigtableTableGCRule flattens a GcRule of the Bigtable Admin API into
+// a gc_policy block, using gc_rules for policies that the block's other
+// fields can't express.
+func flattenBigtableTableGCRule(rule map[string]interface{}) ([]interface{}, error) {
+	if len(rule) == 0 {
+		return nil, nil
+	}
+	policy := map[string]interface{}{
+		"mode":         "",
+		"max_age":      "",
+		"max_versions": 0,
+		"gc_rules":     "",
+	}
+
+	if age, versions, mode, ok := flattenBigtableTableSimpleGCRule(rule); ok {
+		policy["mode"] = mode
+		policy["max_age"] = age
+		policy["max_versions"] = versions
+		return []interface{}{policy}, nil
+	}
+
+	j, err := flattenBigtableTableGCRuleJSON(rule)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := j["mode"]; !ok {
+		j = map[string]interface{}{"rules": []interface{}{j}}
+	}
+	b, err := json.Marshal(j)
+	if err != nil {
+		return nil, err
+	}
+	policy["gc_rules"] = string(b)
+	return []interface{}{policy}, nil
+}
+
+// flattenBigtableTableSimpleGCRule flattens a max age rule, a max versions
+// rule, or a union or intersection of one of each.
+func flattenBigtableTableSimpleGCRule(rule map[string]interface{}) (age string, versions int, mode string, ok bool) {
+	if v, ok := rule["maxAge"].(string); ok {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return "", 0, "", false
+		}
+		return bigtableTableDurationString(d), 0, "", true
+	}
+	if v, ok := rule["maxNumVersions"].(float64); ok {
+		return "", int(v), "", true
+	}
+
+	var rules []interface{}
+	if v, ok := rule["union"].(map[string]interface{}); ok {
+		mode = GCPolicyModeUnion
+		rules, _ = v["rules"].([]interface{})
+	} else if v, ok := rule["intersection"].(map[string]interface{}); ok {
+		mode = GCPolicyModeIntersection
+		rules, _ = v["rules"].([]interface{})
+	}
+	if len(rules) != 2 {
+		return "", 0, "", false
+	}
+	first, _ := rules[0].(map[string]interface{})
+	second, _ := rules[1].(map[string]interface{})
+	age, _, ageMode, aok := flattenBigtableTableSimpleGCRule(first)
+	_, versions, versionsMode, vok := flattenBigtableTableSimpleGCRule(second)
+	if !aok || !vok || ageMode != "" || versionsMode != "" || age == "" || versions == 0 {
+		return "", 0, "", false
+	}
+	return age, versions, mode, true
+}
+
+// flattenBigtableTableGCRuleJSON converts a GcRule into the JSON format of
+// google_bigtable_gc_policy's gc_rules.
+func flattenBigtableTableGCRuleJSON(rule map[string]interface{}) (map[string]interface{}, error) {
+	if v, ok := rule["maxAge"].(string); ok {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{"max_age": bigtableTableDurationString(d)}, nil
+	}
+	if v, ok := rule["maxNumVersions"].(float64); ok {
+		return map[string]interface{}{"max_version": v}, nil
+	}
+
+	for _, mode := range []string{GCPolicyModeUnion, GCPolicyModeIntersection} {
+		v, ok := rule[strings.ToLower(mode)].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		var rules []interface{}
+		raw, _ := v["rules"].([]interface{})
+		for _, r := range raw {
+			child, _ := r.(map[string]interface{})
+			j, err := flattenBigtableTableGCRuleJSON(child)
+			if err != nil {
+				return nil, err
+			}
+			rules = append(rules, j)
+		}
+		return map[string]interface{}{"mode": strings.ToLower(mode), "rules": rules}, nil
+	}
+	return nil, fmt.Errorf("unknown GC rule %v", rule)
+}
+
+// bigtableTableGCPoliciesEquivalent returns whether two gc_policy blocks
+// expand to the same policy.
+func bigtableTableGCPoliciesEquivalent(a, b interface{}) bool {
+	pa, err := expandBigtableTableGCPolicy(a)
+	if err != nil {
+		return false
+	}
+	pb, err := expandBigtableTableGCPolicy(b)
+	if err != nil {
+		return false
+	}
+	return pa.String() == pb.String()
+}
+
+// bigtableTableDurationString formats a duration without trailing zero units,
+// such as 24h rather than 24h0m0s.
+func bigtableTableDurationString(d time.Duration) string {
+	s := d.String()
+	if strings.HasSuffix(s, "m0s") {
+		s = strings.TrimSuffix(s, "0s")
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	return s
+}
+
+func bigtableTableDurationsEqual(a, b string) bool {
+	if a == b {
+		return true
+	}
+	da, err := time.ParseDuration(a)
+	if err != nil {
+		return false
+	}
+	db, err := time.ParseDuration(b)
+	if err != nil {
+		return false
+	}
+	return da == db
+}
+
+// patchBigtableTable updates the fields of a table the admin client doesn't
+// support.
+func patchBigtableTable(d *schema.ResourceData, config *Config, userAgent, project string, timeout time.Duration) error {
+	obj := map[string]interface{}{
+		"deletionProtection": d.Get("deletion_protection") == "PROTECTED",
+	}
+	if v := d.Get("change_stream_retention").(string); v != "" {
+		duration, err := time.ParseDuration(v)
+		if err != nil {
+			return err
+		}
+		obj["changeStreamConfig"] = map[string]interface{}{
+			"retentionPeriod": fmt.Sprintf("%ds", int64(duration.Seconds())),
+		}
+	}
+
+	instanceName := GetResourceNameFromSelfLink(d.Get("instance_name").(string))
+	url := fmt.Sprintf("%sprojects/%s/instances/%s/tables/%s?updateMask=deletionProtection,changeStreamConfig", config.BigtableBasePath, project, instanceName, d.Get("name"))
+	res, err := sendRequestWithTimeout(config, "PATCH", project, url, userAgent, obj, timeout)
+	if err != nil {
+		return fmt.Errorf("Error updating table %q: %s", d.Id(), err)
+	}
+	return bigtableAdminOperationWaitTime(config, res, project, "Updating Table", userAgent, timeout)
 }
 
 //TODO(rileykarson): Fix the stored import format after rebasing 3.0.0
@@ -3,9 +3,11 @@ package google
 import (
 	"context"
 	"fmt"
+	"reflect"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
 
@@ -146,6 +148,194 @@ func TestAccBigtableTable_familyUpdate(t *testing.T) {
 	})
 }
 
+func TestAccBigtableTable_gcPolicy(t *testing.T) {
+	// bigtable instance does not use the shared HTTP client, this test creates an instance
+	skipIfVcr(t)
+	t.Parallel()
+
+	instanceName := fmt.Sprintf("tf-test-%s", randString(t, 10))
+	tableName := fmt.Sprintf("tf-test-%s", randString(t, 10))
+	family := fmt.Sprintf("tf-test-%s", randString(t, 10))
+
+	vcrTest(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckBigtableTableDestroyProducer(t),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccBigtableTable_gcPolicy(instanceName, tableName, family, "PROTECTED", "24h", 2),
+			},
+			{
+				ResourceName:      "google_bigtable_table.table",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				Config: testAccBigtableTable_gcPolicy(instanceName, tableName, family, "UNPROTECTED", "72h", 3),
+			},
+			{
+				ResourceName:      "google_bigtable_table.table",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestBigtableTableGCPolicy(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		Rule   map[string]interface{}
+		Policy []interface{}
+		String string
+	}{
+		"none": {
+			Rule:   map[string]interface{}{},
+			Policy: nil,
+			String: "",
+		},
+		"max age": {
+			Rule: map[string]interface{}{"maxAge": "86400s"},
+			Policy: []interface{}{map[string]interface{}{
+				"mode": "", "max_age": "24h", "max_versions": 0, "gc_rules": "",
+			}},
+			String: "age() > 1d",
+		},
+		"max versions": {
+			Rule: map[string]interface{}{"maxNumVersions": float64(3)},
+			Policy: []interface{}{map[string]interface{}{
+				"mode": "", "max_age": "", "max_versions": 3, "gc_rules": "",
+			}},
+			String: "versions() > 3",
+		},
+		"union": {
+			Rule: map[string]interface{}{"union": map[string]interface{}{"rules": []interface{}{
+				map[string]interface{}{"maxAge": "5400s"},
+				map[string]interface{}{"maxNumVersions": float64(2)},
+			}}},
+			Policy: []interface{}{map[string]interface{}{
+				"mode": "UNION", "max_age": "1h30m", "max_versions": 2, "gc_rules": "",
+			}},
+			String: "(age() > 90m || versions() > 2)",
+		},
+		"nested": {
+			Rule: map[string]interface{}{"intersection": map[string]interface{}{"rules": []interface{}{
+				map[string]interface{}{"maxNumVersions": float64(2)},
+				map[string]interface{}{"union": map[string]interface{}{"rules": []interface{}{
+					map[string]interface{}{"maxAge": "604800s"},
+					map[string]interface{}{"maxNumVersions": float64(10)},
+				}}},
+			}}},
+			Policy: []interface{}{map[string]interface{}{
+				"mode": "", "max_age": "", "max_versions": 0,
+				"gc_rules": `{"mode":"intersection","rules":[{"max_version":2},{"mode":"union","rules":[{"max_age":"168h"},{"max_version":10}]}]}`,
+			}},
+			String: "(versions() > 2 && (age() > 7d || versions() > 10))",
+		},
+	}
+
+	for tn, tc := range cases {
+		policy, err := flattenBigtableTableGCRule(tc.Rule)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %s", tn, err)
+		}
+		if !reflect.DeepEqual(policy, tc.Policy) {
+			t.Errorf("%s: expected %#v, got %#v", tn, tc.Policy, policy)
+		}
+		gcPolicy, err := expandBigtableTableGCPolicy(policy)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %s", tn, err)
+		}
+		if gcPolicy.String() != tc.String {
+			t.Errorf("%s: expected %q, got %q", tn, tc.String, gcPolicy.String())
+		}
+	}
+}
+
+func TestBigtableTableGCPolicy_invalid(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]map[string]interface{}{
+		"mode without rules": {"mode": "UNION", "max_age": "", "max_versions": 0, "gc_rules": ""},
+		"mode with one rule": {"mode": "UNION", "max_age": "1h", "max_versions": 0, "gc_rules": ""},
+		"rules without mode": {"mode": "", "max_age": "1h", "max_versions": 2, "gc_rules": ""},
+		"gc_rules conflict":  {"mode": "", "max_age": "1h", "max_versions": 0, "gc_rules": `{"rules":[{"max_version":2}]}`},
+	}
+
+	for tn, tc := range cases {
+		if _, err := expandBigtableTableGCPolicy([]interface{}{tc}); err == nil {
+			t.Errorf("%s: expected an error", tn)
+		}
+	}
+}
+
+func TestBigtableTableGCPoliciesEquivalent(t *testing.T) {
+	t.Parallel()
+
+	configured := []interface{}{map[string]interface{}{
+		"mode": "", "max_age": "", "max_versions": 0, "gc_rules": `{"rules":[{"max_age":"1440m"}]}`,
+	}}
+	flattened := []interface{}{map[string]interface{}{
+		"mode": "", "max_age": "24h", "max_versions": 0, "gc_rules": "",
+	}}
+	if !bigtableTableGCPoliciesEquivalent(configured, flattened) {
+		t.Errorf("expected %v to be equivalent to %v", configured, flattened)
+	}
+	if bigtableTableGCPoliciesEquivalent(configured, nil) {
+		t.Errorf("expected %v not to be equivalent to no policy", configured)
+	}
+}
+
+func TestBigtableTableNeedsFullView(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		raw      map[string]interface{}
+		expected bool
+	}{
+		"imported": {
+			raw:      map[string]interface{}{"name": "t", "instance_name": "i"},
+			expected: true,
+		},
+		"unprotected": {
+			raw: map[string]interface{}{
+				"name":                "t",
+				"instance_name":       "i",
+				"deletion_protection": "UNPROTECTED",
+				"column_family":       []interface{}{map[string]interface{}{"family": "f"}},
+			},
+		},
+		"protected": {
+			raw:      map[string]interface{}{"name": "t", "instance_name": "i", "deletion_protection": "PROTECTED"},
+			expected: true,
+		},
+		"change stream": {
+			raw:      map[string]interface{}{"name": "t", "instance_name": "i", "deletion_protection": "UNPROTECTED", "change_stream_retention": "24h"},
+			expected: true,
+		},
+		"gc policy": {
+			raw: map[string]interface{}{
+				"name":                "t",
+				"instance_name":       "i",
+				"deletion_protection": "UNPROTECTED",
+				"column_family": []interface{}{map[string]interface{}{
+					"family":    "f",
+					"gc_policy": []interface{}{map[string]interface{}{"max_versions": 1}},
+				}},
+			},
+			expected: true,
+		},
+	}
+
+	for tn, tc := range cases {
+		d := schema.TestResourceDataRaw(t, resourceBigtableTable().Schema, tc.raw)
+		if got := bigtableTableNeedsFullView(d); got != tc.expected {
+			t.Errorf("%s: expected %t, got %t", tn, tc.expected, got)
+		}
+	}
+}
+
 func testAccCheckBigtableTableDestroyProducer(t *testing.T) func(s *terraform.State) error {
 	return func(s *terraform.State) error {
 		var ctx = context.Background()
@@ -300,3 +490,60 @@ resource "google_bigtable_table" "table" {
 }
 `, instanceName, instanceName, tableName, family, family, family)
 }
+
+func testAccBigtableTable_gcPolicy(instanceName, tableName, family, deletionProtection, maxAge string, maxVersions int) string {
+	return fmt.Sprintf(`
+resource "google_bigtable_instance" "instance" {
+  name = "%s"
+
+  cluster {
+    cluster_id = "%s"
+    zone       = "us-central1-b"
+  }
+
+  instance_type = "DEVELOPMENT"
+  deletion_protection = false
+}
+
+resource "google_bigtable_table" "table" {
+  name                    = "%s"
+  instance_name           = google_bigtable_instance.instance.name
+  deletion_protection     = "%s"
+  change_stream_retention = "%s"
+
+  column_family {
+    family = "%s-age"
+
+    gc_policy {
+      max_age = "%s"
+    }
+  }
+
+  column_family {
+    family = "%s-union"
+
+    gc_policy {
+      mode         = "UNION"
+      max_age      = "%s"
+      max_versions = %d
+    }
+  }
+
+  column_family {
+    family = "%s-nested"
+
+    gc_policy {
+      gc_rules = <<EOF
+{
+  "mode": "intersection",
+  "rules": [
+    { "max_version": %d },
+    { "mode": "union", "rules": [{ "max_age": "%s" }, { "max_version": 10 }] }
+  ]
+}
+EOF
+    }
+  }
+}
+`, instanceName, instanceName, tableName, deletionProtection, maxAge, family, maxAge, family, maxAge, maxVersions, family, maxVersions, maxAge)
+}
@@ -79,7 +79,7 @@ func resourceCloudBuildTrigger() *schema.Resource {
 		},
 
 		SchemaVersion: 1,
-		CustomizeDiff: stepTimeoutCustomizeDiff,
+		CustomizeDiff: resourceCloudBuildTriggerCustomizeDiff,
 
 		Schema: map[string]*schema.Schema{
 			"approval_config": {
@@ -0,0 +1,271 @@
+package google
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func resourceCloudBuildTriggerRun() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceCloudBuildTriggerRunCreate,
+		Read:   resourceCloudBuildTriggerRunRead,
+		Delete: resourceCloudBuildTriggerRunDelete,
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(60 * time.Minute),
+		},
+
+		Schema: map[string]*schema.Schema{
+			"trigger": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				DiffSuppressFunc: compareSelfLinkOrResourceName,
+				Description:      `The trigger to run, as its id or in the format projects/{{project}}/triggers/{{trigger_id}}.`,
+			},
+			"branch_name": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				ExactlyOneOf: []string{"branch_name", "tag_name", "commit_sha"},
+				Description:  `The branch to build. The trigger's branch regex doesn't need to match it.`,
+			},
+			"tag_name": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				ExactlyOneOf: []string{"branch_name", "tag_name", "commit_sha"},
+				Description:  `The tag to build. The trigger's tag regex doesn't need to match it.`,
+			},
+			"commit_sha": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				ExactlyOneOf: []string{"branch_name", "tag_name", "commit_sha"},
+				Description:  `The commit to build.`,
+			},
+			"substitutions": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				ForceNew:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: `Substitutions for this build, overriding the ones of the trigger.`,
+			},
+			"triggers": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				ForceNew:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: `Arbitrary map of values that, when changed, will run the trigger again.`,
+			},
+			"build_id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: `The id of the build.`,
+			},
+			"status": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: `The status of the build.`,
+			},
+			"log_url": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: `The URL of the logs of the build in the Google Cloud console.`,
+			},
+			"images": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: `The container images pushed by the build.`,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: `The name of the image, as listed in the images of the build.`,
+						},
+						"digest": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: `The digest of the pushed image, such as sha256:abc...`,
+						},
+					},
+				},
+			},
+			"project": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+		},
+		UseJSONNumber: true,
+	}
+}
+
+func resourceCloudBuildTriggerRunCreate(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*Config)
+	userAgent, err := generateUserAgentString(d, config.userAgent)
+	if err != nil {
+		return err
+	}
+
+	project, err := getProject(d, config)
+	if err != nil {
+		return fmt.Errorf("Error fetching project for Trigger run: %s", err)
+	}
+	billingProject := project
+
+	// err == nil indicates that the billing_project value was found
+	if bp, err := getBillingProject(d, config); err == nil {
+		billingProject = bp
+	}
+
+	trigger := GetResourceNameFromSelfLink(d.Get("trigger").(string))
+	obj := map[string]interface{}{
+		"projectId": project,
+	}
+	if v, ok := d.GetOk("branch_name"); ok {
+		obj["branchName"] = v
+	}
+	if v, ok := d.GetOk("tag_name"); ok {
+		obj["tagName"] = v
+	}
+	if v, ok := d.GetOk("commit_sha"); ok {
+		obj["commitSha"] = v
+	}
+	if v, ok := d.GetOk("substitutions"); ok {
+		obj["substitutions"] = v
+	}
+
+	url := fmt.Sprintf("%sprojects/%s/triggers/%s:run", config.CloudBuildBasePath, project, trigger)
+	log.Printf("[DEBUG] Running Trigger %q: %#v", trigger, obj)
+	op, err := sendRequestWithTimeout(config, "POST", billingProject, url, userAgent, obj, d.Timeout(schema.TimeoutCreate))
+	if err != nil {
+		return fmt.Errorf("Error running Trigger %q: %s", trigger, err)
+	}
+
+	buildId := cloudBuildOperationBuildId(op)
+	if buildId == "" {
+		return fmt.Errorf("Error running Trigger %q: the operation %v doesn't reference a build", trigger, op["name"])
+	}
+	// The id is set before waiting so that a failed build is kept in state,
+	// tainted, and run again by the next apply.
+	d.SetId(fmt.Sprintf("projects/%s/builds/%s", project, buildId))
+
+	waitErr := cloudBuildOperationWaitTime(config, op, project, "Waiting for build to finish", userAgent, d.Timeout(schema.TimeoutCreate))
+
+	build, err := sendRequest(config, "GET", billingProject, config.CloudBuildBasePath+d.Id(), userAgent, nil)
+	if err != nil {
+		if waitErr != nil {
+			return fmt.Errorf("Error running Trigger %q: %s", trigger, waitErr)
+		}
+		return fmt.Errorf("Error reading Build %q: %s", d.Id(), err)
+	}
+	if err := cloudBuildTriggerRunBuildError(build); err != nil {
+		return fmt.Errorf("Error running Trigger %q: %s", trigger, err)
+	}
+
+	log.Printf("[DEBUG] Finished running Trigger %q: build %q", trigger, buildId)
+
+	return resourceCloudBuildTriggerRunRead(d, meta)
+}
+
+func resourceCloudBuildTriggerRunRead(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*Config)
+	userAgent, err := generateUserAgentString(d, config.userAgent)
+	if err != nil {
+		return err
+	}
+
+	project, err := getProject(d, config)
+	if err != nil {
+		return fmt.Errorf("Error fetching project for Trigger run: %s", err)
+	}
+	billingProject := project
+
+	// err == nil indicates that the billing_project value was found
+	if bp, err := getBillingProject(d, config); err == nil {
+		billingProject = bp
+	}
+
+	res, err := sendRequest(config, "GET", billingProject, config.CloudBuildBasePath+d.Id(), userAgent, nil)
+	if err != nil {
+		return handleNotFoundError(err, d, fmt.Sprintf("CloudBuildBuild %q", d.Id()))
+	}
+
+	if err := d.Set("project", project); err != nil {
+		return fmt.Errorf("Error reading Trigger run: %s", err)
+	}
+	if err := d.Set("build_id", res["id"]); err != nil {
+		return fmt.Errorf("Error reading Trigger run: %s", err)
+	}
+	if err := d.Set("status", res["status"]); err != nil {
+		return fmt.Errorf("Error reading Trigger run: %s", err)
+	}
+	if err := d.Set("log_url", res["logUrl"]); err != nil {
+		return fmt.Errorf("Error reading Trigger run: %s", err)
+	}
+	if err := d.Set("images", flattenCloudBuildTriggerRunImages(res["results"])); err != nil {
+		return fmt.Errorf("Error reading Trigger run: %s", err)
+	}
+
+	return nil
+}
+
+func resourceCloudBuildTriggerRunDelete(d *schema.ResourceData, meta interface{}) error {
+	// A build can't be deleted, it's only removed from state.
+	log.Printf("[DEBUG] Removing Trigger run %q from state", d.Id())
+	d.SetId("")
+	return nil
+}
+
+// cloudBuildOperationBuildId returns the id of the build an operation
+// returned by the Cloud Build API is about.
+func cloudBuildOperationBuildId(op map[string]interface{}) string {
+	metadata, _ := op["metadata"].(map[string]interface{})
+	build, _ := metadata["build"].(map[string]interface{})
+	id, _ := build["id"].(string)
+	return id
+}
+
+// cloudBuildTriggerRunBuildError returns an error pointing to the logs of
+// build unless it succeeded.
+func cloudBuildTriggerRunBuildError(build map[string]interface{}) error {
+	status, _ := build["status"].(string)
+	if status == "SUCCESS" {
+		return nil
+	}
+	msg := fmt.Sprintf("build %v finished with status %s", build["id"], status)
+	switch status {
+	case "STATUS_UNKNOWN", "PENDING", "QUEUED", "WORKING":
+		msg = fmt.Sprintf("build %v didn't finish, its status is %s", build["id"], status)
+	}
+	if detail, _ := build["statusDetail"].(string); detail != "" {
+		msg += ": " + detail
+	}
+	if logUrl, _ := build["logUrl"].(string); logUrl != "" {
+		msg += fmt.Sprintf(". See the logs at %s", logUrl)
+	}
+	return fmt.Errorf("%s", msg)
+}
+
+func flattenCloudBuildTriggerRunImages(v interface{}) []interface{} {
+	results, _ := v.(map[string]interface{})
+	images, _ := results["images"].([]interface{})
+	transformed := make([]interface{}, 0, len(images))
+	for _, raw := range images {
+		image, ok := raw.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		transformed = append(transformed, map[string]interface{}{
+			"name":   image["name"],
+			"digest": image["digest"],
+		})
+	}
+	return transformed
+}
@@ -0,0 +1,159 @@
+package google
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestCloudBuildOperationBuildId(t *testing.T) {
+	t.Parallel()
+
+	op := map[string]interface{}{
+		"name": "operations/build/my-project/YWJj",
+		"metadata": map[string]interface{}{
+			"@type": "type.googleapis.com/google.devtools.cloudbuild.v1.BuildOperationMetadata",
+			"build": map[string]interface{}{"id": "abc"},
+		},
+	}
+	if id := cloudBuildOperationBuildId(op); id != "abc" {
+		t.Errorf("expected build id %q, got %q", "abc", id)
+	}
+	if id := cloudBuildOperationBuildId(map[string]interface{}{}); id != "" {
+		t.Errorf("expected no build id, got %q", id)
+	}
+}
+
+func TestCloudBuildTriggerRunBuildError(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		Build    map[string]interface{}
+		Expected string
+	}{
+		"success": {
+			Build: map[string]interface{}{"id": "abc", "status": "SUCCESS"},
+		},
+		"failure": {
+			Build: map[string]interface{}{
+				"id":           "abc",
+				"status":       "FAILURE",
+				"statusDetail": `Build step failure: build step 0 "gcr.io/cloud-builders/go" failed: step exited with non-zero status: 1`,
+				"logUrl":       "https://console.cloud.google.com/cloud-build/builds/abc?project=123",
+			},
+			Expected: `build abc finished with status FAILURE: Build step failure: build step 0 "gcr.io/cloud-builders/go" failed: step exited with non-zero status: 1. See the logs at https://console.cloud.google.com/cloud-build/builds/abc?project=123`,
+		},
+		"still running": {
+			Build:    map[string]interface{}{"id": "abc", "status": "WORKING", "logUrl": "https://console.cloud.google.com/cloud-build/builds/abc?project=123"},
+			Expected: `build abc didn't finish, its status is WORKING. See the logs at https://console.cloud.google.com/cloud-build/builds/abc?project=123`,
+		},
+	}
+
+	for tn, tc := range cases {
+		err := cloudBuildTriggerRunBuildError(tc.Build)
+		if tc.Expected == "" {
+			if err != nil {
+				t.Errorf("%s: expected no error, got %s", tn, err)
+			}
+			continue
+		}
+		if err == nil || err.Error() != tc.Expected {
+			t.Errorf("%s: expected error %q, got %v", tn, tc.Expected, err)
+		}
+	}
+}
+
+func TestFlattenCloudBuildTriggerRunImages(t *testing.T) {
+	t.Parallel()
+
+	results := map[string]interface{}{
+		"images": []interface{}{
+			map[string]interface{}{
+				"name":   "gcr.io/my-project/app:latest",
+				"digest": "sha256:0123456789abcdef",
+				"pushTiming": map[string]interface{}{
+					"startTime": "2022-01-01T00:00:00Z",
+				},
+			},
+		},
+	}
+	expected := []interface{}{
+		map[string]interface{}{
+			"name":   "gcr.io/my-project/app:latest",
+			"digest": "sha256:0123456789abcdef",
+		},
+	}
+	if images := flattenCloudBuildTriggerRunImages(results); !reflect.DeepEqual(images, expected) {
+		t.Errorf("expected %v, got %v", expected, images)
+	}
+	if images := flattenCloudBuildTriggerRunImages(nil); len(images) != 0 {
+		t.Errorf("expected no images, got %v", images)
+	}
+}
+
+func TestAccCloudBuildTriggerRun_basic(t *testing.T) {
+	t.Parallel()
+
+	name := fmt.Sprintf("tf-test-%d", randInt(t))
+
+	vcrTest(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCloudBuildTriggerDestroyProducer(t),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCloudBuildTriggerRun_basic(name, "1"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("google_cloudbuild_trigger_run.run", "status", "SUCCESS"),
+					resource.TestCheckResourceAttrSet("google_cloudbuild_trigger_run.run", "build_id"),
+					resource.TestCheckResourceAttrSet("google_cloudbuild_trigger_run.run", "log_url"),
+				),
+			},
+			{
+				Config: testAccCloudBuildTriggerRun_basic(name, "2"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("google_cloudbuild_trigger_run.run", "status", "SUCCESS"),
+					resource.TestCheckResourceAttr("google_cloudbuild_trigger_run.run", "triggers.version", "2"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCloudBuildTriggerRun_basic(name, version string) string {
+	return fmt.Sprintf(`
+resource "google_cloudbuild_trigger" "build_trigger" {
+  name        = "%s"
+  description = "acceptance test build trigger"
+  trigger_template {
+    branch_name = "main"
+    repo_name   = "some-repo"
+  }
+  substitutions = {
+    _MESSAGE = "hello"
+  }
+  build {
+    timeout = "600s"
+    step {
+      name = "ubuntu"
+      args = ["echo", "$_MESSAGE"]
+    }
+  }
+}
+
+resource "google_cloudbuild_trigger_run" "run" {
+  trigger     = google_cloudbuild_trigger.build_trigger.id
+  branch_name = "main"
+
+  substitutions = {
+    _MESSAGE = "run %s"
+  }
+
+  triggers = {
+    version = "%s"
+  }
+}
+`, name, version, version)
+}
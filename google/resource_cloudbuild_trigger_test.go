@@ -2,6 +2,7 @@ package google
 
 import (
 	"fmt"
+	"reflect"
 	"regexp"
 	"testing"
 
@@ -160,6 +161,32 @@ func TestAccCloudBuildTrigger_customizeDiffTimeoutFormat(t *testing.T) {
 	})
 }
 
+func TestAccCloudBuildTrigger_customizeDiffSteps(t *testing.T) {
+	t.Parallel()
+
+	name := fmt.Sprintf("tf-test-%d", randInt(t))
+
+	vcrTest(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCloudBuildTriggerDestroyProducer(t),
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccCloudBuildTrigger_customizeDiffSteps(name, `["-"]`, "$_TAG"),
+				ExpectError: regexp.MustCompile(`build step 1: substitution \$_TAG isn't defined`),
+			},
+			{
+				Config:      testAccCloudBuildTrigger_customizeDiffSteps(name, `["push"]`, "latest"),
+				ExpectError: regexp.MustCompile(`build step 0: wait_for forms a cycle`),
+			},
+			{
+				Config:      testAccCloudBuildTrigger_customizeDiffSteps(name, `["test"]`, "latest"),
+				ExpectError: regexp.MustCompile(`build step 0: wait_for references unknown step id "test"`),
+			},
+		},
+	})
+}
+
 func TestAccCloudBuildTrigger_disable(t *testing.T) {
 	t.Parallel()
 	name := fmt.Sprintf("tf-test-%d", randInt(t))
@@ -237,6 +264,12 @@ resource "google_cloudbuild_trigger" "build_trigger" {
       args = ["build", "-t", "gcr.io/$PROJECT_ID/$REPO_NAME:$COMMIT_SHA", "-f", "Dockerfile", "."]
       timeout = "300s"
     }
+    secret {
+      kms_key_name = "projects/myProject/locations/global/keyRings/keyring-name/cryptoKeys/key-name"
+      secret_env = {
+        secretenv = "ZW5jcnlwdGVkLXBhc3N3b3JkCg=="
+      }
+    }
     artifacts {
       images = ["gcr.io/$PROJECT_ID/$REPO_NAME:$COMMIT_SHA"]
       objects {
@@ -255,7 +288,7 @@ resource "google_cloudbuild_trigger" "build_trigger" {
       worker_pool = "pool"
       logging = "LEGACY"
       env = ["ekey = evalue"]
-      secret_env = ["secretenv = svalue"]
+      secret_env = ["secretenv"]
       volumes {
         name = "v1"
         path = "v1"
@@ -317,7 +350,13 @@ resource "google_cloudbuild_trigger" "build_trigger" {
       id         = "12345"
       secret_env = ["fooo"]
       timeout    = "100s"
-      wait_for   = ["something"]
+      wait_for   = ["-"]
+    }
+    available_secrets {
+      secret_manager {
+        env          = "fooo"
+        version_name = "projects/myProject/secrets/mySecret/versions/latest"
+      }
     }
   }
 }
@@ -660,3 +699,160 @@ resource "google_cloudbuild_trigger" "build_trigger" {
 }
 `, name)
 }
+
+func testAccCloudBuildTrigger_customizeDiffSteps(name, waitFor, tag string) string {
+	return fmt.Sprintf(`
+resource "google_cloudbuild_trigger" "build_trigger" {
+  name        = "%s"
+  description = "acceptance test build trigger"
+  trigger_template {
+    branch_name = "main"
+    repo_name   = "some-repo"
+  }
+  build {
+    step {
+      id       = "build"
+      name     = "gcr.io/cloud-builders/go"
+      args     = ["build", "my_package"]
+      wait_for = %s
+    }
+    step {
+      id   = "push"
+      name = "gcr.io/cloud-builders/docker"
+      args = ["push", "gcr.io/$PROJECT_ID/$REPO_NAME:%s"]
+    }
+  }
+}
+`, name, waitFor, tag)
+}
+
+func TestCloudBuildStepDependencyErrors(t *testing.T) {
+	t.Parallel()
+
+	step := func(id string, waitFor ...interface{}) interface{} {
+		return map[string]interface{}{"id": id, "wait_for": waitFor}
+	}
+	cases := map[string]struct {
+		Steps    []interface{}
+		Expected []string
+	}{
+		"sequential": {
+			Steps: []interface{}{step("a"), step(""), step("c")},
+		},
+		"parallel": {
+			Steps: []interface{}{step("a", "-"), step("b", "-"), step("c", "a", "b")},
+		},
+		"unknown id": {
+			Steps:    []interface{}{step("a"), step("b", "z")},
+			Expected: []string{`build step 1: wait_for references unknown step id "z"`},
+		},
+		"duplicate id": {
+			Steps:    []interface{}{step("a"), step("a")},
+			Expected: []string{`build step 1: id "a" is already used by build step 0`},
+		},
+		"cycle": {
+			Steps:    []interface{}{step("a", "c"), step("b", "a"), step("c", "b")},
+			Expected: []string{`build step 0: wait_for forms a cycle between steps 0 ("a") -> 2 ("c") -> 1 ("b") -> 0 ("a")`},
+		},
+		"cycle through implicit dependency": {
+			Steps:    []interface{}{step("a", "c"), step(""), step("c")},
+			Expected: []string{`build step 0: wait_for forms a cycle between steps 0 ("a") -> 2 ("c") -> 0 ("a")`},
+		},
+	}
+
+	for tn, tc := range cases {
+		errs, unknownIdErrs := cloudBuildStepDependencyErrors(tc.Steps)
+		var got []string
+		for _, err := range append(errs, unknownIdErrs...) {
+			got = append(got, err.Error())
+		}
+		if !reflect.DeepEqual(got, tc.Expected) {
+			t.Errorf("%s: expected %q, got %q", tn, tc.Expected, got)
+		}
+	}
+}
+
+func TestCloudBuildSubstitutionErrors(t *testing.T) {
+	t.Parallel()
+
+	build := map[string]interface{}{
+		"step": []interface{}{
+			map[string]interface{}{
+				"name":       "gcr.io/cloud-builders/docker",
+				"dir":        "$_DIR",
+				"entrypoint": "",
+				"args":       []interface{}{"build", "-t", "${_IMAGE}:${_TAG:0:7}", "$$_ESCAPED", "$_HEAD_BRANCH", "$PROJECT_ID"},
+				"env":        []interface{}{"FOO=$_FOO"},
+			},
+		},
+		"images": []interface{}{"gcr.io/$PROJECT_ID/$_IMAGE"},
+	}
+	defined := map[string]bool{"_IMAGE": true, "_DIR": true}
+
+	var errs []string
+	for _, err := range cloudBuildSubstitutionErrors(build, defined) {
+		errs = append(errs, err.Error())
+	}
+	expected := []string{
+		"build step 0: substitution $_TAG isn't defined in substitutions",
+		"build step 0: substitution $_FOO isn't defined in substitutions",
+	}
+	if !reflect.DeepEqual(errs, expected) {
+		t.Errorf("expected %q, got %q", expected, errs)
+	}
+}
+
+func TestCloudBuildSecretEnvErrors(t *testing.T) {
+	t.Parallel()
+
+	build := map[string]interface{}{
+		"step": []interface{}{
+			map[string]interface{}{"secret_env": []interface{}{"MANAGED", "ENCRYPTED"}},
+			map[string]interface{}{"secret_env": []interface{}{"MISSING"}},
+		},
+		"available_secrets": []interface{}{
+			map[string]interface{}{
+				"secret_manager": []interface{}{
+					map[string]interface{}{"env": "MANAGED"},
+				},
+			},
+		},
+		"secret": []interface{}{
+			map[string]interface{}{
+				"secret_env": map[string]interface{}{"ENCRYPTED": "Y2lwaGVydGV4dA=="},
+			},
+		},
+		"options": []interface{}{
+			map[string]interface{}{"secret_env": []interface{}{"GLOBAL"}},
+		},
+	}
+
+	var errs []string
+	for _, err := range cloudBuildSecretEnvErrors(build) {
+		errs = append(errs, err.Error())
+	}
+	expected := []string{
+		`build step 1: secret_env "MISSING" isn't provided by available_secrets or secret`,
+		`build options: secret_env "GLOBAL" isn't provided by available_secrets or secret`,
+	}
+	if !reflect.DeepEqual(errs, expected) {
+		t.Errorf("expected %q, got %q", expected, errs)
+	}
+}
+
+func TestCloudBuildDurationRegex(t *testing.T) {
+	t.Parallel()
+
+	for v, valid := range map[string]bool{
+		"600s":   true,
+		"3.5s":   true,
+		"1200":   false,
+		"10m":    false,
+		"-5s":    false,
+		"1.5.0s": false,
+	} {
+		if got := cloudBuildDurationRegex.MatchString(v); got != valid {
+			t.Errorf("%q: expected valid to be %t, got %t", v, valid, got)
+		}
+	}
+}
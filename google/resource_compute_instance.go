@@ -7,6 +7,7 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	neturl "net/url"
 	"strconv"
 	"strings"
 	"time"
@@ -748,6 +749,38 @@ func resourceComputeInstance() *schema.Resource {
 				Computed:    true,
 				Description: `Current status of the instance.`,
 			},
+			"wait_for_guest_attribute": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				MaxItems:    1,
+				Description: `Wait on create until the instance reports readiness through a guest attribute. Guest attributes must be enabled through the enable-guest-attributes metadata key.`,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"namespace": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: `The namespace of the guest attribute.`,
+						},
+						"key": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: `The key of the guest attribute.`,
+						},
+						"expected_value": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: `The value the guest attribute must have. If unset, any value is accepted.`,
+						},
+						"timeout": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							Default:      "10m",
+							ValidateFunc: validateNonNegativeDuration(),
+							Description:  `How long to wait for the guest attribute, as a duration such as "10m". Counted from the end of the insert operation.`,
+						},
+					},
+				},
+			},
 			"tags": {
 				Type:        schema.TypeSet,
 				Optional:    true,
@@ -1063,6 +1096,87 @@ func waitUntilInstanceHasDesiredStatus(config *Config, d *schema.ResourceData) e
 	return nil
 }
 
+// Number of serial port lines included in the error when the guest attribute
+// never reaches the expected value.
+const instanceGuestAttributeSerialPortTailLines = 30
+
+// waitForInstanceGuestAttribute blocks until the guest attribute configured in
+// wait_for_guest_attribute is present with the expected value. On timeout, the
+// end of the serial port output is included in the error to help diagnose why
+// the bootstrap didn't complete.
+func waitForInstanceGuestAttribute(config *Config, d *schema.ResourceData, project, zone, name, userAgent string) error {
+	v, ok := d.GetOk("wait_for_guest_attribute")
+	if !ok || len(v.([]interface{})) == 0 || v.([]interface{})[0] == nil {
+		return nil
+	}
+	wait := v.([]interface{})[0].(map[string]interface{})
+	namespace := wait["namespace"].(string)
+	key := wait["key"].(string)
+	expected := wait["expected_value"].(string)
+
+	timeout, err := time.ParseDuration(wait["timeout"].(string))
+	if err != nil {
+		return err
+	}
+
+	activity := fmt.Sprintf("Waiting for guest attribute %s/%s on instance %s", namespace, key, name)
+	err = PollingWaitTime(instanceGuestAttributePollRead(config, project, zone, name, namespace+"/"+key, userAgent), PollCheckInstanceGuestAttribute(expected), activity, timeout, 1)
+	if err == nil {
+		return nil
+	}
+
+	output, serialErr := config.NewComputeClient(userAgent).Instances.GetSerialPortOutput(project, zone, name).Port(1).Do()
+	if serialErr != nil {
+		log.Printf("[WARN] Unable to read serial port output of instance %s: %s", name, serialErr)
+		return fmt.Errorf("Error waiting for guest attribute %s/%s on instance %s: %s", namespace, key, name, err)
+	}
+
+	return fmt.Errorf("Error waiting for guest attribute %s/%s on instance %s: %s\n\nLast lines of serial port output:\n%s",
+		namespace, key, name, err, serialPortOutputTail(output.Contents, instanceGuestAttributeSerialPortTailLines))
+}
+
+func instanceGuestAttributePollRead(config *Config, project, zone, name, queryPath, userAgent string) PollReadFunc {
+	return func() (map[string]interface{}, error) {
+		url := fmt.Sprintf("%sprojects/%s/zones/%s/instances/%s/getGuestAttributes?queryPath=%s", config.ComputeBasePath, project, zone, name, neturl.QueryEscape(queryPath))
+		return sendRequest(config, "GET", project, url, userAgent, nil)
+	}
+}
+
+// PollCheckInstanceGuestAttribute waits for the queried guest attribute to
+// exist and, if expected is set, to have that value. Guest attributes that
+// haven't been written yet are reported as 404s.
+func PollCheckInstanceGuestAttribute(expected string) PollCheckResponseFunc {
+	return func(resp map[string]interface{}, respErr error) PollResult {
+		if respErr != nil {
+			if isGoogleApiErrorWithCode(respErr, 404) {
+				return PendingStatusPollResult("not found")
+			}
+			return ErrorPollResult(respErr)
+		}
+
+		queryValue, _ := resp["queryValue"].(map[string]interface{})
+		items, _ := queryValue["items"].([]interface{})
+		if len(items) == 0 {
+			return PendingStatusPollResult("not found")
+		}
+		item, _ := items[0].(map[string]interface{})
+		value, _ := item["value"].(string)
+		if expected != "" && value != expected {
+			return PendingStatusPollResult(value)
+		}
+		return SuccessPollResult()
+	}
+}
+
+// serialPortOutputTail returns the last n lines of serial port output.
+func serialPortOutputTail(contents string, n int) string {
+	lines := strings.Split(strings.TrimRight(contents, "\n"), "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.Join(lines, "\n")
+}
+
 func resourceComputeInstanceCreate(d *schema.ResourceData, meta interface{}) error {
 	config := meta.(*Config)
 	userAgent, err := generateUserAgentString(d, config.userAgent)
@@ -1114,6 +1228,10 @@ func resourceComputeInstanceCreate(d *schema.ResourceData, meta interface{}) err
 		return fmt.Errorf("Error waiting for status: %s", err)
 	}
 
+	if err := waitForInstanceGuestAttribute(config, d, project, z, instance.Name, userAgent); err != nil {
+		return err
+	}
+
 	return resourceComputeInstanceRead(d, meta)
 }
 
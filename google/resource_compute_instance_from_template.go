@@ -175,6 +175,10 @@ func resourceComputeInstanceFromTemplateCreate(d *schema.ResourceData, meta inte
 		return waitErr
 	}
 
+	if err := waitForInstanceGuestAttribute(config, d, project, z, instance.Name, userAgent); err != nil {
+		return err
+	}
+
 	return resourceComputeInstanceRead(d, meta)
 }
 
@@ -14,6 +14,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 	"google.golang.org/api/compute/v1"
+	"google.golang.org/api/googleapi"
 )
 
 func init() {
@@ -2240,6 +2241,104 @@ func TestAccComputeInstance_spotVM_update(t *testing.T) {
 	})
 }
 
+func TestAccComputeInstance_waitForGuestAttribute(t *testing.T) {
+	t.Parallel()
+
+	var instance compute.Instance
+	var instanceName = fmt.Sprintf("tf-test-%s", randString(t, 10))
+
+	vcrTest(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckComputeInstanceDestroyProducer(t),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccComputeInstance_waitForGuestAttribute(instanceName),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckComputeInstanceExists(
+						t, "google_compute_instance.foobar", &instance),
+				),
+			},
+			computeInstanceImportStep("us-central1-a", instanceName, []string{"metadata.enable-guest-attributes", "wait_for_guest_attribute"}),
+		},
+	})
+}
+
+func TestComputeInstance_guestAttributePollCheck(t *testing.T) {
+	t.Parallel()
+
+	ready := map[string]interface{}{
+		"queryValue": map[string]interface{}{
+			"items": []interface{}{
+				map[string]interface{}{
+					"namespace": "bootstrap",
+					"key":       "status",
+					"value":     "ready",
+				},
+			},
+		},
+	}
+
+	cases := map[string]struct {
+		Expected  string
+		Resp      map[string]interface{}
+		RespErr   error
+		Pending   bool
+		Retryable bool
+	}{
+		"not written yet": {
+			RespErr:   &googleapi.Error{Code: 404},
+			Pending:   true,
+			Retryable: true,
+		},
+		"other error": {
+			RespErr: &googleapi.Error{Code: 400},
+			Pending: true,
+		},
+		"any value": {
+			Resp: ready,
+		},
+		"expected value": {
+			Expected: "ready",
+			Resp:     ready,
+		},
+		"unexpected value": {
+			Expected:  "done",
+			Resp:      ready,
+			Pending:   true,
+			Retryable: true,
+		},
+		"empty query value": {
+			Resp:      map[string]interface{}{},
+			Pending:   true,
+			Retryable: true,
+		},
+	}
+
+	for tn, tc := range cases {
+		result := PollCheckInstanceGuestAttribute(tc.Expected)(tc.Resp, tc.RespErr)
+		if (result != nil) != tc.Pending {
+			t.Errorf("%s: expected pending %t, got %v", tn, tc.Pending, result)
+			continue
+		}
+		if result != nil && result.Retryable != tc.Retryable {
+			t.Errorf("%s: expected retryable %t, got %t", tn, tc.Retryable, result.Retryable)
+		}
+	}
+}
+
+func TestComputeInstance_serialPortOutputTail(t *testing.T) {
+	t.Parallel()
+
+	contents := "one\ntwo\nthree\nfour\n"
+	if got, want := serialPortOutputTail(contents, 2), "three\nfour"; got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+	if got, want := serialPortOutputTail(contents, 10), "one\ntwo\nthree\nfour"; got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
+
 func TestComputeInstance_networkIPCustomizedDiff(t *testing.T) {
 	t.Parallel()
 
@@ -6170,3 +6269,44 @@ resource "google_compute_instance" "foobar" {
 }
 `, instance)
 }
+
+func testAccComputeInstance_waitForGuestAttribute(instance string) string {
+	return fmt.Sprintf(`
+data "google_compute_image" "my_image" {
+  family  = "debian-10"
+  project = "debian-cloud"
+}
+
+resource "google_compute_instance" "foobar" {
+  name         = "%s"
+  machine_type = "e2-medium"
+  zone         = "us-central1-a"
+
+  boot_disk {
+    initialize_params {
+      image = data.google_compute_image.my_image.self_link
+    }
+  }
+
+  network_interface {
+    network = "default"
+  }
+
+  metadata = {
+    enable-guest-attributes = "TRUE"
+  }
+
+  metadata_startup_script = <<EOT
+curl -X PUT --data "ready" -H "Metadata-Flavor: Google" \
+  http://metadata.google.internal/computeMetadata/v1/instance/guest-attributes/bootstrap/status
+EOT
+
+  wait_for_guest_attribute {
+    namespace      = "bootstrap"
+    key            = "status"
+    expected_value = "ready"
+    timeout        = "5m"
+  }
+}
+`, instance)
+}
@@ -0,0 +1,387 @@
+package google
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"log"
+	"net"
+	"sync"
+
+	"github.com/hashicorp/errwrap"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+
+	"google.golang.org/api/compute/v1"
+)
+
+// subnetworkRangeRegistry records the ranges allocated by this provider
+// instance, so allocations created in the same run don't collide before
+// their subnetworks exist.
+type subnetworkRangeRegistry struct {
+	mu sync.Mutex
+	// ranges by network relative link
+	ranges map[string][]*net.IPNet
+}
+
+func newSubnetworkRangeRegistry() *subnetworkRangeRegistry {
+	return &subnetworkRangeRegistry{ranges: make(map[string][]*net.IPNet)}
+}
+
+func (r *subnetworkRangeRegistry) get(network string) []*net.IPNet {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]*net.IPNet{}, r.ranges[network]...)
+}
+
+func (r *subnetworkRangeRegistry) add(network string, ipNet *net.IPNet) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.ranges[network] = append(r.ranges[network], ipNet)
+}
+
+func (r *subnetworkRangeRegistry) remove(network string, cidr string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	ranges := r.ranges[network][:0]
+	for _, n := range r.ranges[network] {
+		if n.String() != cidr {
+			ranges = append(ranges, n)
+		}
+	}
+	r.ranges[network] = ranges
+}
+
+func resourceComputeSubnetworkRangeAllocation() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceComputeSubnetworkRangeAllocationCreate,
+		Read:   resourceComputeSubnetworkRangeAllocationRead,
+		Delete: resourceComputeSubnetworkRangeAllocationDelete,
+
+		CustomizeDiff: subnetworkRangeAllocationPrefixLengthCustomizeDiff,
+
+		Schema: map[string]*schema.Schema{
+			"network": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				DiffSuppressFunc: compareSelfLinkOrResourceName,
+				Description:      `The name or self link of the network the range is allocated in.`,
+			},
+
+			"supernet": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validateIpCidrRange,
+				Description:  `The IPv4 range the range is allocated from.`,
+			},
+
+			"prefix_length": {
+				Type:         schema.TypeInt,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.IntBetween(8, 29),
+				Description:  `The prefix length of the allocated range.`,
+			},
+
+			"reserved_ranges": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				ForceNew:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString, ValidateFunc: validateIpCidrRange},
+				Description: `Additional ranges the allocated range must not overlap.`,
+			},
+
+			"region": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+				Description: `The region whose routes imported from peered networks are considered. If it is not provided, the provider region is used.`,
+			},
+
+			"project": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+
+			"ip_cidr_range": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: `The allocated range.`,
+			},
+		},
+		UseJSONNumber: true,
+	}
+}
+
+func subnetworkRangeAllocationPrefixLengthCustomizeDiff(_ context.Context, diff *schema.ResourceDiff, _ interface{}) error {
+	if !diff.NewValueKnown("supernet") || !diff.NewValueKnown("prefix_length") {
+		return nil
+	}
+	_, supernet, err := net.ParseCIDR(diff.Get("supernet").(string))
+	if err != nil {
+		return err
+	}
+	if supernet.IP.To4() == nil {
+		return fmt.Errorf("supernet %s is not an IPv4 range", supernet)
+	}
+	if ones, _ := supernet.Mask.Size(); diff.Get("prefix_length").(int) < ones {
+		return fmt.Errorf("prefix_length %d is shorter than the prefix length of supernet %s", diff.Get("prefix_length").(int), supernet)
+	}
+	return nil
+}
+
+func resourceComputeSubnetworkRangeAllocationCreate(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*Config)
+	userAgent, err := generateUserAgentString(d, config.userAgent)
+	if err != nil {
+		return err
+	}
+
+	network, err := ParseNetworkFieldValue(d.Get("network").(string), d, config)
+	if err != nil {
+		return err
+	}
+	region, err := getRegion(d, config)
+	if err != nil {
+		return err
+	}
+	_, supernet, err := net.ParseCIDR(d.Get("supernet").(string))
+	if err != nil {
+		return err
+	}
+
+	// Allocations in the same network must not pick a range concurrently.
+	lockName := fmt.Sprintf("%s/rangeAllocations", network.RelativeLink())
+	mutexKV.Lock(lockName)
+	defer mutexKV.Unlock(lockName)
+
+	subnetworkRanges, used, err := listSubnetworkRangesInUse(config, userAgent, network, region)
+	if err != nil {
+		return err
+	}
+	used = append(used, subnetworkRanges...)
+	for _, r := range convertStringArr(d.Get("reserved_ranges").([]interface{})) {
+		_, ipNet, err := net.ParseCIDR(r)
+		if err != nil {
+			return err
+		}
+		used = append(used, ipNet)
+	}
+	used = append(used, config.subnetworkRangeAllocations.get(network.RelativeLink())...)
+
+	allocated, err := allocateSubnetworkRange(supernet, d.Get("prefix_length").(int), used)
+	if err != nil {
+		return fmt.Errorf("Error allocating a range in network %s: %s", network.RelativeLink(), err)
+	}
+	log.Printf("[DEBUG] Allocated range %s in network %s", allocated, network.RelativeLink())
+	config.subnetworkRangeAllocations.add(network.RelativeLink(), allocated)
+
+	if err := d.Set("ip_cidr_range", allocated.String()); err != nil {
+		return fmt.Errorf("Error setting ip_cidr_range: %s", err)
+	}
+	if err := d.Set("region", region); err != nil {
+		return fmt.Errorf("Error setting region: %s", err)
+	}
+	if err := d.Set("project", network.Project); err != nil {
+		return fmt.Errorf("Error setting project: %s", err)
+	}
+	d.SetId(fmt.Sprintf("%s/%s", network.RelativeLink(), allocated.String()))
+
+	return resourceComputeSubnetworkRangeAllocationRead(d, meta)
+}
+
+// The allocation is only recorded in the state, so that it stays stable once
+// a subnetwork uses the range. Read checks the range is still free, or used by
+// a subnetwork, and removes the allocation otherwise so that it's allocated
+// again.
+func resourceComputeSubnetworkRangeAllocationRead(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*Config)
+	userAgent, err := generateUserAgentString(d, config.userAgent)
+	if err != nil {
+		return err
+	}
+
+	network, err := ParseNetworkFieldValue(d.Get("network").(string), d, config)
+	if err != nil {
+		return err
+	}
+	region, err := getRegion(d, config)
+	if err != nil {
+		return err
+	}
+	_, allocated, err := net.ParseCIDR(d.Get("ip_cidr_range").(string))
+	if err != nil {
+		return err
+	}
+
+	subnetworkRanges, otherRanges, err := listSubnetworkRangesInUse(config, userAgent, network, region)
+	if err != nil {
+		return handleNotFoundError(err, d, fmt.Sprintf("Network %q", network.RelativeLink()))
+	}
+	if overlap := subnetworkRangeAllocationOverlap(allocated, subnetworkRanges, otherRanges); overlap != nil {
+		log.Printf("[WARN] Removing range allocation %s because it overlaps %s in use in network %s", allocated, overlap, network.RelativeLink())
+		d.SetId("")
+	}
+	return nil
+}
+
+func resourceComputeSubnetworkRangeAllocationDelete(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*Config)
+
+	network, err := ParseNetworkFieldValue(d.Get("network").(string), d, config)
+	if err != nil {
+		return err
+	}
+	config.subnetworkRangeAllocations.remove(network.RelativeLink(), d.Get("ip_cidr_range").(string))
+
+	d.SetId("")
+	return nil
+}
+
+// listSubnetworkRangesInUse returns the primary and secondary ranges of the
+// subnetworks of a network and, separately, the ranges reserved in it for
+// private services access and the subnetwork ranges imported from its peered
+// networks.
+func listSubnetworkRangesInUse(config *Config, userAgent string, network *GlobalFieldValue, region string) ([]*net.IPNet, []*net.IPNet, error) {
+	computeClient := config.NewComputeClient(userAgent)
+	var subnetworkCidrs, cidrs []string
+
+	err := computeClient.Subnetworks.AggregatedList(network.Project).Pages(config.context, func(list *compute.SubnetworkAggregatedList) error {
+		for _, scoped := range list.Items {
+			for _, s := range scoped.Subnetworks {
+				if !compareSelfLinkRelativePaths("", s.Network, network.RelativeLink(), nil) {
+					continue
+				}
+				subnetworkCidrs = append(subnetworkCidrs, s.IpCidrRange)
+				for _, r := range s.SecondaryIpRanges {
+					subnetworkCidrs = append(subnetworkCidrs, r.IpCidrRange)
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("Error listing subnetworks: %s", err)
+	}
+
+	err = computeClient.GlobalAddresses.List(network.Project).Pages(config.context, func(list *compute.AddressList) error {
+		for _, a := range list.Items {
+			if a.Purpose == "VPC_PEERING" && compareSelfLinkRelativePaths("", a.Network, network.RelativeLink(), nil) {
+				cidrs = append(cidrs, fmt.Sprintf("%s/%d", a.Address, a.PrefixLength))
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("Error listing global addresses: %s", err)
+	}
+
+	n, err := computeClient.Networks.Get(network.Project, network.Name).Do()
+	if err != nil {
+		return nil, nil, errwrap.Wrapf(fmt.Sprintf("Error reading network %s: {{err}}", network.RelativeLink()), err)
+	}
+	for _, peering := range n.Peerings {
+		if peering.State != "ACTIVE" {
+			continue
+		}
+		err := computeClient.Networks.ListPeeringRoutes(network.Project, network.Name).Direction("INCOMING").PeeringName(peering.Name).Region(region).Pages(config.context, func(list *compute.ExchangedPeeringRoutesList) error {
+			for _, r := range list.Items {
+				if r.Type == "SUBNET_PEERING_ROUTE" {
+					cidrs = append(cidrs, r.DestRange)
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, nil, fmt.Errorf("Error listing routes imported from peering %s: %s", peering.Name, err)
+		}
+	}
+
+	subnetworkRanges, err := parseSubnetworkRanges(subnetworkCidrs)
+	if err != nil {
+		return nil, nil, err
+	}
+	ranges, err := parseSubnetworkRanges(cidrs)
+	if err != nil {
+		return nil, nil, err
+	}
+	return subnetworkRanges, ranges, nil
+}
+
+func parseSubnetworkRanges(cidrs []string) ([]*net.IPNet, error) {
+	var ranges []*net.IPNet
+	for _, c := range cidrs {
+		_, ipNet, err := net.ParseCIDR(c)
+		if err != nil {
+			return nil, fmt.Errorf("Error parsing range %q: %s", c, err)
+		}
+		ranges = append(ranges, ipNet)
+	}
+	return ranges, nil
+}
+
+// subnetworkRangeAllocationOverlap returns a range in use that the allocated
+// range overlaps, if any. Subnetwork ranges equal to the allocated range are
+// expected, as the allocation is meant to be used by a subnetwork.
+func subnetworkRangeAllocationOverlap(allocated *net.IPNet, subnetworkRanges, otherRanges []*net.IPNet) *net.IPNet {
+	for _, r := range subnetworkRanges {
+		if r.String() != allocated.String() && ipNetsOverlap(allocated, r) {
+			return r
+		}
+	}
+	for _, r := range otherRanges {
+		if ipNetsOverlap(allocated, r) {
+			return r
+		}
+	}
+	return nil
+}
+
+func ipNetsOverlap(a, b *net.IPNet) bool {
+	return a.Contains(b.IP) || b.Contains(a.IP)
+}
+
+// allocateSubnetworkRange returns the first range of the given prefix length
+// in supernet that doesn't overlap any of the used ranges.
+func allocateSubnetworkRange(supernet *net.IPNet, prefixLength int, used []*net.IPNet) (*net.IPNet, error) {
+	ones, bits := supernet.Mask.Size()
+	if supernet.IP.To4() == nil || bits != 32 {
+		return nil, fmt.Errorf("supernet %s is not an IPv4 range", supernet)
+	}
+	if prefixLength < ones || prefixLength > 32 {
+		return nil, fmt.Errorf("cannot allocate a /%d range in %s", prefixLength, supernet)
+	}
+
+	start := uint64(binary.BigEndian.Uint32(supernet.IP.To4()))
+	end := start + uint64(1)<<uint(32-ones)
+	size := uint64(1) << uint(32-prefixLength)
+
+	for candidate := start; candidate+size <= end; {
+		next := candidate
+		for _, u := range used {
+			if u.IP.To4() == nil {
+				continue
+			}
+			uOnes, _ := u.Mask.Size()
+			uStart := uint64(binary.BigEndian.Uint32(u.IP.To4()))
+			uEnd := uStart + uint64(1)<<uint(32-uOnes)
+			if uStart < candidate+size && candidate < uEnd {
+				// Skip to the first aligned range after the used one.
+				next = (uEnd + size - 1) / size * size
+				break
+			}
+		}
+		if next == candidate {
+			ip := make(net.IP, 4)
+			binary.BigEndian.PutUint32(ip, uint32(candidate))
+			return &net.IPNet{IP: ip, Mask: net.CIDRMask(prefixLength, 32)}, nil
+		}
+		candidate = next
+	}
+	return nil, fmt.Errorf("no free /%d range left in %s", prefixLength, supernet)
+}
@@ -0,0 +1,220 @@
+package google
+
+import (
+	"net"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAllocateSubnetworkRange(t *testing.T) {
+	cases := map[string]struct {
+		supernet     string
+		prefixLength int
+		used         []string
+		expected     string
+		expectError  bool
+	}{
+		"empty supernet": {
+			supernet:     "10.0.0.0/16",
+			prefixLength: 24,
+			expected:     "10.0.0.0/24",
+		},
+		"first free range": {
+			supernet:     "10.0.0.0/16",
+			prefixLength: 24,
+			used:         []string{"10.0.0.0/24", "10.0.1.0/25", "10.0.3.0/24"},
+			expected:     "10.0.2.0/24",
+		},
+		"aligned after a smaller range": {
+			supernet:     "10.0.0.0/16",
+			prefixLength: 22,
+			used:         []string{"10.0.0.0/24", "10.0.4.16/28"},
+			expected:     "10.0.8.0/22",
+		},
+		"larger range overlapping the supernet": {
+			supernet:     "10.0.0.0/16",
+			prefixLength: 20,
+			used:         []string{"10.0.0.0/8"},
+			expectError:  true,
+		},
+		"ranges outside the supernet": {
+			supernet:     "192.168.0.0/24",
+			prefixLength: 26,
+			used:         []string{"10.0.0.0/8", "192.168.1.0/24", "fd20::/64"},
+			expected:     "192.168.0.0/26",
+		},
+		"supernet full": {
+			supernet:     "192.168.0.0/24",
+			prefixLength: 25,
+			used:         []string{"192.168.0.0/26", "192.168.0.128/25"},
+			expectError:  true,
+		},
+		"prefix length shorter than the supernet": {
+			supernet:     "192.168.0.0/24",
+			prefixLength: 16,
+			expectError:  true,
+		},
+		"end of the address space": {
+			supernet:     "255.255.255.0/24",
+			prefixLength: 25,
+			used:         []string{"255.255.255.0/25"},
+			expected:     "255.255.255.128/25",
+		},
+	}
+
+	for tn, tc := range cases {
+		_, supernet, err := net.ParseCIDR(tc.supernet)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var used []*net.IPNet
+		for _, u := range tc.used {
+			_, n, err := net.ParseCIDR(u)
+			if err != nil {
+				t.Fatal(err)
+			}
+			used = append(used, n)
+		}
+
+		allocated, err := allocateSubnetworkRange(supernet, tc.prefixLength, used)
+		if tc.expectError {
+			if err == nil {
+				t.Errorf("%s: expected error, got %s", tn, allocated)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", tn, err)
+			continue
+		}
+		if allocated.String() != tc.expected {
+			t.Errorf("%s: expected %s, got %s", tn, tc.expected, allocated)
+		}
+	}
+}
+
+func TestSubnetworkRangeAllocationOverlap(t *testing.T) {
+	cases := map[string]struct {
+		subnetworkRanges []string
+		otherRanges      []string
+		expected         string
+	}{
+		"free": {
+			subnetworkRanges: []string{"10.0.0.0/24"},
+			otherRanges:      []string{"10.0.2.0/24"},
+		},
+		"used by a subnetwork": {
+			subnetworkRanges: []string{"10.0.0.0/24", "10.0.1.0/24"},
+		},
+		"overlapping a subnetwork": {
+			subnetworkRanges: []string{"10.0.1.128/25"},
+			expected:         "10.0.1.128/25",
+		},
+		"inside a subnetwork": {
+			subnetworkRanges: []string{"10.0.0.0/16"},
+			expected:         "10.0.0.0/16",
+		},
+		"reserved for private services access": {
+			otherRanges: []string{"10.0.1.0/24"},
+			expected:    "10.0.1.0/24",
+		},
+	}
+
+	_, allocated, err := net.ParseCIDR("10.0.1.0/24")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for tn, tc := range cases {
+		subnetworkRanges, err := parseSubnetworkRanges(tc.subnetworkRanges)
+		if err != nil {
+			t.Fatal(err)
+		}
+		otherRanges, err := parseSubnetworkRanges(tc.otherRanges)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		overlap := subnetworkRangeAllocationOverlap(allocated, subnetworkRanges, otherRanges)
+		if tc.expected == "" {
+			if overlap != nil {
+				t.Errorf("%s: expected no overlap, got %s", tn, overlap)
+			}
+			continue
+		}
+		if overlap == nil || overlap.String() != tc.expected {
+			t.Errorf("%s: expected overlap with %s, got %v", tn, tc.expected, overlap)
+		}
+	}
+}
+
+func TestAccComputeSubnetworkRangeAllocation_basic(t *testing.T) {
+	t.Parallel()
+
+	context := map[string]interface{}{
+		"random_suffix": randString(t, 10),
+	}
+
+	vcrTest(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccComputeSubnetworkRangeAllocation_basic(context),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("google_compute_subnetwork_range_allocation.first", "ip_cidr_range", "10.2.2.0/24"),
+					resource.TestCheckResourceAttr("google_compute_subnetwork_range_allocation.second", "ip_cidr_range", "10.2.4.0/23"),
+					resource.TestCheckResourceAttr("google_compute_subnetwork.allocated", "ip_cidr_range", "10.2.2.0/24"),
+				),
+			},
+			{
+				// The allocations stay stable once their ranges are in use.
+				Config:   testAccComputeSubnetworkRangeAllocation_basic(context),
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+func testAccComputeSubnetworkRangeAllocation_basic(context map[string]interface{}) string {
+	return Nprintf(`
+resource "google_compute_network" "network" {
+  name                    = "tf-test-network%{random_suffix}"
+  auto_create_subnetworks = false
+}
+
+resource "google_compute_subnetwork" "existing" {
+  name          = "tf-test-existing%{random_suffix}"
+  region        = "us-central1"
+  network       = google_compute_network.network.id
+  ip_cidr_range = "10.2.0.0/24"
+
+  secondary_ip_range {
+    range_name    = "pods"
+    ip_cidr_range = "10.2.1.0/24"
+  }
+}
+
+resource "google_compute_subnetwork_range_allocation" "first" {
+  network       = google_compute_network.network.id
+  supernet      = "10.2.0.0/16"
+  prefix_length = 24
+
+  depends_on = [google_compute_subnetwork.existing]
+}
+
+resource "google_compute_subnetwork_range_allocation" "second" {
+  network         = google_compute_network.network.id
+  supernet        = "10.2.0.0/16"
+  prefix_length   = 23
+  reserved_ranges = [google_compute_subnetwork_range_allocation.first.ip_cidr_range]
+}
+
+resource "google_compute_subnetwork" "allocated" {
+  name          = "tf-test-allocated%{random_suffix}"
+  region        = "us-central1"
+  network       = google_compute_network.network.id
+  ip_cidr_range = google_compute_subnetwork_range_allocation.first.ip_cidr_range
+}
+`, context)
+}
@@ -1407,6 +1407,22 @@ func resourceContainerClusterCreate(d *schema.ResourceData, meta interface{}) er
 
 	log.Printf("[INFO] GKE cluster %s has been created", clusterName)
 
+	// Upgrade strategies can't be sent with the cluster, set them on the
+	// created node pools instead.
+	for i, np := range cluster.NodePools {
+		prefix := fmt.Sprintf("node_pool.%d.", i)
+		if d.Get(prefix+"upgrade_settings.0.strategy").(string) != "BLUE_GREEN" {
+			continue
+		}
+		nodePoolInfo, err := extractNodePoolInformationFromCluster(d, config, clusterName)
+		if err != nil {
+			return err
+		}
+		if err := containerNodePoolSetUpgradeSettings(config, nodePoolInfo, np.Name, d.Get(prefix+"upgrade_settings"), userAgent, d.Timeout(schema.TimeoutCreate)); err != nil {
+			return err
+		}
+	}
+
 	if d.Get("remove_default_node_pool").(bool) {
 		parent := fmt.Sprintf("%s/nodePools/%s", containerClusterFullName(project, location, clusterName), "default-pool")
 		err = retry(func() error {
@@ -1611,7 +1627,7 @@ func resourceContainerClusterRead(d *schema.ResourceData, meta interface{}) erro
 	if err := d.Set("addons_config", flattenClusterAddonsConfig(cluster.AddonsConfig)); err != nil {
 		return err
 	}
-	nps, err := flattenClusterNodePools(d, config, cluster.NodePools)
+	nps, err := flattenClusterNodePools(d, config, cluster, project)
 	if err != nil {
 		return err
 	}
@@ -3124,11 +3140,36 @@ func flattenClusterAddonsConfig(c *container.AddonsConfig) []map[string]interfac
 	return []map[string]interface{}{result}
 }
 
-func flattenClusterNodePools(d *schema.ResourceData, config *Config, c []*container.NodePool) ([]map[string]interface{}, error) {
-	nodePools := make([]map[string]interface{}, 0, len(c))
+func flattenClusterNodePools(d *schema.ResourceData, config *Config, cluster *container.Cluster, project string) ([]map[string]interface{}, error) {
+	nodePools := make([]map[string]interface{}, 0, len(cluster.NodePools))
 
-	for i, np := range c {
-		nodePool, err := flattenNodePool(d, config, np, fmt.Sprintf("node_pool.%d.", i))
+	// Upgrade strategies aren't known to the container client, read them from
+	// the raw cluster instead, once for all of its node pools.
+	rawNodePools := make(map[string]map[string]interface{})
+	if containerClusterNodePoolsUseBlueGreen(d) {
+		userAgent, err := generateUserAgentString(d, config.userAgent)
+		if err != nil {
+			return nil, err
+		}
+		location, err := getLocation(d, config)
+		if err != nil {
+			return nil, err
+		}
+		raw, err := containerNodePoolGetRaw(config, containerClusterFullName(project, location, cluster.Name), project, userAgent)
+		if err != nil {
+			return nil, err
+		}
+		rawList, _ := raw["nodePools"].([]interface{})
+		for _, v := range rawList {
+			if rawNp, ok := v.(map[string]interface{}); ok {
+				name, _ := rawNp["name"].(string)
+				rawNodePools[name] = rawNp
+			}
+		}
+	}
+
+	for i, np := range cluster.NodePools {
+		nodePool, err := flattenNodePoolWithRaw(d, config, np, rawNodePools[np.Name], fmt.Sprintf("node_pool.%d.", i))
 		if err != nil {
 			return nil, err
 		}
@@ -3138,6 +3179,30 @@ func flattenClusterNodePools(d *schema.ResourceData, config *Config, c []*contai
 	return nodePools, nil
 }
 
+// containerClusterNodePoolsUseBlueGreen reports whether any node pool of the
+// cluster is known to use the BLUE_GREEN upgrade strategy, either from its
+// config or from a previous read. Only those pools need the raw cluster to
+// read their upgrade settings, so a pool switched to BLUE_GREEN outside of
+// Terraform isn't detected until its upgrade settings are configured.
+func containerClusterNodePoolsUseBlueGreen(d *schema.ResourceData) bool {
+	for _, np := range d.Get("node_pool").([]interface{}) {
+		npMap, ok := np.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		for _, us := range npMap["upgrade_settings"].([]interface{}) {
+			usMap, ok := us.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if usMap["strategy"] == "BLUE_GREEN" || len(usMap["blue_green_settings"].([]interface{})) > 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 func flattenAuthenticatorGroupsConfig(c *container.AuthenticatorGroupsConfig) []map[string]interface{} {
 	if c == nil {
 		return nil
@@ -70,6 +70,57 @@ func resourceContainerNodePool() *schema.Resource {
 					Type:     schema.TypeString,
 					Computed: true,
 				},
+				"upgrade_rollout_action": {
+					Type:         schema.TypeString,
+					Optional:     true,
+					ValidateFunc: validation.StringInSlice([]string{"COMPLETE", "ROLLBACK"}, false),
+					Description:  `Action to take on an in-progress blue-green upgrade of this node pool the next time Terraform is applied. COMPLETE skips the remaining soak time and deletes the blue pool, ROLLBACK drains the green pool and returns to the previous version. Has no effect when no blue-green upgrade is in progress.`,
+				},
+				"update_info": {
+					Type:        schema.TypeList,
+					Computed:    true,
+					Description: `Information about the most recent update of the node pool.`,
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"blue_green_info": {
+								Type:        schema.TypeList,
+								Computed:    true,
+								Description: `Information about a blue-green upgrade.`,
+								Elem: &schema.Resource{
+									Schema: map[string]*schema.Schema{
+										"phase": {
+											Type:        schema.TypeString,
+											Computed:    true,
+											Description: `The current phase of the blue-green upgrade.`,
+										},
+										"blue_instance_group_urls": {
+											Type:        schema.TypeList,
+											Computed:    true,
+											Elem:        &schema.Schema{Type: schema.TypeString},
+											Description: `The resource URLs of the managed instance groups associated with the blue pool.`,
+										},
+										"green_instance_group_urls": {
+											Type:        schema.TypeList,
+											Computed:    true,
+											Elem:        &schema.Schema{Type: schema.TypeString},
+											Description: `The resource URLs of the managed instance groups associated with the green pool.`,
+										},
+										"blue_pool_deletion_start_time": {
+											Type:        schema.TypeString,
+											Computed:    true,
+											Description: `Time to start deleting the blue pool to complete the blue-green upgrade, in RFC3339 text format.`,
+										},
+										"green_pool_version": {
+											Type:        schema.TypeString,
+											Computed:    true,
+											Description: `Version of the green pool.`,
+										},
+									},
+								},
+							},
+						},
+					},
+				},
 			}),
 	}
 }
@@ -120,22 +171,82 @@ var schemaNodePool = map[string]*schema.Schema{
 		Optional:    true,
 		Computed:    true,
 		MaxItems:    1,
-		Description: `Specify node upgrade settings to change how many nodes GKE attempts to upgrade at once. The number of nodes upgraded simultaneously is the sum of max_surge and max_unavailable. The maximum number of nodes upgraded simultaneously is limited to 20.`,
+		Description: `Specify node upgrade settings to change how GKE upgrades the nodes of the pool. With the SURGE strategy, the number of nodes upgraded simultaneously is the sum of max_surge and max_unavailable. The maximum number of nodes upgraded simultaneously is limited to 20.`,
 		Elem: &schema.Resource{
 			Schema: map[string]*schema.Schema{
 				"max_surge": {
 					Type:         schema.TypeInt,
-					Required:     true,
+					Optional:     true,
+					Computed:     true,
 					ValidateFunc: validation.IntAtLeast(0),
 					Description:  `The number of additional nodes that can be added to the node pool during an upgrade. Increasing max_surge raises the number of nodes that can be upgraded simultaneously. Can be set to 0 or greater.`,
 				},
 
 				"max_unavailable": {
 					Type:         schema.TypeInt,
-					Required:     true,
+					Optional:     true,
+					Computed:     true,
 					ValidateFunc: validation.IntAtLeast(0),
 					Description:  `The number of nodes that can be simultaneously unavailable during an upgrade. Increasing max_unavailable raises the number of nodes that can be upgraded in parallel. Can be set to 0 or greater.`,
 				},
+
+				"strategy": {
+					Type:         schema.TypeString,
+					Optional:     true,
+					Computed:     true,
+					ValidateFunc: validation.StringInSlice([]string{"SURGE", "BLUE_GREEN"}, false),
+					Description:  `Update strategy for the node pool. One of SURGE or BLUE_GREEN.`,
+				},
+
+				"blue_green_settings": {
+					Type:        schema.TypeList,
+					Optional:    true,
+					Computed:    true,
+					MaxItems:    1,
+					Description: `Settings for the BLUE_GREEN update strategy.`,
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"standard_rollout_policy": {
+								Type:        schema.TypeList,
+								Required:    true,
+								MaxItems:    1,
+								Description: `Standard rollout policy is the default policy for blue-green.`,
+								Elem: &schema.Resource{
+									Schema: map[string]*schema.Schema{
+										"batch_percentage": {
+											Type:         schema.TypeFloat,
+											Optional:     true,
+											Computed:     true,
+											ValidateFunc: validation.FloatBetween(0.0, 1.0),
+											Description:  `Percentage of the blue pool nodes to drain in a batch. Conflicts with batch_node_count.`,
+										},
+										"batch_node_count": {
+											Type:         schema.TypeInt,
+											Optional:     true,
+											Computed:     true,
+											ValidateFunc: validation.IntAtLeast(0),
+											Description:  `Number of blue nodes to drain in a batch. Conflicts with batch_percentage.`,
+										},
+										"batch_soak_duration": {
+											Type:         schema.TypeString,
+											Optional:     true,
+											Computed:     true,
+											ValidateFunc: validateNonNegativeDuration(),
+											Description:  `Soak time after each batch gets drained, in seconds with up to nine fractional digits terminated by 's'. Example: "3.5s".`,
+										},
+									},
+								},
+							},
+							"node_pool_soak_duration": {
+								Type:         schema.TypeString,
+								Optional:     true,
+								Computed:     true,
+								ValidateFunc: validateNonNegativeDuration(),
+								Description:  `Time needed after draining the entire blue pool. After this period, the blue pool will be cleaned up, in seconds with up to nine fractional digits terminated by 's'. Example: "3600s".`,
+							},
+						},
+					},
+				},
 			},
 		},
 	},
@@ -398,6 +509,13 @@ func resourceContainerNodePoolCreate(d *schema.ResourceData, meta interface{}) e
 		return fmt.Errorf("NodePool %s was created in the error state %q", nodePool.Name, state)
 	}
 
+	if d.Get("upgrade_settings.0.strategy").(string) == "BLUE_GREEN" {
+		if err := containerNodePoolSetUpgradeSettings(config, nodePoolInfo, nodePool.Name, d.Get("upgrade_settings"), userAgent, d.Timeout(schema.TimeoutCreate)); err != nil {
+			return err
+		}
+		return resourceContainerNodePoolRead(d, meta)
+	}
+
 	return nil
 }
 
@@ -430,19 +548,32 @@ func resourceContainerNodePoolRead(d *schema.ResourceData, meta interface{}) err
 
 	name := getNodePoolName(d.Id())
 
-	clusterNodePoolsGetCall := config.NewContainerClient(userAgent).Projects.Locations.Clusters.NodePools.Get(nodePoolInfo.fullyQualifiedName(name))
-	if config.UserProjectOverride {
-		clusterNodePoolsGetCall.Header().Add("X-Goog-User-Project", nodePoolInfo.project)
-	}
-	nodePool, err := clusterNodePoolsGetCall.Do()
+	// The node pool is read without the container client as it doesn't know
+	// about upgrade strategies or update info yet.
+	res, err := containerNodePoolGetRaw(config, nodePoolInfo.fullyQualifiedName(name), nodePoolInfo.project, userAgent)
 	if err != nil {
 		return handleNotFoundError(err, d, fmt.Sprintf("NodePool %q from cluster %q", name, nodePoolInfo.cluster))
 	}
 
-	npMap, err := flattenNodePool(d, config, nodePool, "")
+	nodePool := &container.NodePool{}
+	if err := Convert(res, nodePool); err != nil {
+		return err
+	}
+
+	npMap, err := flattenNodePoolWithRaw(d, config, nodePool, res, "")
 	if err != nil {
 		return err
 	}
+	npMap["update_info"] = flattenNodePoolUpdateInfo(res["updateInfo"])
+
+	// Only keep the configured rollout action in state while there is nothing
+	// for it to act on. During a blue-green upgrade, clearing it makes the
+	// configured action show up as a diff, so re-applying carries it out.
+	if containerNodePoolBlueGreenUpgradeInProgress(res) {
+		npMap["upgrade_rollout_action"] = ""
+	} else {
+		npMap["upgrade_rollout_action"] = d.Get("upgrade_rollout_action")
+	}
 
 	for k, v := range npMap {
 		if err := d.Set(k, v); err != nil {
@@ -473,6 +604,14 @@ func resourceContainerNodePoolUpdate(d *schema.ResourceData, meta interface{}) e
 	}
 	name := getNodePoolName(d.Id())
 
+	// A blue-green upgrade keeps the node pool reconciling until it is
+	// completed or rolled back, so act on it before waiting for a resting state.
+	if d.HasChange("upgrade_rollout_action") {
+		if err := containerNodePoolRolloutAction(config, nodePoolInfo, name, d.Get("upgrade_rollout_action").(string), userAgent, d.Timeout(schema.TimeoutUpdate)); err != nil {
+			return err
+		}
+	}
+
 	//Check cluster is in running state
 	_, err = containerClusterAwaitRestingState(config, nodePoolInfo.project, nodePoolInfo.location, nodePoolInfo.cluster, userAgent, d.Timeout(schema.TimeoutCreate))
 	if err != nil {
@@ -722,6 +861,10 @@ func expandNodePool(d *schema.ResourceData, prefix string) (*container.NodePool,
 	}
 
 	if v, ok := d.GetOk(prefix + "upgrade_settings"); ok {
+		if _, err := expandNodePoolUpgradeSettings(v); err != nil {
+			return nil, err
+		}
+
 		upgradeSettingsConfig := v.([]interface{})[0].(map[string]interface{})
 		np.UpgradeSettings = &container.UpgradeSettings{}
 
@@ -737,7 +880,9 @@ func expandNodePool(d *schema.ResourceData, prefix string) (*container.NodePool,
 	return np, nil
 }
 
-func flattenNodePool(d *schema.ResourceData, config *Config, np *container.NodePool, prefix string) (map[string]interface{}, error) {
+// flattenNodePoolWithRaw flattens a node pool, taking the fields that the
+// container client doesn't expose from the raw API response.
+func flattenNodePoolWithRaw(d *schema.ResourceData, config *Config, np *container.NodePool, raw map[string]interface{}, prefix string) (map[string]interface{}, error) {
 	userAgent, err := generateUserAgentString(d, config.userAgent)
 	if err != nil {
 		return nil, err
@@ -807,13 +952,16 @@ func flattenNodePool(d *schema.ResourceData, config *Config, np *container.NodeP
 		},
 	}
 
-	if np.UpgradeSettings != nil {
-		nodePool["upgrade_settings"] = []map[string]interface{}{
-			{
-				"max_surge":       np.UpgradeSettings.MaxSurge,
-				"max_unavailable": np.UpgradeSettings.MaxUnavailable,
-			},
-		}
+	if np.UpgradeSettings != nil && raw != nil {
+		nodePool["upgrade_settings"] = flattenNodePoolUpgradeSettings(raw["upgradeSettings"])
+	} else if np.UpgradeSettings != nil {
+		// Without the raw node pool the strategy can't be read, so keep the one
+		// from the previous read.
+		nodePool["upgrade_settings"] = flattenNodePoolUpgradeSettings(map[string]interface{}{
+			"maxSurge":       np.UpgradeSettings.MaxSurge,
+			"maxUnavailable": np.UpgradeSettings.MaxUnavailable,
+			"strategy":       d.Get(prefix + "upgrade_settings.0.strategy"),
+		})
 	} else {
 		delete(nodePool, "upgrade_settings")
 	}
@@ -1077,28 +1225,8 @@ func nodePoolUpdate(d *schema.ResourceData, meta interface{}, nodePoolInfo *Node
 	}
 
 	if d.HasChange(prefix + "upgrade_settings") {
-		upgradeSettings := &container.UpgradeSettings{}
-		if v, ok := d.GetOk(prefix + "upgrade_settings"); ok {
-			upgradeSettingsConfig := v.([]interface{})[0].(map[string]interface{})
-			upgradeSettings.MaxSurge = int64(upgradeSettingsConfig["max_surge"].(int))
-			upgradeSettings.MaxUnavailable = int64(upgradeSettingsConfig["max_unavailable"].(int))
-		}
-		req := &container.UpdateNodePoolRequest{
-			UpgradeSettings: upgradeSettings,
-		}
 		updateF := func() error {
-			clusterNodePoolsUpdateCall := config.NewContainerClient(userAgent).Projects.Locations.Clusters.NodePools.Update(nodePoolInfo.fullyQualifiedName(name), req)
-			if config.UserProjectOverride {
-				clusterNodePoolsUpdateCall.Header().Add("X-Goog-User-Project", nodePoolInfo.project)
-			}
-			op, err := clusterNodePoolsUpdateCall.Do()
-
-			if err != nil {
-				return err
-			}
-
-			// Wait until it's updated
-			return containerOperationWait(config, op, nodePoolInfo.project, nodePoolInfo.location, "updating GKE node pool upgrade settings", userAgent, timeout)
+			return containerNodePoolSetUpgradeSettings(config, nodePoolInfo, name, d.Get(prefix+"upgrade_settings"), userAgent, timeout)
 		}
 
 		// Call update serially.
@@ -1152,3 +1280,228 @@ func containerNodePoolAwaitRestingState(config *Config, name, project, userAgent
 
 	return state, err
 }
+
+// Phases of a blue-green upgrade during which it can be completed or rolled back.
+var containerNodePoolBlueGreenActivePhases = map[string]bool{
+	"UPDATE_STARTED":      true,
+	"CREATING_GREEN_POOL": true,
+	"CORDONING_BLUE_POOL": true,
+	"DRAINING_BLUE_POOL":  true,
+	"NODE_POOL_SOAKING":   true,
+	"DELETING_BLUE_POOL":  true,
+}
+
+func containerNodePoolGetRaw(config *Config, name, project, userAgent string) (map[string]interface{}, error) {
+	url := name
+	if !strings.HasPrefix(url, "https://") {
+		url = config.ContainerBasePath + name
+	}
+	return sendRequest(config, "GET", project, url, userAgent, nil)
+}
+
+func containerNodePoolBlueGreenUpgradeInProgress(res map[string]interface{}) bool {
+	updateInfo, ok := res["updateInfo"].(map[string]interface{})
+	if !ok {
+		return false
+	}
+	blueGreenInfo, ok := updateInfo["blueGreenInfo"].(map[string]interface{})
+	if !ok {
+		return false
+	}
+	phase, _ := blueGreenInfo["phase"].(string)
+	return containerNodePoolBlueGreenActivePhases[phase]
+}
+
+// containerNodePoolSetUpgradeSettings updates the upgrade settings of a node
+// pool. The container client can't express upgrade strategies, so the request
+// is sent directly and the returned operation converted for polling.
+func containerNodePoolSetUpgradeSettings(config *Config, nodePoolInfo *NodePoolInformation, name string, v interface{}, userAgent string, timeout time.Duration) error {
+	upgradeSettings, err := expandNodePoolUpgradeSettings(v)
+	if err != nil {
+		return err
+	}
+
+	obj := map[string]interface{}{
+		"upgradeSettings": upgradeSettings,
+	}
+	url := config.ContainerBasePath + nodePoolInfo.fullyQualifiedName(name)
+	res, err := sendRequestWithTimeout(config, "PUT", nodePoolInfo.project, url, userAgent, obj, timeout)
+	if err != nil {
+		return fmt.Errorf("Error updating upgrade settings of NodePool %s: %s", name, err)
+	}
+
+	op := &container.Operation{}
+	if err := Convert(res, op); err != nil {
+		return err
+	}
+
+	return containerOperationWait(config, op, nodePoolInfo.project, nodePoolInfo.location, "updating GKE node pool upgrade settings", userAgent, timeout)
+}
+
+// containerNodePoolRolloutAction completes or rolls back an in-progress
+// blue-green upgrade. It does nothing if no such upgrade is in progress.
+func containerNodePoolRolloutAction(config *Config, nodePoolInfo *NodePoolInformation, name, action, userAgent string, timeout time.Duration) error {
+	if action == "" {
+		return nil
+	}
+
+	res, err := containerNodePoolGetRaw(config, nodePoolInfo.fullyQualifiedName(name), nodePoolInfo.project, userAgent)
+	if err != nil {
+		return err
+	}
+	if !containerNodePoolBlueGreenUpgradeInProgress(res) {
+		log.Printf("[DEBUG] No blue-green upgrade in progress for NodePool %s, skipping %s", name, action)
+		return nil
+	}
+
+	switch action {
+	case "COMPLETE":
+		// completeUpgrade returns no operation; the caller waits for the node
+		// pool to reach a resting state.
+		url := config.ContainerBasePath + nodePoolInfo.fullyQualifiedName(name) + ":completeUpgrade"
+		if _, err := sendRequestWithTimeout(config, "POST", nodePoolInfo.project, url, userAgent, map[string]interface{}{}, timeout); err != nil {
+			return fmt.Errorf("Error completing upgrade of NodePool %s: %s", name, err)
+		}
+		log.Printf("[INFO] Completed blue-green upgrade of NodePool %s", name)
+	case "ROLLBACK":
+		clusterNodePoolsRollbackCall := config.NewContainerClient(userAgent).Projects.Locations.Clusters.NodePools.Rollback(nodePoolInfo.fullyQualifiedName(name), &container.RollbackNodePoolUpgradeRequest{})
+		if config.UserProjectOverride {
+			clusterNodePoolsRollbackCall.Header().Add("X-Goog-User-Project", nodePoolInfo.project)
+		}
+		op, err := clusterNodePoolsRollbackCall.Do()
+		if err != nil {
+			return fmt.Errorf("Error rolling back upgrade of NodePool %s: %s", name, err)
+		}
+		if err := containerOperationWait(config, op, nodePoolInfo.project, nodePoolInfo.location, "rolling back GKE node pool upgrade", userAgent, timeout); err != nil {
+			return err
+		}
+		log.Printf("[INFO] Rolled back blue-green upgrade of NodePool %s", name)
+	}
+
+	return nil
+}
+
+func expandNodePoolUpgradeSettings(v interface{}) (map[string]interface{}, error) {
+	l := v.([]interface{})
+	if len(l) == 0 || l[0] == nil {
+		return map[string]interface{}{}, nil
+	}
+	raw := l[0].(map[string]interface{})
+
+	transformed := map[string]interface{}{
+		"maxSurge":       raw["max_surge"],
+		"maxUnavailable": raw["max_unavailable"],
+	}
+	if v, ok := raw["strategy"]; ok && v.(string) != "" {
+		transformed["strategy"] = v
+	}
+
+	bgs, ok := raw["blue_green_settings"].([]interface{})
+	if !ok || len(bgs) == 0 || bgs[0] == nil {
+		return transformed, nil
+	}
+	if transformed["strategy"] != "BLUE_GREEN" {
+		return nil, fmt.Errorf("blue_green_settings can only be set when strategy is BLUE_GREEN")
+	}
+
+	blueGreenSettings := map[string]interface{}{}
+	bg := bgs[0].(map[string]interface{})
+	if v, ok := bg["node_pool_soak_duration"]; ok && v.(string) != "" {
+		blueGreenSettings["nodePoolSoakDuration"] = v
+	}
+	if srps, ok := bg["standard_rollout_policy"].([]interface{}); ok && len(srps) > 0 && srps[0] != nil {
+		srp := srps[0].(map[string]interface{})
+		standardRolloutPolicy := map[string]interface{}{}
+		percentage, _ := srp["batch_percentage"].(float64)
+		count, _ := srp["batch_node_count"].(int)
+		if percentage != 0 && count != 0 {
+			return nil, fmt.Errorf("only one of batch_percentage or batch_node_count can be set in standard_rollout_policy")
+		}
+		if percentage != 0 {
+			standardRolloutPolicy["batchPercentage"] = percentage
+		}
+		if count != 0 {
+			standardRolloutPolicy["batchNodeCount"] = count
+		}
+		if v, ok := srp["batch_soak_duration"]; ok && v.(string) != "" {
+			standardRolloutPolicy["batchSoakDuration"] = v
+		}
+		blueGreenSettings["standardRolloutPolicy"] = standardRolloutPolicy
+	}
+	transformed["blueGreenSettings"] = blueGreenSettings
+
+	return transformed, nil
+}
+
+func flattenNodePoolUpgradeSettings(v interface{}) []map[string]interface{} {
+	original, ok := v.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	transformed := map[string]interface{}{
+		"max_surge":       flattenNodePoolInteger(original["maxSurge"]),
+		"max_unavailable": flattenNodePoolInteger(original["maxUnavailable"]),
+		"strategy":        original["strategy"],
+	}
+
+	if bg, ok := original["blueGreenSettings"].(map[string]interface{}); ok {
+		blueGreenSettings := map[string]interface{}{
+			"node_pool_soak_duration": bg["nodePoolSoakDuration"],
+		}
+		if srp, ok := bg["standardRolloutPolicy"].(map[string]interface{}); ok {
+			blueGreenSettings["standard_rollout_policy"] = []map[string]interface{}{
+				{
+					"batch_percentage":    srp["batchPercentage"],
+					"batch_node_count":    flattenNodePoolInteger(srp["batchNodeCount"]),
+					"batch_soak_duration": srp["batchSoakDuration"],
+				},
+			}
+		}
+		transformed["blue_green_settings"] = []map[string]interface{}{blueGreenSettings}
+	}
+
+	return []map[string]interface{}{transformed}
+}
+
+func flattenNodePoolInteger(v interface{}) interface{} {
+	// Handles the string fixed64 format
+	if strVal, ok := v.(string); ok {
+		if intVal, err := stringToFixed64(strVal); err == nil {
+			return intVal
+		}
+	}
+
+	// number values are represented as float64
+	if floatVal, ok := v.(float64); ok {
+		intVal := int(floatVal)
+		return intVal
+	}
+
+	return v // let terraform core handle it otherwise
+}
+
+func flattenNodePoolUpdateInfo(v interface{}) []map[string]interface{} {
+	original, ok := v.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	bg, ok := original["blueGreenInfo"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	return []map[string]interface{}{
+		{
+			"blue_green_info": []map[string]interface{}{
+				{
+					"phase":                         bg["phase"],
+					"blue_instance_group_urls":      bg["blueInstanceGroupUrls"],
+					"green_instance_group_urls":     bg["greenInstanceGroupUrls"],
+					"blue_pool_deletion_start_time": bg["bluePoolDeletionStartTime"],
+					"green_pool_version":            bg["greenPoolVersion"],
+				},
+			},
+		},
+	}
+}
@@ -2,9 +2,11 @@ package google
 
 import (
 	"fmt"
+	"reflect"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
 
@@ -260,6 +262,249 @@ func TestAccContainerNodePool_withUpgradeSettings(t *testing.T) {
 	})
 }
 
+func TestAccContainerNodePool_withBlueGreenUpgradeSettings(t *testing.T) {
+	t.Parallel()
+
+	cluster := fmt.Sprintf("tf-test-cluster-%s", randString(t, 10))
+	np := fmt.Sprintf("tf-test-np-%s", randString(t, 10))
+
+	vcrTest(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckContainerClusterDestroyProducer(t),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccContainerNodePool_withBlueGreenUpgradeSettings(cluster, np, "10s", "3600s"),
+			},
+			{
+				ResourceName:            "google_container_node_pool.with_upgrade_settings",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"upgrade_rollout_action"},
+			},
+			{
+				Config: testAccContainerNodePool_withBlueGreenUpgradeSettings(cluster, np, "5s", "60s"),
+			},
+			{
+				ResourceName:            "google_container_node_pool.with_upgrade_settings",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"upgrade_rollout_action"},
+			},
+			{
+				Config: testAccContainerNodePool_withUpgradeSettings(cluster, np, 1, 1),
+			},
+			{
+				ResourceName:      "google_container_node_pool.with_upgrade_settings",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestNodePoolUpgradeSettings_expandFlatten(t *testing.T) {
+	cases := map[string]struct {
+		Config      []interface{}
+		Expected    map[string]interface{}
+		ExpectError bool
+	}{
+		"surge": {
+			Config: []interface{}{
+				map[string]interface{}{
+					"max_surge":           2,
+					"max_unavailable":     1,
+					"strategy":            "SURGE",
+					"blue_green_settings": []interface{}{},
+				},
+			},
+			Expected: map[string]interface{}{
+				"maxSurge":       2,
+				"maxUnavailable": 1,
+				"strategy":       "SURGE",
+			},
+		},
+		"blue green": {
+			Config: []interface{}{
+				map[string]interface{}{
+					"max_surge":       0,
+					"max_unavailable": 0,
+					"strategy":        "BLUE_GREEN",
+					"blue_green_settings": []interface{}{
+						map[string]interface{}{
+							"node_pool_soak_duration": "3600s",
+							"standard_rollout_policy": []interface{}{
+								map[string]interface{}{
+									"batch_percentage":    0.0,
+									"batch_node_count":    2,
+									"batch_soak_duration": "10s",
+								},
+							},
+						},
+					},
+				},
+			},
+			Expected: map[string]interface{}{
+				"maxSurge":       0,
+				"maxUnavailable": 0,
+				"strategy":       "BLUE_GREEN",
+				"blueGreenSettings": map[string]interface{}{
+					"nodePoolSoakDuration": "3600s",
+					"standardRolloutPolicy": map[string]interface{}{
+						"batchNodeCount":    2,
+						"batchSoakDuration": "10s",
+					},
+				},
+			},
+		},
+		"blue green settings without strategy": {
+			Config: []interface{}{
+				map[string]interface{}{
+					"max_surge":       1,
+					"max_unavailable": 0,
+					"strategy":        "SURGE",
+					"blue_green_settings": []interface{}{
+						map[string]interface{}{
+							"node_pool_soak_duration": "3600s",
+						},
+					},
+				},
+			},
+			ExpectError: true,
+		},
+		"batch percentage and node count": {
+			Config: []interface{}{
+				map[string]interface{}{
+					"strategy": "BLUE_GREEN",
+					"blue_green_settings": []interface{}{
+						map[string]interface{}{
+							"standard_rollout_policy": []interface{}{
+								map[string]interface{}{
+									"batch_percentage": 0.5,
+									"batch_node_count": 2,
+								},
+							},
+						},
+					},
+				},
+			},
+			ExpectError: true,
+		},
+	}
+
+	for tn, tc := range cases {
+		expanded, err := expandNodePoolUpgradeSettings(tc.Config)
+		if tc.ExpectError {
+			if err == nil {
+				t.Errorf("%s: expected error, got none", tn)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", tn, err)
+			continue
+		}
+		if !reflect.DeepEqual(expanded, tc.Expected) {
+			t.Errorf("%s: expected %#v, got %#v", tn, tc.Expected, expanded)
+		}
+	}
+
+	// Values read from the API are float64s and durations are strings.
+	flattened := flattenNodePoolUpgradeSettings(map[string]interface{}{
+		"maxSurge":       float64(0),
+		"maxUnavailable": float64(0),
+		"strategy":       "BLUE_GREEN",
+		"blueGreenSettings": map[string]interface{}{
+			"nodePoolSoakDuration": "3600s",
+			"standardRolloutPolicy": map[string]interface{}{
+				"batchPercentage":   0.5,
+				"batchSoakDuration": "10s",
+			},
+		},
+	})
+	expected := []map[string]interface{}{
+		{
+			"max_surge":       0,
+			"max_unavailable": 0,
+			"strategy":        "BLUE_GREEN",
+			"blue_green_settings": []map[string]interface{}{
+				{
+					"node_pool_soak_duration": "3600s",
+					"standard_rollout_policy": []map[string]interface{}{
+						{
+							"batch_percentage":    0.5,
+							"batch_node_count":    nil,
+							"batch_soak_duration": "10s",
+						},
+					},
+				},
+			},
+		},
+	}
+	if !reflect.DeepEqual(flattened, expected) {
+		t.Errorf("expected %#v, got %#v", expected, flattened)
+	}
+}
+
+func TestContainerClusterNodePoolsUseBlueGreen(t *testing.T) {
+	cases := map[string]struct {
+		NodePools []interface{}
+		Expected  bool
+	}{
+		"no upgrade settings": {
+			NodePools: []interface{}{
+				map[string]interface{}{"name": "default-pool"},
+			},
+		},
+		"surge": {
+			NodePools: []interface{}{
+				map[string]interface{}{
+					"name": "default-pool",
+					"upgrade_settings": []interface{}{
+						map[string]interface{}{"strategy": "SURGE", "max_surge": 1},
+					},
+				},
+			},
+		},
+		"blue green strategy": {
+			NodePools: []interface{}{
+				map[string]interface{}{"name": "default-pool"},
+				map[string]interface{}{
+					"name": "blue-green",
+					"upgrade_settings": []interface{}{
+						map[string]interface{}{"strategy": "BLUE_GREEN"},
+					},
+				},
+			},
+			Expected: true,
+		},
+		"blue green settings": {
+			NodePools: []interface{}{
+				map[string]interface{}{
+					"name": "default-pool",
+					"upgrade_settings": []interface{}{
+						map[string]interface{}{
+							"blue_green_settings": []interface{}{
+								map[string]interface{}{"node_pool_soak_duration": "3600s"},
+							},
+						},
+					},
+				},
+			},
+			Expected: true,
+		},
+	}
+
+	for tn, tc := range cases {
+		d := schema.TestResourceDataRaw(t, resourceContainerCluster().Schema, map[string]interface{}{
+			"node_pool": tc.NodePools,
+		})
+		if got := containerClusterNodePoolsUseBlueGreen(d); got != tc.Expected {
+			t.Errorf("%s: expected %t, got %t", tn, tc.Expected, got)
+		}
+	}
+}
+
 func TestAccContainerNodePool_withGPU(t *testing.T) {
 	t.Parallel()
 
@@ -1599,3 +1844,36 @@ resource "google_container_node_pool" "np" {
 }
 `, cluster, np)
 }
+
+func testAccContainerNodePool_withBlueGreenUpgradeSettings(clusterName, nodePoolName, batchSoakDuration, nodePoolSoakDuration string) string {
+	return fmt.Sprintf(`
+data "google_container_engine_versions" "central1" {
+  location = "us-central1"
+}
+
+resource "google_container_cluster" "cluster" {
+  name               = "%s"
+  location           = "us-central1"
+  initial_node_count = 1
+  min_master_version = "${data.google_container_engine_versions.central1.latest_master_version}"
+}
+
+resource "google_container_node_pool" "with_upgrade_settings" {
+  name = "%s"
+  location = "us-central1"
+  cluster = "${google_container_cluster.cluster.name}"
+  initial_node_count = 1
+  upgrade_settings {
+    strategy = "BLUE_GREEN"
+    blue_green_settings {
+      standard_rollout_policy {
+        batch_node_count    = 1
+        batch_soak_duration = "%s"
+      }
+      node_pool_soak_duration = "%s"
+    }
+  }
+  upgrade_rollout_action = "COMPLETE"
+}
+`, clusterName, nodePoolName, batchSoakDuration, nodePoolSoakDuration)
+}
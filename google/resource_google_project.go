@@ -118,50 +118,11 @@ func resourceGoogleProjectCreate(d *schema.ResourceData, meta interface{}) error
 		return fmt.Errorf("failed pre-requisites: %v", err)
 	}
 
-	var pid string
-	pid = d.Get("project_id").(string)
-
-	log.Printf("[DEBUG]: Creating new project %q", pid)
-	project := &cloudresourcemanager.Project{
-		ProjectId: pid,
-		Name:      d.Get("name").(string),
-	}
-
-	if err = getParentResourceId(d, project); err != nil {
-		return err
-	}
-
-	if _, ok := d.GetOk("labels"); ok {
-		project.Labels = expandLabels(d)
-	}
-
-	var op *cloudresourcemanager.Operation
-	err = retryTimeDuration(func() (reqErr error) {
-		op, reqErr = config.NewResourceManagerClient(userAgent).Projects.Create(project).Do()
-		return reqErr
-	}, d.Timeout(schema.TimeoutCreate))
-	if err != nil {
-		return fmt.Errorf("error creating project %s (%s): %s. "+
-			"If you received a 403 error, make sure you have the"+
-			" `roles/resourcemanager.projectCreator` permission",
-			project.ProjectId, project.Name, err)
-	}
-
-	d.SetId(fmt.Sprintf("projects/%s", pid))
-
-	// Wait for the operation to complete
-	opAsMap, err := ConvertToMap(op)
+	project, err := createGoogleProject(d, config, userAgent)
 	if err != nil {
 		return err
 	}
 
-	waitErr := resourceManagerOperationWaitTime(config, opAsMap, "creating folder", userAgent, d.Timeout(schema.TimeoutCreate))
-	if waitErr != nil {
-		// The resource wasn't actually created
-		d.SetId("")
-		return waitErr
-	}
-
 	// Set the billing account
 	if _, ok := d.GetOk("billing_account"); ok {
 		err = updateProjectBillingAccount(d, config, userAgent)
@@ -244,6 +205,56 @@ func resourceGoogleProjectCheckPreRequisites(config *Config, d *schema.ResourceD
 	return nil
 }
 
+// createGoogleProject creates the project described by the project_id, name,
+// org_id, folder_id and labels fields of d, and sets the id of d once it
+// exists.
+func createGoogleProject(d *schema.ResourceData, config *Config, userAgent string) (*cloudresourcemanager.Project, error) {
+	pid := d.Get("project_id").(string)
+
+	log.Printf("[DEBUG]: Creating new project %q", pid)
+	project := &cloudresourcemanager.Project{
+		ProjectId: pid,
+		Name:      d.Get("name").(string),
+	}
+
+	if err := getParentResourceId(d, project); err != nil {
+		return nil, err
+	}
+
+	if _, ok := d.GetOk("labels"); ok {
+		project.Labels = expandLabels(d)
+	}
+
+	var op *cloudresourcemanager.Operation
+	err := retryTimeDuration(func() (reqErr error) {
+		op, reqErr = config.NewResourceManagerClient(userAgent).Projects.Create(project).Do()
+		return reqErr
+	}, d.Timeout(schema.TimeoutCreate))
+	if err != nil {
+		return nil, fmt.Errorf("error creating project %s (%s): %s. "+
+			"If you received a 403 error, make sure you have the"+
+			" `roles/resourcemanager.projectCreator` permission",
+			project.ProjectId, project.Name, err)
+	}
+
+	d.SetId(fmt.Sprintf("projects/%s", pid))
+
+	// Wait for the operation to complete
+	opAsMap, err := ConvertToMap(op)
+	if err != nil {
+		return nil, err
+	}
+
+	waitErr := resourceManagerOperationWaitTime(config, opAsMap, "creating folder", userAgent, d.Timeout(schema.TimeoutCreate))
+	if waitErr != nil {
+		// The resource wasn't actually created
+		d.SetId("")
+		return nil, waitErr
+	}
+
+	return project, nil
+}
+
 func resourceGoogleProjectRead(d *schema.ResourceData, meta interface{}) error {
 	config := meta.(*Config)
 	userAgent, err := generateUserAgentString(d, config.userAgent)
@@ -372,6 +383,15 @@ func resourceGoogleProjectUpdate(d *schema.ResourceData, meta interface{}) error
 	if err != nil {
 		return err
 	}
+	if err := updateGoogleProject(d, config, userAgent); err != nil {
+		return err
+	}
+	return resourceGoogleProjectRead(d, meta)
+}
+
+// updateGoogleProject applies changes to the name, org_id, folder_id,
+// billing_account and labels fields of d to the project.
+func updateGoogleProject(d *schema.ResourceData, config *Config, userAgent string) error {
 	parts := strings.Split(d.Id(), "/")
 	pid := parts[len(parts)-1]
 	project_name := d.Get("name").(string)
@@ -429,7 +449,7 @@ func resourceGoogleProjectUpdate(d *schema.ResourceData, meta interface{}) error
 	}
 
 	d.Partial(false)
-	return resourceGoogleProjectRead(d, meta)
+	return nil
 }
 
 func updateProject(config *Config, d *schema.ResourceData, projectName, userAgent string, desiredProject *cloudresourcemanager.Project) (*cloudresourcemanager.Project, error) {
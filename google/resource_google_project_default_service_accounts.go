@@ -69,7 +69,13 @@ func resourceGoogleProjectDefaultServiceAccountsDoAction(d *schema.ResourceData,
 	if err != nil {
 		return err
 	}
-	restorePolicy := d.Get("restore_policy").(string)
+	return doDefaultServiceAccountAction(config, userAgent, action, d.Get("restore_policy").(string), uniqueID, email, project)
+}
+
+// doDefaultServiceAccountAction performs action on a default service account.
+// restorePolicy decides whether failing to revert a previous action with
+// UNDELETE or ENABLE is an error.
+func doDefaultServiceAccountAction(config *Config, userAgent, action, restorePolicy, uniqueID, email, project string) error {
 	serviceAccountSelfLink := fmt.Sprintf("projects/%s/serviceAccounts/%s", project, uniqueID)
 	switch action {
 	case "DELETE":
@@ -144,7 +150,7 @@ func resourceGoogleProjectDefaultServiceAccountsCreate(d *schema.ResourceData, m
 	pid := d.Get("project").(string)
 	action := d.Get("action").(string)
 
-	serviceAccounts, err := listServiceAccounts(config, d, userAgent)
+	serviceAccounts, err := listServiceAccounts(config, pid, userAgent)
 	if err != nil {
 		return fmt.Errorf("error listing service accounts on project %s: %v", pid, err)
 	}
@@ -169,8 +175,7 @@ func resourceGoogleProjectDefaultServiceAccountsCreate(d *schema.ResourceData, m
 	return nil
 }
 
-func listServiceAccounts(config *Config, d *schema.ResourceData, userAgent string) ([]*iam.ServiceAccount, error) {
-	pid := d.Get("project").(string)
+func listServiceAccounts(config *Config, pid, userAgent string) ([]*iam.ServiceAccount, error) {
 	response, err := config.NewIamClient(userAgent).Projects.ServiceAccounts.List(prefixedProject(pid)).Do()
 	if err != nil {
 		return nil, fmt.Errorf("failed to list service accounts on project %q: %v", pid, err)
@@ -186,15 +191,7 @@ func resourceGoogleProjectDefaultServiceAccountsDelete(d *schema.ResourceData, m
 
 	pid := d.Get("project").(string)
 	for saUniqueID, saEmail := range d.Get("service_accounts").(map[string]interface{}) {
-		origAction := d.Get("action").(string)
-		newAction := ""
-		// We agreed to not revert the DEPRIVILEGE because Morgante said it is not required.
-		// It may be an enhancement. https://github.com/hashicorp/terraform-provider-google/issues/4135#issuecomment-709480278
-		if origAction == "DISABLE" {
-			newAction = "ENABLE"
-		} else if origAction == "DELETE" {
-			newAction = "UNDELETE"
-		}
+		newAction := revertDefaultServiceAccountAction(d.Get("action").(string))
 		if newAction != "" {
 			err := resourceGoogleProjectDefaultServiceAccountsDoAction(d, meta, newAction, saUniqueID, saEmail.(string), pid)
 			if err != nil {
@@ -218,3 +215,17 @@ func isDefaultServiceAccount(displayName string) bool {
 
 	return false
 }
+
+// revertDefaultServiceAccountAction returns the action undoing action, or ""
+// when it isn't reverted.
+func revertDefaultServiceAccountAction(action string) string {
+	// We agreed to not revert the DEPRIVILEGE because Morgante said it is not required.
+	// It may be an enhancement. https://github.com/hashicorp/terraform-provider-google/issues/4135#issuecomment-709480278
+	switch action {
+	case "DISABLE":
+		return "ENABLE"
+	case "DELETE":
+		return "UNDELETE"
+	}
+	return ""
+}
@@ -0,0 +1,371 @@
+package google
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/errwrap"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"google.golang.org/api/googleapi"
+)
+
+// resourceGoogleProjectFactory returns a *schema.Resource that creates a
+// project the way google_project, google_project_services and
+// google_project_default_service_accounts would together: it attaches billing,
+// enables APIs in a single batch, removes the default network and applies an
+// action to the default service accounts, all in one apply.
+func resourceGoogleProjectFactory() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceGoogleProjectFactoryCreate,
+		Read:   resourceGoogleProjectFactoryRead,
+		Update: resourceGoogleProjectFactoryUpdate,
+		Delete: resourceGoogleProjectFactoryDelete,
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(20 * time.Minute),
+			Update: schema.DefaultTimeout(20 * time.Minute),
+			Read:   schema.DefaultTimeout(10 * time.Minute),
+			Delete: schema.DefaultTimeout(20 * time.Minute),
+		},
+
+		Schema: map[string]*schema.Schema{
+			"project_id": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validateProjectID(),
+				Description:  `The project ID. Changing this forces a new project to be created.`,
+			},
+			"name": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validateProjectName(),
+				Description:  `The display name of the project.`,
+			},
+			"org_id": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"folder_id"},
+				Description:   `The numeric ID of the organization this project belongs to. Only one of org_id or folder_id may be specified. Changing this forces the project to be migrated to the newly specified organization.`,
+			},
+			"folder_id": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				StateFunc:     parseFolderId,
+				ConflictsWith: []string{"org_id"},
+				Description:   `The numeric ID of the folder this project should be created under. Only one of org_id or folder_id may be specified. Changing this forces the project to be migrated to the newly specified folder.`,
+			},
+			"billing_account": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: `The alphanumeric ID of the billing account this project belongs to.`,
+			},
+			"labels": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: `A set of key/value label pairs to assign to the project.`,
+			},
+			"activate_apis": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validateProjectServiceService,
+				},
+				Set:         schema.HashString,
+				Description: `The services to enable in the project, in a single batch.`,
+			},
+			"disable_dependent_services": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Description: `If true, services that depend on a service removed from activate_apis are disabled along with it.`,
+			},
+			"auto_create_network": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: `Keep the 'default' network created with the project. Defaults to false, which deletes it along with its firewall rules once the Compute Engine API is enabled. Only applied when the project is created.`,
+			},
+			"default_service_account": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "DISABLE",
+				ValidateFunc: validation.StringInSlice([]string{"KEEP", "DEPRIVILEGE", "DISABLE", "DELETE"}, false),
+				Description: `The action to perform on the default service accounts of the project: KEEP, DEPRIVILEGE, DISABLE or DELETE.
+Changing it from DISABLE or DELETE reverts the previous action first. DEPRIVILEGE is never reverted.`,
+			},
+			"skip_delete": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Description: `If true, the Terraform resource can be deleted without deleting the Project via the Google API.`,
+			},
+			"number": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: `The numeric identifier of the project.`,
+			},
+			"enabled_dependencies": {
+				Type:        schema.TypeSet,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Set:         schema.HashString,
+				Description: `Services that were enabled as dependencies of activate_apis, or to delete the default network, and are still enabled.`,
+			},
+			"default_service_accounts": {
+				Type:        schema.TypeMap,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: `The default service accounts default_service_account was applied to, keyed by unique ID.`,
+			},
+		},
+		UseJSONNumber: true,
+	}
+}
+
+func resourceGoogleProjectFactoryCreate(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*Config)
+	userAgent, err := generateUserAgentString(d, config.userAgent)
+	if err != nil {
+		return err
+	}
+
+	if err = resourceGoogleProjectCheckPreRequisites(config, d, userAgent); err != nil {
+		return fmt.Errorf("failed pre-requisites: %v", err)
+	}
+
+	if _, err := createGoogleProject(d, config, userAgent); err != nil {
+		return err
+	}
+	pid := d.Get("project_id").(string)
+
+	if _, ok := d.GetOk("billing_account"); ok {
+		if err := updateProjectBillingAccount(d, config, userAgent); err != nil {
+			return err
+		}
+		// Let the billing account settle before enabling services that need it.
+		time.Sleep(10 * time.Second)
+	}
+
+	// Deleting the default network needs the Compute Engine API, which is
+	// enabled in the same batch as the configured services rather than in a
+	// separate request racing with them.
+	services := convertStringSet(d.Get("activate_apis").(*schema.Set))
+	implicit := projectFactoryImplicitServices(services, d.Get("auto_create_network").(bool))
+	if toEnable := append(services, implicit...); len(toEnable) > 0 {
+		dependencies, err := enableProjectServicesTrackingDependencies(toEnable, pid, d, config, d.Timeout(schema.TimeoutCreate))
+		if err != nil {
+			return err
+		}
+		if err := d.Set("enabled_dependencies", append(dependencies, implicit...)); err != nil {
+			return fmt.Errorf("Error setting enabled_dependencies: %s", err)
+		}
+	}
+
+	if !d.Get("auto_create_network").(bool) {
+		if err := forceDeleteComputeNetwork(d, config, pid, "default"); err != nil {
+			if isGoogleApiErrorWithCode(err, 404) {
+				log.Printf("[DEBUG] Default network not found for project %q, no need to delete it", pid)
+			} else {
+				return errwrap.Wrapf(fmt.Sprintf("Error deleting default network in project %s: {{err}}", pid), err)
+			}
+		}
+	}
+
+	// The accounts acted upon are recorded even on failure, so they are
+	// reverted when the action changes.
+	accounts, actionErr := applyProjectFactoryDefaultServiceAccountsAction(d, config, userAgent, pid, d.Get("default_service_account").(string), nil)
+	if err := d.Set("default_service_accounts", accounts); err != nil {
+		return fmt.Errorf("Error setting default_service_accounts: %s", err)
+	}
+	if actionErr != nil {
+		return actionErr
+	}
+
+	return resourceGoogleProjectFactoryRead(d, meta)
+}
+
+func resourceGoogleProjectFactoryRead(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*Config)
+
+	if err := resourceGoogleProjectRead(d, meta); err != nil {
+		return err
+	}
+	if d.Id() == "" {
+		return nil
+	}
+	pid := d.Get("project_id").(string)
+
+	servicesRaw, err := BatchRequestReadServices(pid, d, config)
+	if err != nil {
+		return handleNotFoundError(err, d, fmt.Sprintf("Project Services %s", pid))
+	}
+	enabled := servicesRaw.(map[string]struct{})
+
+	// Only the services owned by this resource are kept in state, so services
+	// that were disabled out of band show up as a diff.
+	var services []string
+	for _, s := range convertStringSet(d.Get("activate_apis").(*schema.Set)) {
+		if _, ok := enabled[s]; ok {
+			services = append(services, s)
+		}
+	}
+	var dependencies []string
+	for _, s := range convertStringSet(d.Get("enabled_dependencies").(*schema.Set)) {
+		if _, ok := enabled[s]; ok {
+			dependencies = append(dependencies, s)
+		}
+	}
+
+	config.projectServicesInConfig.register(pid, d.Id(), services)
+
+	if err := d.Set("activate_apis", services); err != nil {
+		return fmt.Errorf("Error setting activate_apis: %s", err)
+	}
+	if err := d.Set("enabled_dependencies", dependencies); err != nil {
+		return fmt.Errorf("Error setting enabled_dependencies: %s", err)
+	}
+	return nil
+}
+
+func resourceGoogleProjectFactoryUpdate(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*Config)
+	userAgent, err := generateUserAgentString(d, config.userAgent)
+	if err != nil {
+		return err
+	}
+	pid := d.Get("project_id").(string)
+
+	if err := updateGoogleProject(d, config, userAgent); err != nil {
+		return err
+	}
+
+	if d.HasChange("activate_apis") {
+		o, n := d.GetChange("activate_apis")
+		added := convertStringSet(n.(*schema.Set).Difference(o.(*schema.Set)))
+		removed := convertStringSet(o.(*schema.Set).Difference(n.(*schema.Set)))
+
+		if len(removed) > 0 {
+			if err := disableProjectServices(removed, pid, d, config, d.Get("disable_dependent_services").(bool)); err != nil {
+				return err
+			}
+		}
+
+		if len(added) > 0 {
+			dependencies, err := enableProjectServicesTrackingDependencies(added, pid, d, config, d.Timeout(schema.TimeoutUpdate))
+			if err != nil {
+				return err
+			}
+			dependencies = append(dependencies, convertStringSet(d.Get("enabled_dependencies").(*schema.Set))...)
+			if err := d.Set("enabled_dependencies", dependencies); err != nil {
+				return fmt.Errorf("Error setting enabled_dependencies: %s", err)
+			}
+		}
+	}
+
+	accounts := d.Get("default_service_accounts").(map[string]interface{})
+	if d.HasChange("default_service_account") {
+		o, _ := d.GetChange("default_service_account")
+		if revert := revertDefaultServiceAccountAction(o.(string)); revert != "" {
+			for uniqueID, email := range accounts {
+				if err := doDefaultServiceAccountAction(config, userAgent, revert, "REVERT", uniqueID, email.(string), pid); err != nil {
+					return fmt.Errorf("error doing action %s on Service Account %s: %v", revert, email, err)
+				}
+			}
+		}
+		accounts = nil
+	}
+	// Enabling APIs may create default service accounts, which get the
+	// action too.
+	if d.HasChanges("default_service_account", "activate_apis") {
+		accounts, actionErr := applyProjectFactoryDefaultServiceAccountsAction(d, config, userAgent, pid, d.Get("default_service_account").(string), accounts)
+		if err := d.Set("default_service_accounts", accounts); err != nil {
+			return fmt.Errorf("Error setting default_service_accounts: %s", err)
+		}
+		if actionErr != nil {
+			return actionErr
+		}
+	}
+
+	return resourceGoogleProjectFactoryRead(d, meta)
+}
+
+func resourceGoogleProjectFactoryDelete(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*Config)
+	config.projectServicesInConfig.unregister(d.Get("project_id").(string), d.Id())
+	return resourceGoogleProjectDelete(d, meta)
+}
+
+// projectFactoryImplicitServices returns the services enabled on top of
+// services for the factory itself: deleting the default network needs the
+// Compute Engine API.
+func projectFactoryImplicitServices(services []string, autoCreateNetwork bool) []string {
+	if autoCreateNetwork || stringInSlice(services, "compute.googleapis.com") {
+		return nil
+	}
+	return []string{"compute.googleapis.com"}
+}
+
+// applyProjectFactoryDefaultServiceAccountsAction performs action on the
+// default service accounts of the project that aren't in done yet, and
+// returns every account it was performed on.
+func applyProjectFactoryDefaultServiceAccountsAction(d *schema.ResourceData, config *Config, userAgent, pid, action string, done map[string]interface{}) (map[string]interface{}, error) {
+	accounts := make(map[string]interface{})
+	for uniqueID, email := range done {
+		accounts[uniqueID] = email
+	}
+	if action == "KEEP" {
+		return accounts, nil
+	}
+
+	// The Compute Engine default service account is created asynchronously
+	// once the API is enabled.
+	_, computeEnabled := golangSetFromStringSlice(append(
+		convertStringSet(d.Get("activate_apis").(*schema.Set)),
+		convertStringSet(d.Get("enabled_dependencies").(*schema.Set))...,
+	))["compute.googleapis.com"]
+
+	err := retryTimeDuration(func() error {
+		serviceAccounts, err := listServiceAccounts(config, pid, userAgent)
+		if err != nil {
+			return err
+		}
+
+		for _, sa := range serviceAccounts {
+			if !isDefaultServiceAccount(sa.DisplayName) {
+				continue
+			}
+			if _, ok := accounts[sa.UniqueId]; ok {
+				continue
+			}
+			if err := doDefaultServiceAccountAction(config, userAgent, action, "REVERT", sa.UniqueId, sa.Email, pid); err != nil {
+				return fmt.Errorf("error doing action %s on Service Account %s: %v", action, sa.Email, err)
+			}
+			accounts[sa.UniqueId] = sa.Email
+		}
+
+		if computeEnabled && !hasComputeDefaultServiceAccount(accounts) {
+			// Spoof a googleapi Error so retryTime will try again
+			return &googleapi.Error{
+				Code:    503,
+				Message: fmt.Sprintf("The Compute Engine default service account of project %s isn't created yet. This isn't a real API error, this is just eventual consistency.", pid),
+			}
+		}
+		return nil
+	}, d.Timeout(schema.TimeoutCreate))
+	return accounts, err
+}
+
+// hasComputeDefaultServiceAccount reports whether accounts, emails keyed by
+// unique ID, holds the Compute Engine default service account.
+func hasComputeDefaultServiceAccount(accounts map[string]interface{}) bool {
+	for _, email := range accounts {
+		if strings.HasSuffix(email.(string), "-compute@developer.gserviceaccount.com") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,129 @@
+package google
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestProjectFactoryImplicitServices(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		Services          []string
+		AutoCreateNetwork bool
+		Expected          []string
+	}{
+		"default network kept": {
+			Services:          []string{"bigquery.googleapis.com"},
+			AutoCreateNetwork: true,
+		},
+		"default network deleted": {
+			Services: []string{"bigquery.googleapis.com"},
+			Expected: []string{"compute.googleapis.com"},
+		},
+		"compute already configured": {
+			Services: []string{"compute.googleapis.com"},
+		},
+	}
+
+	for tn, tc := range cases {
+		if services := projectFactoryImplicitServices(tc.Services, tc.AutoCreateNetwork); !reflect.DeepEqual(services, tc.Expected) {
+			t.Errorf("%s: expected %v, got %v", tn, tc.Expected, services)
+		}
+	}
+}
+
+func TestHasComputeDefaultServiceAccount(t *testing.T) {
+	t.Parallel()
+
+	if hasComputeDefaultServiceAccount(map[string]interface{}{"1": "my-project@appspot.gserviceaccount.com"}) {
+		t.Errorf("expected the App Engine default service account not to be the Compute Engine one")
+	}
+	if !hasComputeDefaultServiceAccount(map[string]interface{}{"2": "123456789-compute@developer.gserviceaccount.com"}) {
+		t.Errorf("expected the Compute Engine default service account to be found")
+	}
+}
+
+func TestRevertDefaultServiceAccountAction(t *testing.T) {
+	t.Parallel()
+
+	for action, expected := range map[string]string{
+		"DISABLE":     "ENABLE",
+		"DELETE":      "UNDELETE",
+		"DEPRIVILEGE": "",
+		"KEEP":        "",
+	} {
+		if revert := revertDefaultServiceAccountAction(action); revert != expected {
+			t.Errorf("%s: expected %q, got %q", action, expected, revert)
+		}
+	}
+}
+
+func TestAccProjectFactory_basic(t *testing.T) {
+	t.Parallel()
+
+	org := getTestOrgFromEnv(t)
+	billingId := getTestBillingAccountFromEnv(t)
+	pid := fmt.Sprintf("%s-%d", testPrefix, randInt(t))
+	resourceName := "google_project_factory.acceptance"
+
+	vcrTest(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProjectFactory(pid, org, billingId, "DISABLE", `"iam.googleapis.com"`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "id", "projects/"+pid),
+					resource.TestCheckResourceAttr(resourceName, "billing_account", billingId),
+					resource.TestCheckResourceAttr(resourceName, "activate_apis.#", "1"),
+					resource.TestCheckTypeSetElemAttr(resourceName, "enabled_dependencies.*", "compute.googleapis.com"),
+					resource.TestCheckResourceAttr(resourceName, "default_service_accounts.%", "1"),
+					testAccCheckProjectFactoryNoDefaultNetwork(t, pid),
+					testAccCheckGoogleProjectDefaultServiceAccountsChanges(t, pid, "DISABLE"),
+				),
+			},
+			{
+				Config: testAccProjectFactory(pid, org, billingId, "DEPRIVILEGE", `"iam.googleapis.com", "pubsub.googleapis.com"`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "activate_apis.#", "2"),
+					resource.TestCheckResourceAttr(resourceName, "default_service_accounts.%", "1"),
+					testAccCheckGoogleProjectDefaultServiceAccountsRevert(t, pid, "DISABLE"),
+					testAccCheckGoogleProjectDefaultServiceAccountsChanges(t, pid, "DEPRIVILEGE"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckProjectFactoryNoDefaultNetwork(t *testing.T, pid string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		config := googleProviderConfig(t)
+		_, err := config.NewComputeClient(config.userAgent).Networks.Get(pid, "default").Do()
+		if err == nil {
+			return fmt.Errorf("expected the default network of project %q to be deleted", pid)
+		}
+		if !isGoogleApiErrorWithCode(err, 404) {
+			return fmt.Errorf("Got non-404 error while reading the default network of project %q: %v", pid, err)
+		}
+		return nil
+	}
+}
+
+func testAccProjectFactory(pid, org, billing, action, apis string) string {
+	return fmt.Sprintf(`
+resource "google_project_factory" "acceptance" {
+  project_id      = "%s"
+  name            = "%s"
+  org_id          = "%s"
+  billing_account = "%s"
+
+  activate_apis           = [%s]
+  default_service_account = "%s"
+}
+`, pid, pname, org, billing, apis, action)
+}
@@ -214,6 +214,7 @@ func resourceGoogleProjectServiceRead(d *schema.ResourceData, meta interface{})
 
 	srv := d.Get("service").(string)
 	if _, ok := servicesList[srv]; ok {
+		config.projectServicesInConfig.register(project, d.Id(), []string{srv})
 		if err := d.Set("project", project); err != nil {
 			return fmt.Errorf("Error setting project: %s", err)
 		}
@@ -231,6 +232,8 @@ func resourceGoogleProjectServiceRead(d *schema.ResourceData, meta interface{})
 func resourceGoogleProjectServiceDelete(d *schema.ResourceData, meta interface{}) error {
 	config := meta.(*Config)
 
+	config.projectServicesInConfig.unregister(GetResourceNameFromSelfLink(d.Get("project").(string)), d.Id())
+
 	if disable := d.Get("disable_on_destroy"); !(disable.(bool)) {
 		log.Printf("[WARN] Project service %q disable_on_destroy is false, skip disabling service", d.Id())
 		d.SetId("")
@@ -0,0 +1,517 @@
+package google
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"google.golang.org/api/googleapi"
+)
+
+// projectServiceRegistry records which services each google_project_service,
+// google_project_services and google_project_factory resource known to a
+// provider instance keeps enabled, so google_project_services can tell which
+// services a cascading disable would take down are managed in config.
+type projectServiceRegistry struct {
+	sync.Mutex
+	// project -> owning resource id -> services
+	owners map[string]map[string][]string
+}
+
+func newProjectServiceRegistry() *projectServiceRegistry {
+	return &projectServiceRegistry{
+		owners: make(map[string]map[string][]string),
+	}
+}
+
+func (r *projectServiceRegistry) register(project, owner string, services []string) {
+	if r == nil {
+		return
+	}
+	r.Lock()
+	defer r.Unlock()
+	if r.owners[project] == nil {
+		r.owners[project] = make(map[string][]string)
+	}
+	r.owners[project][owner] = services
+}
+
+func (r *projectServiceRegistry) unregister(project, owner string) {
+	if r == nil {
+		return
+	}
+	r.Lock()
+	defer r.Unlock()
+	delete(r.owners[project], owner)
+}
+
+// servicesExcept returns the sorted services registered for project by any
+// owner other than the given one.
+func (r *projectServiceRegistry) servicesExcept(project, owner string) []string {
+	if r == nil {
+		return nil
+	}
+	r.Lock()
+	defer r.Unlock()
+	set := make(map[string]struct{})
+	for o, services := range r.owners[project] {
+		if o == owner {
+			continue
+		}
+		for _, s := range services {
+			set[s] = struct{}{}
+		}
+	}
+	return sortedServiceSet(set)
+}
+
+func sortedServiceSet(set map[string]struct{}) []string {
+	services := make([]string, 0, len(set))
+	for s := range set {
+		services = append(services, s)
+	}
+	sort.Strings(services)
+	return services
+}
+
+// projectServicesDifference returns the sorted services in a that aren't in b.
+func projectServicesDifference(a []string, b map[string]struct{}) []string {
+	diff := make(map[string]struct{})
+	for _, s := range a {
+		if _, ok := b[s]; !ok {
+			diff[s] = struct{}{}
+		}
+	}
+	return sortedServiceSet(diff)
+}
+
+func resourceGoogleProjectServices() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceGoogleProjectServicesCreate,
+		Read:   resourceGoogleProjectServicesRead,
+		Update: resourceGoogleProjectServicesUpdate,
+		Delete: resourceGoogleProjectServicesDelete,
+
+		Importer: &schema.ResourceImporter{
+			State: resourceGoogleProjectServicesImport,
+		},
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(20 * time.Minute),
+			Update: schema.DefaultTimeout(20 * time.Minute),
+			Read:   schema.DefaultTimeout(10 * time.Minute),
+			Delete: schema.DefaultTimeout(20 * time.Minute),
+		},
+
+		CustomizeDiff: resourceGoogleProjectServicesCustomizeDiff,
+
+		Schema: map[string]*schema.Schema{
+			"services": {
+				Type:     schema.TypeSet,
+				Required: true,
+				MinItems: 1,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validateProjectServiceService,
+				},
+				Set:         schema.HashString,
+				Description: `The services to enable.`,
+			},
+			"project": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Computed:         true,
+				ForceNew:         true,
+				DiffSuppressFunc: compareResourceNames,
+			},
+
+			"disable_dependent_services": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Description: `If true, services that depend on a removed service are disabled along with it.`,
+			},
+
+			"acknowledge_cascading_disable": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Description: `If true, removing services with disable_dependent_services set may disable the enabled services that depend on them.`,
+			},
+
+			"disable_on_destroy": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: `If true, disable the services when the resource is destroyed.`,
+			},
+
+			"enabled_dependencies": {
+				Type:        schema.TypeSet,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Set:         schema.HashString,
+				Description: `Services that were enabled as dependencies of the configured services and are still enabled.`,
+			},
+		},
+		UseJSONNumber: true,
+	}
+}
+
+func resourceGoogleProjectServicesImport(d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	if d.Id() == "" || strings.Contains(d.Id(), "/") {
+		return nil, fmt.Errorf("Invalid google_project_services id format for import, expecting `{project}`, found %s", d.Id())
+	}
+	if err := d.Set("project", d.Id()); err != nil {
+		return nil, fmt.Errorf("Error setting project: %s", err)
+	}
+	if err := d.Set("disable_on_destroy", true); err != nil {
+		return nil, fmt.Errorf("Error setting disable_on_destroy: %s", err)
+	}
+	return []*schema.ResourceData{d}, nil
+}
+
+// resourceGoogleProjectServicesCustomizeDiff fails the plan when services
+// removed with disable_dependent_services set would take down services that
+// are enabled in config and depend on them, unless
+// acknowledge_cascading_disable is set. Other enabled dependents are disabled
+// without failing the plan. When the dependencies can't be read, the plan goes
+// ahead and Service Usage disables what it has to.
+//
+// Services enabled in config are the ones in services and the ones that other
+// google_project_service, google_project_services and google_project_factory
+// resources registered when they were created or read by this provider
+// instance. Resources that haven't been read yet, such as ones planned for
+// creation in the same run, aren't considered.
+func resourceGoogleProjectServicesCustomizeDiff(_ context.Context, diff *schema.ResourceDiff, meta interface{}) error {
+	if diff.Id() == "" || !diff.HasChange("services") || !diff.Get("disable_dependent_services").(bool) || diff.Get("acknowledge_cascading_disable").(bool) {
+		return nil
+	}
+
+	o, n := diff.GetChange("services")
+	removed := convertStringSet(o.(*schema.Set).Difference(n.(*schema.Set)))
+	if len(removed) == 0 {
+		return nil
+	}
+
+	config := meta.(*Config)
+	project := diff.Id()
+	enabled, err := listCurrentlyEnabledServices(project, project, config.userAgent, config, DefaultRequestTimeout)
+	if err != nil {
+		log.Printf("[WARN] Couldn't list the enabled services of project %q to find the dependents of %v: %s", project, removed, err)
+		return nil
+	}
+
+	// Only dependents enabled in config can fail the plan, so the dependencies
+	// of the other enabled services aren't read.
+	inConfig := append(convertStringSet(n.(*schema.Set)), config.projectServicesInConfig.servicesExcept(project, project)...)
+	dependencies := make(map[string][]string)
+	for _, s := range projectServicesDifference(inConfig, golangSetFromStringSlice(removed)) {
+		if _, ok := enabled[s]; !ok {
+			continue
+		}
+		deps, err := listProjectServiceDependencies(project, s, config.userAgent, config)
+		if err != nil {
+			log.Printf("[WARN] Couldn't read the dependencies of %s in project %q to find the dependents of %v: %s", s, project, removed, err)
+			return nil
+		}
+		dependencies[s] = deps
+	}
+
+	return projectServicesCascadeError(project, removed, dependencies)
+}
+
+// projectServicesCascadeError returns an error listing the services, by the
+// services they depend on, that disabling the removed services would disable.
+// dependencies holds the dependencies of the enabled services kept in config.
+func projectServicesCascadeError(project string, removed []string, dependencies map[string][]string) error {
+	removedSet := golangSetFromStringSlice(removed)
+	var dependents []string
+	for s, deps := range dependencies {
+		var on []string
+		for _, dep := range deps {
+			if _, ok := removedSet[dep]; ok {
+				on = append(on, dep)
+			}
+		}
+		if len(on) == 0 {
+			continue
+		}
+		sort.Strings(on)
+		dependents = append(dependents, fmt.Sprintf("%s (depends on %s)", s, strings.Join(on, ", ")))
+	}
+	if len(dependents) == 0 {
+		return nil
+	}
+	sort.Strings(dependents)
+	sort.Strings(removed)
+
+	return fmt.Errorf("removing %s from services with disable_dependent_services = true would also disable these services of project %q that are enabled in config: %s. "+
+		"Set acknowledge_cascading_disable = true to disable them", strings.Join(removed, ", "), project, strings.Join(dependents, "; "))
+}
+
+// listProjectServiceDependencies returns the services that service depends
+// on, directly or not, from the dependencies group of the service.
+func listProjectServiceDependencies(project, service, userAgent string, config *Config) ([]string, error) {
+	url := fmt.Sprintf("%sv2beta/projects/%s/services/%s/groups/dependencies/expandedMembers", removeBasePathVersion(config.ServiceUsageBasePath), project, service)
+
+	var dependencies []string
+	params := make(map[string]string)
+	for {
+		u, err := addQueryParams(url, params)
+		if err != nil {
+			return nil, err
+		}
+		res, err := sendRequest(config, "GET", project, u, userAgent, nil)
+		if err != nil {
+			return nil, err
+		}
+		members, _ := res["members"].([]interface{})
+		for _, m := range members {
+			member, _ := m.(map[string]interface{})
+			if name, ok := member["name"].(string); ok {
+				dependencies = append(dependencies, strings.TrimPrefix(name, "services/"))
+			}
+		}
+		token, _ := res["nextPageToken"].(string)
+		if token == "" {
+			return dependencies, nil
+		}
+		params["pageToken"] = token
+	}
+}
+
+func resourceGoogleProjectServicesCreate(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*Config)
+
+	project, err := getProject(d, config)
+	if err != nil {
+		return err
+	}
+	project = GetResourceNameFromSelfLink(project)
+
+	services := convertStringSet(d.Get("services").(*schema.Set))
+	dependencies, err := enableProjectServicesTrackingDependencies(services, project, d, config, d.Timeout(schema.TimeoutCreate))
+	if err != nil {
+		return err
+	}
+
+	d.SetId(project)
+	if err := d.Set("enabled_dependencies", dependencies); err != nil {
+		return fmt.Errorf("Error setting enabled_dependencies: %s", err)
+	}
+	return resourceGoogleProjectServicesRead(d, meta)
+}
+
+func resourceGoogleProjectServicesRead(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*Config)
+	userAgent, err := generateUserAgentString(d, config.userAgent)
+	if err != nil {
+		return err
+	}
+
+	project, err := getProject(d, config)
+	if err != nil {
+		return err
+	}
+	project = GetResourceNameFromSelfLink(project)
+
+	// Verify project for services still exists
+	projectGetCall := config.NewResourceManagerClient(userAgent).Projects.Get(project)
+	if config.UserProjectOverride {
+		billingProject := project
+
+		// err == nil indicates that the billing_project value was found
+		if bp, err := getBillingProject(d, config); err == nil {
+			billingProject = bp
+		}
+		projectGetCall.Header().Add("X-Goog-User-Project", billingProject)
+	}
+	p, err := projectGetCall.Do()
+
+	if err == nil && p.LifecycleState == "DELETE_REQUESTED" {
+		// Construct a 404 error for handleNotFoundError
+		err = &googleapi.Error{
+			Code:    404,
+			Message: "Project deletion was requested",
+		}
+	}
+	if err != nil {
+		return handleNotFoundError(err, d, fmt.Sprintf("Project Services %s", d.Id()))
+	}
+
+	servicesRaw, err := BatchRequestReadServices(project, d, config)
+	if err != nil {
+		return handleNotFoundError(err, d, fmt.Sprintf("Project Services %s", d.Id()))
+	}
+	enabled := servicesRaw.(map[string]struct{})
+
+	// Only the services owned by this resource are kept in state, so services
+	// that were disabled out of band show up as a diff.
+	var services []string
+	if v, ok := d.GetOk("services"); ok {
+		for _, s := range convertStringSet(v.(*schema.Set)) {
+			if _, ok := enabled[s]; ok {
+				services = append(services, s)
+			}
+		}
+	} else {
+		// On import every enabled service is adopted.
+		services = sortedServiceSet(enabled)
+	}
+
+	var dependencies []string
+	if v, ok := d.GetOk("enabled_dependencies"); ok {
+		for _, s := range convertStringSet(v.(*schema.Set)) {
+			if _, ok := enabled[s]; ok {
+				dependencies = append(dependencies, s)
+			}
+		}
+	}
+
+	config.projectServicesInConfig.register(project, project, services)
+
+	if err := d.Set("project", project); err != nil {
+		return fmt.Errorf("Error setting project: %s", err)
+	}
+	if err := d.Set("services", services); err != nil {
+		return fmt.Errorf("Error setting services: %s", err)
+	}
+	if err := d.Set("enabled_dependencies", dependencies); err != nil {
+		return fmt.Errorf("Error setting enabled_dependencies: %s", err)
+	}
+	return nil
+}
+
+func resourceGoogleProjectServicesUpdate(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*Config)
+
+	project, err := getProject(d, config)
+	if err != nil {
+		return err
+	}
+	project = GetResourceNameFromSelfLink(project)
+
+	if d.HasChange("services") {
+		o, n := d.GetChange("services")
+		added := convertStringSet(n.(*schema.Set).Difference(o.(*schema.Set)))
+		removed := convertStringSet(o.(*schema.Set).Difference(n.(*schema.Set)))
+
+		if len(removed) > 0 {
+			if err := disableProjectServices(removed, project, d, config, d.Get("disable_dependent_services").(bool)); err != nil {
+				return err
+			}
+		}
+
+		if len(added) > 0 {
+			dependencies, err := enableProjectServicesTrackingDependencies(added, project, d, config, d.Timeout(schema.TimeoutUpdate))
+			if err != nil {
+				return err
+			}
+			dependencies = append(dependencies, convertStringSet(d.Get("enabled_dependencies").(*schema.Set))...)
+			if err := d.Set("enabled_dependencies", dependencies); err != nil {
+				return fmt.Errorf("Error setting enabled_dependencies: %s", err)
+			}
+		}
+	}
+
+	return resourceGoogleProjectServicesRead(d, meta)
+}
+
+func resourceGoogleProjectServicesDelete(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*Config)
+
+	project, err := getProject(d, config)
+	if err != nil {
+		return err
+	}
+	project = GetResourceNameFromSelfLink(project)
+	config.projectServicesInConfig.unregister(project, d.Id())
+
+	if disable := d.Get("disable_on_destroy"); !(disable.(bool)) {
+		log.Printf("[WARN] Project services %q disable_on_destroy is false, skip disabling services", d.Id())
+		d.SetId("")
+		return nil
+	}
+
+	services := convertStringSet(d.Get("services").(*schema.Set))
+	if err := disableProjectServices(services, project, d, config, d.Get("disable_dependent_services").(bool)); err != nil {
+		return handleNotFoundError(err, d, fmt.Sprintf("Project Services %s", d.Id()))
+	}
+
+	d.SetId("")
+	return nil
+}
+
+// enableProjectServicesTrackingDependencies enables the services that aren't
+// already enabled in a single batch, and returns the services that were
+// enabled as a side effect.
+func enableProjectServicesTrackingDependencies(services []string, project string, d *schema.ResourceData, config *Config, timeout time.Duration) ([]string, error) {
+	userAgent, err := generateUserAgentString(d, config.userAgent)
+	if err != nil {
+		return nil, err
+	}
+
+	billingProject := project
+	// err == nil indicates that the billing_project value was found
+	if bp, err := getBillingProject(d, config); err == nil {
+		billingProject = bp
+	}
+
+	before, err := listCurrentlyEnabledServices(project, billingProject, userAgent, config, timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	toEnable := projectServicesDifference(services, before)
+	if len(toEnable) == 0 {
+		log.Printf("[DEBUG] services %v were already found to be enabled in project %s", services, project)
+		return nil, nil
+	}
+
+	if err := enableServiceUsageProjectServices(toEnable, project, billingProject, userAgent, config, timeout); err != nil {
+		return nil, err
+	}
+
+	after, err := listCurrentlyEnabledServices(project, billingProject, userAgent, config, timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	requested := golangSetFromStringSlice(services)
+	var dependencies []string
+	for _, s := range projectServicesDifference(sortedServiceSet(after), before) {
+		if _, ok := requested[s]; !ok {
+			dependencies = append(dependencies, s)
+		}
+	}
+	log.Printf("[DEBUG] enabling %v in project %s also enabled dependencies %v", toEnable, project, dependencies)
+	return dependencies, nil
+}
+
+// disableProjectServices disables services one at a time, as Service Usage
+// has no batch disable. Without disable_dependent_services a service can only
+// be disabled once the services depending on it are, so failed services are
+// retried for as long as each pass disables at least one service.
+func disableProjectServices(services []string, project string, d *schema.ResourceData, config *Config, disableDependentServices bool) error {
+	remaining := services
+	for len(remaining) > 0 {
+		var failed []string
+		var lastErr error
+		for _, s := range remaining {
+			if err := disableServiceUsageProjectService(s, project, d, config, disableDependentServices); err != nil {
+				log.Printf("[DEBUG] failed to disable service %s in project %s, will retry: %s", s, project, err)
+				failed = append(failed, s)
+				lastErr = err
+			}
+		}
+		if len(failed) == len(remaining) {
+			return lastErr
+		}
+		remaining = failed
+	}
+	return nil
+}
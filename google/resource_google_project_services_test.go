@@ -0,0 +1,123 @@
+package google
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestProjectServicesDifference(t *testing.T) {
+	got := projectServicesDifference(
+		[]string{"pubsub.googleapis.com", "iam.googleapis.com", "compute.googleapis.com"},
+		golangSetFromStringSlice([]string{"iam.googleapis.com"}),
+	)
+	expected := []string{"compute.googleapis.com", "pubsub.googleapis.com"}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+}
+
+func TestProjectServiceRegistry(t *testing.T) {
+	r := newProjectServiceRegistry()
+	r.register("my-project", "my-project", []string{"container.googleapis.com"})
+	r.register("my-project", "my-project/iam.googleapis.com", []string{"iam.googleapis.com"})
+	r.register("my-project", "my-project/compute.googleapis.com", []string{"compute.googleapis.com"})
+	r.register("other-project", "other-project", []string{"pubsub.googleapis.com"})
+
+	expected := []string{"compute.googleapis.com", "iam.googleapis.com"}
+	if got := r.servicesExcept("my-project", "my-project"); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+
+	r.unregister("my-project", "my-project/iam.googleapis.com")
+	expected = []string{"compute.googleapis.com"}
+	if got := r.servicesExcept("my-project", "my-project"); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %v after unregister, got %v", expected, got)
+	}
+
+	if got := r.servicesExcept("unknown-project", ""); len(got) != 0 {
+		t.Errorf("expected no services for unknown project, got %v", got)
+	}
+}
+
+func TestProjectServicesCascadeError(t *testing.T) {
+	removed := []string{"pubsub.googleapis.com", "bigquery.googleapis.com"}
+	dependencies := map[string][]string{
+		"dataflow.googleapis.com":             {"bigquery.googleapis.com", "compute.googleapis.com", "pubsub.googleapis.com"},
+		"container.googleapis.com":            {"compute.googleapis.com"},
+		"cloudresourcemanager.googleapis.com": nil,
+	}
+
+	err := projectServicesCascadeError("my-project", removed, dependencies)
+	expected := `removing bigquery.googleapis.com, pubsub.googleapis.com from services with disable_dependent_services = true would also disable these services of project "my-project" that are enabled in config: ` +
+		`dataflow.googleapis.com (depends on bigquery.googleapis.com, pubsub.googleapis.com). ` +
+		`Set acknowledge_cascading_disable = true to disable them`
+	if err == nil || err.Error() != expected {
+		t.Errorf("expected error %q, got %v", expected, err)
+	}
+
+	if err := projectServicesCascadeError("my-project", []string{"iam.googleapis.com"}, dependencies); err != nil {
+		t.Errorf("expected no error when no service enabled in config depends on the removed ones, got %s", err)
+	}
+}
+
+func TestAccProjectServices_basic(t *testing.T) {
+	t.Parallel()
+
+	org := getTestOrgFromEnv(t)
+	billingId := getTestBillingAccountFromEnv(t)
+	pid := fmt.Sprintf("tf-test-%d", randInt(t))
+	services := []string{"iam.googleapis.com", "pubsub.googleapis.com"}
+	vcrTest(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProjectServices_basic(pid, pname, org, billingId, `"iam.googleapis.com"`),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckProjectService(t, services[:1], pid, true),
+					resource.TestCheckResourceAttr("google_project_services.test", "services.#", "1"),
+				),
+			},
+			{
+				Config: testAccProjectServices_basic(pid, pname, org, billingId, `"iam.googleapis.com", "pubsub.googleapis.com"`),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckProjectService(t, services, pid, true),
+					resource.TestCheckResourceAttr("google_project_services.test", "services.#", "2"),
+				),
+			},
+			{
+				Config: testAccProjectServices_basic(pid, pname, org, billingId, `"iam.googleapis.com"`),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckProjectService(t, services[:1], pid, true),
+					testAccCheckProjectService(t, services[1:], pid, false),
+				),
+			},
+			// Use a separate TestStep rather than a CheckDestroy because we need the project to still exist.
+			{
+				Config: testAccProject_createBilling(pid, pname, org, billingId),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckProjectService(t, services, pid, false),
+				),
+			},
+		},
+	})
+}
+
+func testAccProjectServices_basic(pid, name, org, billing, services string) string {
+	return fmt.Sprintf(`
+resource "google_project" "acceptance" {
+  project_id      = "%s"
+  name            = "%s"
+  org_id          = "%s"
+  billing_account = "%s"
+}
+
+resource "google_project_services" "test" {
+  project  = google_project.acceptance.project_id
+  services = [%s]
+}
+`, pid, name, org, billing, services)
+}
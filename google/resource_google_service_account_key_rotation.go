@@ -0,0 +1,408 @@
+package google
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"google.golang.org/api/iam/v1"
+)
+
+func resourceGoogleServiceAccountKeyRotation() *schema.Resource {
+	return &schema.Resource{
+		Create:        resourceGoogleServiceAccountKeyRotationCreate,
+		Read:          resourceGoogleServiceAccountKeyRotationRead,
+		Update:        resourceGoogleServiceAccountKeyRotationUpdate,
+		Delete:        resourceGoogleServiceAccountKeyRotationDelete,
+		CustomizeDiff: resourceGoogleServiceAccountKeyRotationCustomizeDiff,
+		Schema: map[string]*schema.Schema{
+			// Required
+			"service_account_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: `The ID of the parent service account of the keys. This can be a string in the format {ACCOUNT} or projects/{PROJECT_ID}/serviceAccounts/{ACCOUNT}, where {ACCOUNT} is the email address or unique id of the service account. If the {ACCOUNT} syntax is used, the project will be inferred from the provider's configuration.`,
+			},
+			"rotation_period": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validateDuration(),
+				Description:  `A new key is created when the newest key is older than this duration, such as "720h".`,
+			},
+			// Optional
+			"max_age": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateDuration(),
+				Description:  `Keys older than this duration are deleted, once a newer key is active. It must be longer than rotation_period.`,
+			},
+			"key_count": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      2,
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  `The number of keys to keep. When a key is created, the oldest keys beyond this number are deleted.`,
+			},
+			"key_algorithm": {
+				Type:         schema.TypeString,
+				Default:      "KEY_ALG_RSA_2048",
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice([]string{"KEY_ALG_UNSPECIFIED", "KEY_ALG_RSA_1024", "KEY_ALG_RSA_2048"}, false),
+				Description:  `The algorithm used to generate new keys. KEY_ALG_RSA_2048 is the default algorithm. Valid values are: "KEY_ALG_RSA_1024", "KEY_ALG_RSA_2048".`,
+			},
+			"private_key_type": {
+				Type:         schema.TypeString,
+				Default:      "TYPE_GOOGLE_CREDENTIALS_FILE",
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice([]string{"TYPE_UNSPECIFIED", "TYPE_PKCS12_FILE", "TYPE_GOOGLE_CREDENTIALS_FILE"}, false),
+				Description:  `The output format of the private key of new keys.`,
+			},
+			"public_key_type": {
+				Type:         schema.TypeString,
+				Default:      "TYPE_X509_PEM_FILE",
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice([]string{"TYPE_NONE", "TYPE_X509_PEM_FILE", "TYPE_RAW_PUBLIC_KEY"}, false),
+				Description:  `The output format of the public key.`,
+			},
+			// Computed
+			"keys": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: `The keys managed by this resource, from the oldest to the newest.`,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"valid_after": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"valid_before": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+			"name": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: `The name of the current key, which is the newest key.`,
+			},
+			"public_key": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: `The public key of the current key, base64 encoded.`,
+			},
+			"private_key": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Sensitive:   true,
+				Description: `The private key of the current key in JSON format, base64 encoded.`,
+			},
+			"valid_after": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: `The current key can be used after this timestamp. A timestamp in RFC3339 UTC "Zulu" format, accurate to nanoseconds. Example: "2014-10-02T15:01:23.045123456Z".`,
+			},
+			"valid_before": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: `The current key can be used before this timestamp. A timestamp in RFC3339 UTC "Zulu" format, accurate to nanoseconds. Example: "2014-10-02T15:01:23.045123456Z".`,
+			},
+		},
+		UseJSONNumber: true,
+	}
+}
+
+// resourceGoogleServiceAccountKeyRotationCustomizeDiff plans an update when a
+// key is due: either a new key or the deletion of expired ones. A new key is
+// also planned when the newest key was deleted outside of Terraform, as the
+// private key of the remaining keys can't be read.
+func resourceGoogleServiceAccountKeyRotationCustomizeDiff(_ context.Context, diff *schema.ResourceDiff, meta interface{}) error {
+	rotationPeriod, maxAge, err := serviceAccountKeyRotationDurations(diff.Get("rotation_period").(string), diff.Get("max_age").(string))
+	if err != nil {
+		return err
+	}
+	if diff.Id() == "" {
+		return nil
+	}
+
+	keys := diff.Get("keys").([]interface{})
+	now := time.Now()
+	if serviceAccountKeyRotationNewKeyNeeded(keys, diff.Get("private_key").(string), now, rotationPeriod) {
+		for _, k := range []string{"keys", "name", "public_key", "private_key", "valid_after", "valid_before"} {
+			if err := diff.SetNewComputed(k); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if len(serviceAccountKeyRotationExpired(keys, now, maxAge, diff.Get("key_count").(int))) > 0 {
+		return diff.SetNewComputed("keys")
+	}
+	return nil
+}
+
+func resourceGoogleServiceAccountKeyRotationCreate(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*Config)
+	userAgent, err := generateUserAgentString(d, config.userAgent)
+	if err != nil {
+		return err
+	}
+
+	serviceAccountName, err := serviceAccountFQN(d.Get("service_account_id").(string), d, config)
+	if err != nil {
+		return err
+	}
+
+	key, err := createServiceAccountKeyRotationKey(d, config, userAgent, serviceAccountName)
+	if err != nil {
+		return err
+	}
+	d.SetId(serviceAccountName)
+	if err := d.Set("keys", []interface{}{key}); err != nil {
+		return fmt.Errorf("Error setting keys: %s", err)
+	}
+
+	return resourceGoogleServiceAccountKeyRotationRead(d, meta)
+}
+
+func resourceGoogleServiceAccountKeyRotationRead(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*Config)
+	userAgent, err := generateUserAgentString(d, config.userAgent)
+	if err != nil {
+		return err
+	}
+
+	publicKeyType := d.Get("public_key_type").(string)
+	client := config.NewIamClient(userAgent).Projects.ServiceAccounts.Keys
+
+	// Keep the keys that still exist.
+	stored := d.Get("keys").([]interface{})
+	var keys []interface{}
+	var current *iam.ServiceAccountKey
+	newestGone := false
+	for i, raw := range stored {
+		key := raw.(map[string]interface{})
+		sak, err := client.Get(key["name"].(string)).PublicKeyType(publicKeyType).Do()
+		if err != nil {
+			// This resource also returns 403 when it's not found.
+			if isGoogleApiErrorWithCode(err, 404) || isGoogleApiErrorWithCode(err, 403) {
+				log.Printf("[DEBUG] Service account key %s is gone, removing it from state.", key["name"])
+				newestGone = i == len(stored)-1
+				continue
+			}
+			return err
+		}
+		keys = append(keys, key)
+		current = sak
+	}
+
+	if current == nil {
+		log.Printf("[WARN] Removing %s because all its keys are gone", d.Id())
+		d.SetId("")
+		return nil
+	}
+
+	if newestGone {
+		// The private key of a key is only returned when it's created, so the
+		// remaining keys can't replace it. An empty private_key plans a new key.
+		log.Printf("[WARN] The newest key of %s is gone, a new key will be created", d.Id())
+		if err := d.Set("private_key", ""); err != nil {
+			return fmt.Errorf("Error setting private_key: %s", err)
+		}
+	}
+
+	if err := d.Set("keys", keys); err != nil {
+		return fmt.Errorf("Error setting keys: %s", err)
+	}
+	if err := d.Set("name", current.Name); err != nil {
+		return fmt.Errorf("Error setting name: %s", err)
+	}
+	if err := d.Set("public_key", current.PublicKeyData); err != nil {
+		return fmt.Errorf("Error setting public_key: %s", err)
+	}
+	if err := d.Set("valid_after", current.ValidAfterTime); err != nil {
+		return fmt.Errorf("Error setting valid_after: %s", err)
+	}
+	if err := d.Set("valid_before", current.ValidBeforeTime); err != nil {
+		return fmt.Errorf("Error setting valid_before: %s", err)
+	}
+	return nil
+}
+
+func resourceGoogleServiceAccountKeyRotationUpdate(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*Config)
+	userAgent, err := generateUserAgentString(d, config.userAgent)
+	if err != nil {
+		return err
+	}
+
+	rotationPeriod, maxAge, err := serviceAccountKeyRotationDurations(d.Get("rotation_period").(string), d.Get("max_age").(string))
+	if err != nil {
+		return err
+	}
+
+	keys := d.Get("keys").([]interface{})
+	now := time.Now()
+	// The planned private_key is unknown, the one read before the plan is
+	// empty when the newest key is gone.
+	privateKey, _ := d.GetChange("private_key")
+	if serviceAccountKeyRotationNewKeyNeeded(keys, privateKey.(string), now, rotationPeriod) {
+		// The new key is active once created, so older keys can be deleted.
+		key, err := createServiceAccountKeyRotationKey(d, config, userAgent, d.Id())
+		if err != nil {
+			return err
+		}
+		keys = append(keys, key)
+		if err := d.Set("keys", keys); err != nil {
+			return fmt.Errorf("Error setting keys: %s", err)
+		}
+	}
+
+	expired := serviceAccountKeyRotationExpired(keys, now, maxAge, d.Get("key_count").(int))
+	client := config.NewIamClient(userAgent).Projects.ServiceAccounts.Keys
+	for _, name := range expired {
+		log.Printf("[DEBUG] Deleting expired service account key %s", name)
+		if _, err := client.Delete(name).Do(); err != nil && !isGoogleApiErrorWithCode(err, 404) && !isGoogleApiErrorWithCode(err, 403) {
+			return fmt.Errorf("Error deleting service account key %s: %s", name, err)
+		}
+		keys = serviceAccountKeyRotationRemove(keys, name)
+		if err := d.Set("keys", keys); err != nil {
+			return fmt.Errorf("Error setting keys: %s", err)
+		}
+	}
+
+	return resourceGoogleServiceAccountKeyRotationRead(d, meta)
+}
+
+func resourceGoogleServiceAccountKeyRotationDelete(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*Config)
+	userAgent, err := generateUserAgentString(d, config.userAgent)
+	if err != nil {
+		return err
+	}
+
+	client := config.NewIamClient(userAgent).Projects.ServiceAccounts.Keys
+	for _, raw := range d.Get("keys").([]interface{}) {
+		name := raw.(map[string]interface{})["name"].(string)
+		if _, err := client.Delete(name).Do(); err != nil {
+			// This resource also returns 403 when it's not found.
+			if isGoogleApiErrorWithCode(err, 404) || isGoogleApiErrorWithCode(err, 403) {
+				log.Printf("[DEBUG] Service account key %s is already gone.", name)
+				continue
+			}
+			return fmt.Errorf("Error deleting service account key %s: %s", name, err)
+		}
+	}
+
+	d.SetId("")
+	return nil
+}
+
+// createServiceAccountKeyRotationKey creates a key, stores its private key as
+// the current one and waits for the key to be active.
+func createServiceAccountKeyRotationKey(d *schema.ResourceData, config *Config, userAgent, serviceAccountName string) (map[string]interface{}, error) {
+	rc := &iam.CreateServiceAccountKeyRequest{
+		KeyAlgorithm:   d.Get("key_algorithm").(string),
+		PrivateKeyType: d.Get("private_key_type").(string),
+	}
+	sak, err := config.NewIamClient(userAgent).Projects.ServiceAccounts.Keys.Create(serviceAccountName, rc).Do()
+	if err != nil {
+		return nil, fmt.Errorf("Error creating service account key: %s", err)
+	}
+
+	err = serviceAccountKeyWaitTime(config.NewIamClient(userAgent).Projects.ServiceAccounts.Keys, sak.Name, d.Get("public_key_type").(string), "Creating Service account key", 4*time.Minute)
+	if err != nil {
+		return nil, err
+	}
+
+	// Data only available on create.
+	if err := d.Set("private_key", sak.PrivateKeyData); err != nil {
+		return nil, fmt.Errorf("Error setting private_key: %s", err)
+	}
+	return map[string]interface{}{
+		"name":         sak.Name,
+		"valid_after":  sak.ValidAfterTime,
+		"valid_before": sak.ValidBeforeTime,
+	}, nil
+}
+
+func serviceAccountKeyRotationDurations(rotationPeriod, maxAge string) (time.Duration, time.Duration, error) {
+	rotation, err := time.ParseDuration(rotationPeriod)
+	if err != nil {
+		return 0, 0, err
+	}
+	if maxAge == "" {
+		return rotation, 0, nil
+	}
+	age, err := time.ParseDuration(maxAge)
+	if err != nil {
+		return 0, 0, err
+	}
+	if age <= rotation {
+		return 0, 0, fmt.Errorf("max_age (%s) must be longer than rotation_period (%s)", maxAge, rotationPeriod)
+	}
+	return rotation, age, nil
+}
+
+// serviceAccountKeyRotationDue returns whether the newest of keys, ordered from
+// the oldest to the newest, is older than the rotation period.
+func serviceAccountKeyRotationDue(keys []interface{}, now time.Time, rotationPeriod time.Duration) bool {
+	if len(keys) == 0 {
+		return true
+	}
+	validAfter, err := time.Parse(time.RFC3339, keys[len(keys)-1].(map[string]interface{})["valid_after"].(string))
+	if err != nil {
+		return false
+	}
+	return !now.Before(validAfter.Add(rotationPeriod))
+}
+
+// serviceAccountKeyRotationNewKeyNeeded returns whether a new key has to be
+// created, because a key is due or because the private key of the newest key
+// was lost when it was deleted.
+func serviceAccountKeyRotationNewKeyNeeded(keys []interface{}, privateKey string, now time.Time, rotationPeriod time.Duration) bool {
+	return privateKey == "" || serviceAccountKeyRotationDue(keys, now, rotationPeriod)
+}
+
+// serviceAccountKeyRotationExpired returns the names of the keys to delete:
+// the oldest keys beyond keyCount, and the keys older than maxAge when it's
+// set. The newest key is never expired.
+func serviceAccountKeyRotationExpired(keys []interface{}, now time.Time, maxAge time.Duration, keyCount int) []string {
+	var expired []string
+	for i, raw := range keys {
+		if i == len(keys)-1 {
+			break
+		}
+		key := raw.(map[string]interface{})
+		if i < len(keys)-keyCount {
+			expired = append(expired, key["name"].(string))
+			continue
+		}
+		if maxAge == 0 {
+			continue
+		}
+		validAfter, err := time.Parse(time.RFC3339, key["valid_after"].(string))
+		if err == nil && !now.Before(validAfter.Add(maxAge)) {
+			expired = append(expired, key["name"].(string))
+		}
+	}
+	return expired
+}
+
+func serviceAccountKeyRotationRemove(keys []interface{}, name string) []interface{} {
+	var result []interface{}
+	for _, raw := range keys {
+		if raw.(map[string]interface{})["name"] != name {
+			result = append(result, raw)
+		}
+	}
+	return result
+}
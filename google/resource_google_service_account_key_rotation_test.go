@@ -0,0 +1,145 @@
+package google
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestServiceAccountKeyRotation(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2022, 3, 1, 12, 0, 0, 0, time.UTC)
+	key := func(name string, age time.Duration) interface{} {
+		return map[string]interface{}{
+			"name":         name,
+			"valid_after":  now.Add(-age).Format(time.RFC3339),
+			"valid_before": "9999-12-31T23:59:59Z",
+		}
+	}
+
+	cases := map[string]struct {
+		Keys     []interface{}
+		MaxAge   time.Duration
+		KeyCount int
+		Due      bool
+		Expired  []string
+	}{
+		"no keys": {
+			Keys:     nil,
+			KeyCount: 2,
+			Due:      true,
+		},
+		"newest key is recent": {
+			Keys:     []interface{}{key("a", 40*time.Hour), key("b", time.Hour)},
+			KeyCount: 2,
+			Due:      false,
+		},
+		"newest key is old": {
+			Keys:     []interface{}{key("a", 40*time.Hour), key("b", 25*time.Hour)},
+			KeyCount: 2,
+			Due:      true,
+		},
+		"beyond key count": {
+			Keys:     []interface{}{key("a", 50*time.Hour), key("b", 40*time.Hour), key("c", time.Hour)},
+			KeyCount: 2,
+			Expired:  []string{"a"},
+		},
+		"older than max age": {
+			Keys:     []interface{}{key("a", 50*time.Hour), key("b", 40*time.Hour), key("c", time.Hour)},
+			MaxAge:   45 * time.Hour,
+			KeyCount: 3,
+			Expired:  []string{"a"},
+		},
+		"newest key never expires": {
+			Keys:     []interface{}{key("a", 50*time.Hour)},
+			MaxAge:   45 * time.Hour,
+			KeyCount: 1,
+			Due:      true,
+		},
+	}
+
+	for tn, tc := range cases {
+		if due := serviceAccountKeyRotationDue(tc.Keys, now, 24*time.Hour); due != tc.Due {
+			t.Errorf("%s: expected due to be %t, got %t", tn, tc.Due, due)
+		}
+		if needed := serviceAccountKeyRotationNewKeyNeeded(tc.Keys, "private", now, 24*time.Hour); needed != tc.Due {
+			t.Errorf("%s: expected a new key to be needed when due, got %t", tn, needed)
+		}
+		if !serviceAccountKeyRotationNewKeyNeeded(tc.Keys, "", now, 24*time.Hour) {
+			t.Errorf("%s: expected a new key to be needed without a private key", tn)
+		}
+		if expired := serviceAccountKeyRotationExpired(tc.Keys, now, tc.MaxAge, tc.KeyCount); !reflect.DeepEqual(expired, tc.Expired) {
+			t.Errorf("%s: expected expired keys %v, got %v", tn, tc.Expired, expired)
+		}
+	}
+}
+
+func TestServiceAccountKeyRotationDurations(t *testing.T) {
+	t.Parallel()
+
+	if _, _, err := serviceAccountKeyRotationDurations("720h", "1440h"); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+	if _, _, err := serviceAccountKeyRotationDurations("720h", "720h"); err == nil {
+		t.Errorf("expected an error when max_age isn't longer than rotation_period")
+	}
+}
+
+func TestAccServiceAccountKeyRotation_basic(t *testing.T) {
+	t.Parallel()
+
+	resourceName := "google_service_account_key_rotation.acceptance"
+	accountID := "a" + randString(t, 10)
+	vcrTest(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				// The rotation period elapses right away, so every plan rotates.
+				Config:             testAccServiceAccountKeyRotation(accountID),
+				ExpectNonEmptyPlan: true,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "keys.#", "1"),
+					resource.TestCheckResourceAttrPair(resourceName, "name", resourceName, "keys.0.name"),
+					resource.TestCheckResourceAttrSet(resourceName, "public_key"),
+					resource.TestCheckResourceAttrSet(resourceName, "private_key"),
+				),
+			},
+			{
+				Config:             testAccServiceAccountKeyRotation(accountID),
+				ExpectNonEmptyPlan: true,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "keys.#", "2"),
+					resource.TestCheckResourceAttrPair(resourceName, "name", resourceName, "keys.1.name"),
+				),
+			},
+			{
+				Config:             testAccServiceAccountKeyRotation(accountID),
+				ExpectNonEmptyPlan: true,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "keys.#", "2"),
+					resource.TestCheckResourceAttrPair(resourceName, "name", resourceName, "keys.1.name"),
+				),
+			},
+		},
+	})
+}
+
+func testAccServiceAccountKeyRotation(account string) string {
+	return fmt.Sprintf(`
+resource "google_service_account" "acceptance" {
+  account_id   = "%s"
+  display_name = "Terraform Test"
+}
+
+resource "google_service_account_key_rotation" "acceptance" {
+  service_account_id = google_service_account.acceptance.name
+  rotation_period    = "1s"
+  key_count          = 2
+}
+`, account)
+}
@@ -0,0 +1,184 @@
+package google
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestIamDenyPolicyParent(t *testing.T) {
+	cases := map[string]string{
+		"cloudresourcemanager.googleapis.com/projects/my-project":         "cloudresourcemanager.googleapis.com%2Fprojects%2Fmy-project",
+		"cloudresourcemanager.googleapis.com%2Fprojects%2Fmy-project":     "cloudresourcemanager.googleapis.com%2Fprojects%2Fmy-project",
+		"cloudresourcemanager.googleapis.com/organizations/123456789":     "cloudresourcemanager.googleapis.com%2Forganizations%2F123456789",
+		"cloudresourcemanager.googleapis.com%2Forganizations%2F123456789": "cloudresourcemanager.googleapis.com%2Forganizations%2F123456789",
+	}
+	for parent, expected := range cases {
+		if got := iamDenyPolicyParent(parent); got != expected {
+			t.Errorf("%s: expected %q, got %q", parent, expected, got)
+		}
+	}
+}
+
+func TestAccIAM2DenyPolicy_iamDenyPolicyUpdate(t *testing.T) {
+	t.Parallel()
+
+	context := map[string]interface{}{
+		"org_id":          getTestOrgFromEnv(t),
+		"billing_account": getTestBillingAccountFromEnv(t),
+		"random_suffix":   randString(t, 10),
+	}
+
+	vcrTest(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckIAM2DenyPolicyDestroyProducer(t),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccIAM2DenyPolicy_iamDenyPolicyBasic(context),
+			},
+			{
+				ResourceName:      "google_iam_deny_policy.example",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				Config: testAccIAM2DenyPolicy_iamDenyPolicyUpdate(context),
+			},
+			{
+				ResourceName:      "google_iam_deny_policy.example",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				Config: testAccIAM2DenyPolicy_iamDenyPolicyDataSource(context),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.google_iam_deny_policies.policies", "policies.#", "1"),
+					resource.TestCheckResourceAttr("data.google_iam_deny_policies.policies", "policies.0.name", "tf-test-deny-"+context["random_suffix"].(string)),
+					resource.TestCheckResourceAttr("data.google_iam_deny_policies.policies", "policies.0.rules.#", "1"),
+					resource.TestCheckResourceAttr("data.google_iam_deny_policies.policies", "policies.0.rules.0.deny_rule.0.denied_permissions.0", "cloudresourcemanager.googleapis.com/projects.delete"),
+				),
+			},
+		},
+	})
+}
+
+func testAccIAM2DenyPolicy_iamDenyPolicyBasic(context map[string]interface{}) string {
+	return Nprintf(`
+resource "google_project" "project" {
+  project_id      = "tf-test%{random_suffix}"
+  name            = "tf-test%{random_suffix}"
+  org_id          = "%{org_id}"
+  billing_account = "%{billing_account}"
+}
+
+resource "google_iam_deny_policy" "example" {
+  parent       = urlencode("cloudresourcemanager.googleapis.com/projects/${google_project.project.project_id}")
+  name         = "tf-test-deny-%{random_suffix}"
+  display_name = "A deny rule"
+  rules {
+    description = "First rule"
+    deny_rule {
+      denied_principals = ["principalSet://goog/public:all"]
+      denial_condition {
+        title      = "Some expr"
+        expression = "!resource.matchTag('12345678/env', 'test')"
+      }
+      denied_permissions = ["cloudresourcemanager.googleapis.com/projects.delete"]
+    }
+  }
+  rules {
+    description = "Second rule"
+    deny_rule {
+      denied_principals = ["principalSet://goog/public:all"]
+      denial_condition {
+        title      = "Some expr"
+        expression = "!resource.matchTag('12345678/env', 'test')"
+      }
+      denied_permissions   = ["cloudresourcemanager.googleapis.com/projects.delete"]
+      exception_principals = ["principal://iam.googleapis.com/projects/-/serviceAccounts/${google_service_account.test-account.email}"]
+    }
+  }
+}
+
+resource "google_service_account" "test-account" {
+  account_id   = "tf-test-deny-%{random_suffix}"
+  display_name = "Test Service Account"
+  project      = google_project.project.project_id
+}
+`, context)
+}
+
+func testAccIAM2DenyPolicy_iamDenyPolicyUpdate(context map[string]interface{}) string {
+	return Nprintf(`
+resource "google_project" "project" {
+  project_id      = "tf-test%{random_suffix}"
+  name            = "tf-test%{random_suffix}"
+  org_id          = "%{org_id}"
+  billing_account = "%{billing_account}"
+}
+
+resource "google_iam_deny_policy" "example" {
+  parent       = urlencode("cloudresourcemanager.googleapis.com/projects/${google_project.project.project_id}")
+  name         = "tf-test-deny-%{random_suffix}"
+  display_name = "A deny rule"
+  rules {
+    description = "Second rule"
+    deny_rule {
+      denied_principals = ["principalSet://goog/public:all"]
+      denial_condition {
+        title      = "Some other expr"
+        expression = "!resource.matchTag('12345678/env', 'prod')"
+      }
+      denied_permissions = ["cloudresourcemanager.googleapis.com/projects.delete"]
+    }
+  }
+}
+`, context)
+}
+
+func testAccIAM2DenyPolicy_iamDenyPolicyDataSource(context map[string]interface{}) string {
+	return testAccIAM2DenyPolicy_iamDenyPolicyUpdate(context) + Nprintf(`
+data "google_iam_deny_policies" "policies" {
+  parent = "cloudresourcemanager.googleapis.com/projects/${google_project.project.project_id}"
+
+  depends_on = [google_iam_deny_policy.example]
+}
+`, context)
+}
+
+func testAccCheckIAM2DenyPolicyDestroyProducer(t *testing.T) func(s *terraform.State) error {
+	return func(s *terraform.State) error {
+		for name, rs := range s.RootModule().Resources {
+			if rs.Type != "google_iam_deny_policy" {
+				continue
+			}
+			if strings.HasPrefix(name, "data.") {
+				continue
+			}
+
+			config := googleProviderConfig(t)
+
+			url, err := replaceVarsForTest(config, rs, "{{IAM2BasePath}}policies/{{parent}}/denypolicies/{{name}}")
+			if err != nil {
+				return err
+			}
+
+			billingProject := ""
+
+			if config.BillingProject != "" {
+				billingProject = config.BillingProject
+			}
+
+			_, err = sendRequest(config, "GET", billingProject, url, config.userAgent, nil)
+			if err == nil {
+				return fmt.Errorf("IAM2DenyPolicy still exists at %s", url)
+			}
+		}
+
+		return nil
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"strings"
 
 	"github.com/davecgh/go-spew/spew"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"google.golang.org/api/cloudresourcemanager/v1"
@@ -79,6 +80,11 @@ func ResourceIamBindingWithBatching(parentSpecificSchema map[string]*schema.Sche
 		Update: resourceIamBindingCreateUpdate(newUpdaterFunc, enableBatching),
 		Delete: resourceIamBindingDelete(newUpdaterFunc, enableBatching),
 
+		CustomizeDiff: customdiff.All(
+			iamConditionCustomizeDiff,
+			iamConflictCustomizeDiff(iamConflictKindBinding, parentSpecificSchema, newUpdaterFunc),
+		),
+
 		// if non-empty, this will be used to send a deprecation message when the
 		// resource is used.
 		DeprecationMessage: settings.DeprecationMessage,
@@ -49,6 +49,8 @@ func ResourceIamPolicy(parentSpecificSchema map[string]*schema.Schema, newUpdate
 		Update: ResourceIamPolicyUpdate(newUpdaterFunc),
 		Delete: ResourceIamPolicyDelete(newUpdaterFunc),
 
+		CustomizeDiff: iamConflictCustomizeDiff(iamConflictKindPolicy, parentSpecificSchema, newUpdaterFunc),
+
 		// if non-empty, this will be used to send a deprecation message when the
 		// resource is used.
 		DeprecationMessage: settings.DeprecationMessage,
@@ -0,0 +1,139 @@
+package google
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"google.golang.org/api/cloudkms/v1"
+)
+
+// resourceKMSCryptoKeyWithMaxEnabledVersions wraps the generated
+// google_kms_crypto_key resource with max_enabled_versions, which disables
+// the enabled versions of the key beyond the most recent ones.
+func resourceKMSCryptoKeyWithMaxEnabledVersions() *schema.Resource {
+	r := resourceKMSCryptoKey()
+
+	r.Schema["max_enabled_versions"] = &schema.Schema{
+		Type:         schema.TypeInt,
+		Optional:     true,
+		ValidateFunc: validation.IntAtLeast(1),
+		Description: `The number of most recent enabled CryptoKeyVersions to keep enabled. Older enabled versions,
+such as the ones left behind by rotation, are disabled when Terraform runs. It conflicts with
+google_kms_crypto_key_version resources managing versions of this key.`,
+	}
+	r.Schema["versions_to_disable"] = &schema.Schema{
+		Type:        schema.TypeList,
+		Computed:    true,
+		Description: `The enabled CryptoKeyVersions beyond max_enabled_versions, which will be disabled.`,
+		Elem:        &schema.Schema{Type: schema.TypeString},
+	}
+
+	r.Read = resourceKMSCryptoKeyMaxEnabledVersionsRead
+	r.Update = resourceKMSCryptoKeyMaxEnabledVersionsUpdate
+	r.CustomizeDiff = resourceKMSCryptoKeyCustomizeDiff
+
+	return r
+}
+
+func resourceKMSCryptoKeyMaxEnabledVersionsRead(d *schema.ResourceData, meta interface{}) error {
+	if err := resourceKMSCryptoKeyRead(d, meta); err != nil || d.Id() == "" {
+		return err
+	}
+
+	config := meta.(*Config)
+	userAgent, err := generateUserAgentString(d, config.userAgent)
+	if err != nil {
+		return err
+	}
+	if err := setCryptoKeyVersionsToDisable(d, userAgent, config); err != nil {
+		return fmt.Errorf("Error reading CryptoKey: %s", err)
+	}
+	return nil
+}
+
+func resourceKMSCryptoKeyMaxEnabledVersionsUpdate(d *schema.ResourceData, meta interface{}) error {
+	// The generated update would send an empty update mask when only
+	// max_enabled_versions or the versions to disable changed.
+	if d.HasChangesExcept("max_enabled_versions", "versions_to_disable") {
+		if err := resourceKMSCryptoKeyUpdate(d, meta); err != nil {
+			return err
+		}
+	}
+
+	config := meta.(*Config)
+	userAgent, err := generateUserAgentString(d, config.userAgent)
+	if err != nil {
+		return err
+	}
+	if err := disableCryptoKeyVersions(d, userAgent, config); err != nil {
+		return fmt.Errorf("Error updating CryptoKey %q: %s", d.Id(), err)
+	}
+
+	return resourceKMSCryptoKeyMaxEnabledVersionsRead(d, meta)
+}
+
+// setCryptoKeyVersionsToDisable sets versions_to_disable to the versions of
+// the crypto key in d beyond max_enabled_versions.
+func setCryptoKeyVersionsToDisable(d *schema.ResourceData, userAgent string, config *Config) error {
+	var names []string
+	if max := d.Get("max_enabled_versions").(int); max > 0 {
+		cryptoKeyId, err := parseKmsCryptoKeyId(d.Id(), config)
+		if err != nil {
+			return err
+		}
+		names, err = listCryptoKeyVersionsToDisable(cryptoKeyId, max, userAgent, config)
+		if err != nil {
+			return err
+		}
+	}
+	return d.Set("versions_to_disable", names)
+}
+
+// disableCryptoKeyVersions disables the versions of the crypto key in d
+// beyond max_enabled_versions.
+func disableCryptoKeyVersions(d *schema.ResourceData, userAgent string, config *Config) error {
+	max := d.Get("max_enabled_versions").(int)
+	if max == 0 {
+		return nil
+	}
+	cryptoKeyId, err := parseKmsCryptoKeyId(d.Id(), config)
+	if err != nil {
+		return err
+	}
+	names, err := listCryptoKeyVersionsToDisable(cryptoKeyId, max, userAgent, config)
+	if err != nil {
+		return err
+	}
+
+	versionsClient := config.NewKmsClient(userAgent).Projects.Locations.KeyRings.CryptoKeys.CryptoKeyVersions
+	for _, name := range names {
+		log.Printf("[DEBUG] Disabling CryptoKeyVersion %s", name)
+		patchCall := versionsClient.Patch(name, &cloudkms.CryptoKeyVersion{State: "DISABLED"}).UpdateMask("state")
+		if config.UserProjectOverride {
+			patchCall.Header().Set("X-Goog-User-Project", cryptoKeyId.KeyRingId.Project)
+		}
+		if _, err := patchCall.Do(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// resourceKMSCryptoKeyCustomizeDiff plans the disabling of the versions
+// beyond max_enabled_versions found by the last refresh.
+func resourceKMSCryptoKeyCustomizeDiff(_ context.Context, diff *schema.ResourceDiff, meta interface{}) error {
+	if diff.Id() == "" || diff.Get("max_enabled_versions").(int) == 0 {
+		return nil
+	}
+	if len(diff.Get("versions_to_disable").([]interface{})) > 0 {
+		return diff.SetNew("versions_to_disable", []string{})
+	}
+	if diff.HasChange("max_enabled_versions") {
+		return diff.SetNewComputed("versions_to_disable")
+	}
+	return nil
+}
@@ -3,11 +3,13 @@ package google
 import (
 	"context"
 	"fmt"
+	"reflect"
 	"testing"
 	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"google.golang.org/api/cloudkms/v1"
 )
 
 func TestCryptoKeyIdParsing(t *testing.T) {
@@ -109,6 +111,50 @@ func TestCryptoKeyNextRotationCalculation_validation(t *testing.T) {
 	}
 }
 
+func TestCryptoKeyVersionsToDisable(t *testing.T) {
+	t.Parallel()
+
+	versions := []*cloudkms.CryptoKeyVersion{
+		{Name: "v1", State: "ENABLED", CreateTime: "2022-01-01T00:00:00Z"},
+		{Name: "v3", State: "ENABLED", CreateTime: "2022-03-01T00:00:00Z"},
+		{Name: "v2", State: "DISABLED", CreateTime: "2022-02-01T00:00:00Z"},
+		{Name: "v4", State: "ENABLED", CreateTime: "2022-04-01T00:00:00Z"},
+	}
+
+	cases := map[string]struct {
+		Primary  string
+		Max      int
+		Expected []string
+	}{
+		"keep one": {
+			Primary:  "v4",
+			Max:      1,
+			Expected: []string{"v3", "v1"},
+		},
+		"keep two": {
+			Primary:  "v4",
+			Max:      2,
+			Expected: []string{"v1"},
+		},
+		"keep all": {
+			Primary:  "v4",
+			Max:      3,
+			Expected: nil,
+		},
+		"primary is never disabled": {
+			Primary:  "v1",
+			Max:      1,
+			Expected: []string{"v3"},
+		},
+	}
+
+	for tn, tc := range cases {
+		if got := cryptoKeyVersionsToDisable(versions, tc.Primary, tc.Max); !reflect.DeepEqual(got, tc.Expected) {
+			t.Errorf("%s: expected %v, got %v", tn, tc.Expected, got)
+		}
+	}
+}
+
 func TestCryptoKeyStateUpgradeV0(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,296 @@
+package google
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourceKMSCryptoKeyVersion() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceKMSCryptoKeyVersionCreate,
+		Read:   resourceKMSCryptoKeyVersionRead,
+		Update: resourceKMSCryptoKeyVersionUpdate,
+		Delete: resourceKMSCryptoKeyVersionDelete,
+
+		Importer: &schema.ResourceImporter{
+			State: resourceKMSCryptoKeyVersionImport,
+		},
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(20 * time.Minute),
+			Update: schema.DefaultTimeout(20 * time.Minute),
+			Delete: schema.DefaultTimeout(20 * time.Minute),
+		},
+
+		Schema: map[string]*schema.Schema{
+			"crypto_key": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				DiffSuppressFunc: kmsCryptoKeyVersionCryptoKeysEquivalent,
+				Description: `The CryptoKey that this version belongs to.
+Format: ''projects/{{project}}/locations/{{location}}/keyRings/{{keyRing}}/cryptoKeys/{{cryptoKey}}''.`,
+			},
+			"state": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "ENABLED",
+				ValidateFunc: validation.StringInSlice([]string{"ENABLED", "DISABLED"}, false),
+				Description:  `The state of the version, either ENABLED or DISABLED. Destroying the resource schedules the destruction of the version.`,
+			},
+			"primary": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Computed:    true,
+				Description: `Whether the version is the primary version of its CryptoKey, used for encryption. Only ENCRYPT_DECRYPT keys have a primary version. Another version must be made primary to stop this one from being primary.`,
+			},
+			"name": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: `The resource name of the version.`,
+			},
+			"algorithm": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: `The algorithm of the version.`,
+			},
+			"protection_level": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: `The protection level of the version.`,
+			},
+			"create_time": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: `The time at which the version was created.`,
+			},
+		},
+		UseJSONNumber: true,
+	}
+}
+
+func kmsCryptoKeyVersionCryptoKeysEquivalent(k, old, new string, d *schema.ResourceData) bool {
+	oldId, err := parseKmsCryptoKeyId(old, &Config{})
+	if err != nil {
+		return false
+	}
+	newId, err := parseKmsCryptoKeyId(new, &Config{})
+	if err != nil {
+		return false
+	}
+	return oldId.cryptoKeyId() == newId.cryptoKeyId()
+}
+
+func resourceKMSCryptoKeyVersionCreate(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*Config)
+	userAgent, err := generateUserAgentString(d, config.userAgent)
+	if err != nil {
+		return err
+	}
+
+	cryptoKeyId, err := parseKmsCryptoKeyId(d.Get("crypto_key").(string), config)
+	if err != nil {
+		return err
+	}
+	project := cryptoKeyId.KeyRingId.Project
+
+	url := fmt.Sprintf("%s%s/cryptoKeyVersions", config.KMSBasePath, cryptoKeyId.cryptoKeyId())
+	log.Printf("[DEBUG] Creating new CryptoKeyVersion of %s", cryptoKeyId.cryptoKeyId())
+	res, err := sendRequestWithTimeout(config, "POST", project, url, userAgent, map[string]interface{}{}, d.Timeout(schema.TimeoutCreate))
+	if err != nil {
+		return fmt.Errorf("Error creating CryptoKeyVersion: %s", err)
+	}
+	name, ok := res["name"].(string)
+	if !ok {
+		return fmt.Errorf("Error creating CryptoKeyVersion: the response contains no name")
+	}
+	d.SetId(name)
+
+	// Versions can only be updated once generated.
+	if err := kmsCryptoKeyVersionWaitForGeneration(config, userAgent, project, name, d.Timeout(schema.TimeoutCreate)); err != nil {
+		return err
+	}
+
+	if d.Get("state").(string) != "ENABLED" {
+		if err := kmsCryptoKeyVersionSetState(config, userAgent, project, name, d.Get("state").(string), d.Timeout(schema.TimeoutCreate)); err != nil {
+			return err
+		}
+	}
+	if d.Get("primary").(bool) {
+		if err := kmsCryptoKeyVersionSetPrimary(config, userAgent, cryptoKeyId, name, d.Timeout(schema.TimeoutCreate)); err != nil {
+			return err
+		}
+	}
+
+	log.Printf("[DEBUG] Finished creating CryptoKeyVersion %q", d.Id())
+
+	return resourceKMSCryptoKeyVersionRead(d, meta)
+}
+
+func resourceKMSCryptoKeyVersionRead(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*Config)
+	userAgent, err := generateUserAgentString(d, config.userAgent)
+	if err != nil {
+		return err
+	}
+
+	cryptoKeyId, err := parseKmsCryptoKeyId(d.Get("crypto_key").(string), config)
+	if err != nil {
+		return err
+	}
+	project := cryptoKeyId.KeyRingId.Project
+
+	res, err := sendRequest(config, "GET", project, config.KMSBasePath+d.Id(), userAgent, nil)
+	if err != nil {
+		return handleNotFoundError(err, d, fmt.Sprintf("KMSCryptoKeyVersion %q", d.Id()))
+	}
+
+	switch state := res["state"]; state {
+	case "DESTROY_SCHEDULED", "DESTROYED":
+		log.Printf("[DEBUG] Removing KMSCryptoKeyVersion %q because it is %s.", d.Id(), state)
+		d.SetId("")
+		return nil
+	case "ENABLED", "DISABLED":
+		if err := d.Set("state", state); err != nil {
+			return fmt.Errorf("Error reading CryptoKeyVersion: %s", err)
+		}
+	}
+
+	key, err := sendRequest(config, "GET", project, config.KMSBasePath+cryptoKeyId.cryptoKeyId(), userAgent, nil)
+	if err != nil {
+		return fmt.Errorf("Error reading CryptoKey %s: %s", cryptoKeyId.cryptoKeyId(), err)
+	}
+	primary, _ := key["primary"].(map[string]interface{})
+
+	if err := d.Set("primary", primary != nil && primary["name"] == d.Id()); err != nil {
+		return fmt.Errorf("Error reading CryptoKeyVersion: %s", err)
+	}
+	if err := d.Set("name", res["name"]); err != nil {
+		return fmt.Errorf("Error reading CryptoKeyVersion: %s", err)
+	}
+	if err := d.Set("algorithm", res["algorithm"]); err != nil {
+		return fmt.Errorf("Error reading CryptoKeyVersion: %s", err)
+	}
+	if err := d.Set("protection_level", res["protectionLevel"]); err != nil {
+		return fmt.Errorf("Error reading CryptoKeyVersion: %s", err)
+	}
+	if err := d.Set("create_time", res["createTime"]); err != nil {
+		return fmt.Errorf("Error reading CryptoKeyVersion: %s", err)
+	}
+
+	return nil
+}
+
+func resourceKMSCryptoKeyVersionUpdate(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*Config)
+	userAgent, err := generateUserAgentString(d, config.userAgent)
+	if err != nil {
+		return err
+	}
+
+	cryptoKeyId, err := parseKmsCryptoKeyId(d.Get("crypto_key").(string), config)
+	if err != nil {
+		return err
+	}
+	project := cryptoKeyId.KeyRingId.Project
+
+	// Enable the version before making it primary.
+	if d.HasChange("state") && d.Get("state").(string) == "ENABLED" {
+		if err := kmsCryptoKeyVersionSetState(config, userAgent, project, d.Id(), "ENABLED", d.Timeout(schema.TimeoutUpdate)); err != nil {
+			return err
+		}
+	}
+	// A version stops being primary when another one is made primary.
+	if d.HasChange("primary") && d.Get("primary").(bool) {
+		if err := kmsCryptoKeyVersionSetPrimary(config, userAgent, cryptoKeyId, d.Id(), d.Timeout(schema.TimeoutUpdate)); err != nil {
+			return err
+		}
+	}
+	if d.HasChange("state") && d.Get("state").(string) != "ENABLED" {
+		if err := kmsCryptoKeyVersionSetState(config, userAgent, project, d.Id(), d.Get("state").(string), d.Timeout(schema.TimeoutUpdate)); err != nil {
+			return err
+		}
+	}
+
+	return resourceKMSCryptoKeyVersionRead(d, meta)
+}
+
+func resourceKMSCryptoKeyVersionDelete(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*Config)
+	userAgent, err := generateUserAgentString(d, config.userAgent)
+	if err != nil {
+		return err
+	}
+
+	cryptoKeyId, err := parseKmsCryptoKeyId(d.Get("crypto_key").(string), config)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("[DEBUG] Scheduling the destruction of CryptoKeyVersion %q", d.Id())
+	url := fmt.Sprintf("%s%s:destroy", config.KMSBasePath, d.Id())
+	_, err = sendRequestWithTimeout(config, "POST", cryptoKeyId.KeyRingId.Project, url, userAgent, map[string]interface{}{}, d.Timeout(schema.TimeoutDelete))
+	if err != nil {
+		return handleNotFoundError(err, d, fmt.Sprintf("KMSCryptoKeyVersion %q", d.Id()))
+	}
+
+	d.SetId("")
+	return nil
+}
+
+func resourceKMSCryptoKeyVersionImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	parts := regexp.MustCompile(`^(projects/[^/]+/locations/[^/]+/keyRings/[^/]+/cryptoKeys/[^/]+)/cryptoKeyVersions/[^/]+$`).FindStringSubmatch(d.Id())
+	if parts == nil {
+		return nil, fmt.Errorf("Invalid CryptoKeyVersion id format, expecting `projects/{projectId}/locations/{locationId}/keyRings/{keyRingName}/cryptoKeys/{cryptoKeyName}/cryptoKeyVersions/{version}`, got id: %s", d.Id())
+	}
+	if err := d.Set("crypto_key", parts[1]); err != nil {
+		return nil, fmt.Errorf("Error setting crypto_key: %s", err)
+	}
+
+	return []*schema.ResourceData{d}, nil
+}
+
+func kmsCryptoKeyVersionWaitForGeneration(config *Config, userAgent, project, name string, timeout time.Duration) error {
+	pollRead := func() (map[string]interface{}, error) {
+		return sendRequest(config, "GET", project, config.KMSBasePath+name, userAgent, nil)
+	}
+	checkGenerated := func(res map[string]interface{}, respErr error) PollResult {
+		if respErr != nil {
+			return ErrorPollResult(respErr)
+		}
+		switch state := res["state"]; state {
+		case "PENDING_GENERATION", "PENDING_IMPORT":
+			return PendingStatusPollResult(state.(string))
+		case "GENERATION_FAILED", "IMPORT_FAILED":
+			return ErrorPollResult(fmt.Errorf("CryptoKeyVersion %s is %s", name, state))
+		}
+		return SuccessPollResult()
+	}
+	return PollingWaitTime(pollRead, checkGenerated, "Generating CryptoKeyVersion", timeout, 1)
+}
+
+func kmsCryptoKeyVersionSetState(config *Config, userAgent, project, name, state string, timeout time.Duration) error {
+	url := fmt.Sprintf("%s%s?updateMask=state", config.KMSBasePath, name)
+	_, err := sendRequestWithTimeout(config, "PATCH", project, url, userAgent, map[string]interface{}{"state": state}, timeout)
+	if err != nil {
+		return fmt.Errorf("Error setting the state of CryptoKeyVersion %q to %s: %s", name, state, err)
+	}
+	return nil
+}
+
+func kmsCryptoKeyVersionSetPrimary(config *Config, userAgent string, cryptoKeyId *kmsCryptoKeyId, name string, timeout time.Duration) error {
+	url := fmt.Sprintf("%s%s:updatePrimaryVersion", config.KMSBasePath, cryptoKeyId.cryptoKeyId())
+	obj := map[string]interface{}{
+		"cryptoKeyVersionId": GetResourceNameFromSelfLink(name),
+	}
+	_, err := sendRequestWithTimeout(config, "POST", cryptoKeyId.KeyRingId.Project, url, userAgent, obj, timeout)
+	if err != nil {
+		return fmt.Errorf("Error making CryptoKeyVersion %q primary: %s", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,159 @@
+package google
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccKmsCryptoKeyVersion_lifecycle(t *testing.T) {
+	t.Parallel()
+
+	projectId := fmt.Sprintf("tf-test-%d", randInt(t))
+	projectOrg := getTestOrgFromEnv(t)
+	projectBillingAccount := getTestBillingAccountFromEnv(t)
+	keyRingName := fmt.Sprintf("tf-test-%s", randString(t, 10))
+	cryptoKeyName := fmt.Sprintf("tf-test-%s", randString(t, 10))
+
+	vcrTest(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testGoogleKmsCryptoKeyVersion_lifecycle(projectId, projectOrg, projectBillingAccount, keyRingName, cryptoKeyName, true, "ENABLED"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("google_kms_crypto_key_version.old", "primary", "true"),
+					resource.TestCheckResourceAttr("google_kms_crypto_key_version.new", "state", "DISABLED"),
+				),
+			},
+			{
+				ResourceName:      "google_kms_crypto_key_version.new",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				// Stage the rotation: the new version becomes primary while the
+				// old one stays enabled for decryption.
+				Config: testGoogleKmsCryptoKeyVersion_lifecycle(projectId, projectOrg, projectBillingAccount, keyRingName, cryptoKeyName, false, "ENABLED"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("google_kms_crypto_key_version.new", "primary", "true"),
+					resource.TestCheckResourceAttr("google_kms_crypto_key_version.new", "state", "ENABLED"),
+				),
+			},
+			{
+				Config: testGoogleKmsCryptoKeyVersion_lifecycle(projectId, projectOrg, projectBillingAccount, keyRingName, cryptoKeyName, false, "DISABLED"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("google_kms_crypto_key_version.old", "state", "DISABLED"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccKmsCryptoKey_maxEnabledVersions(t *testing.T) {
+	t.Parallel()
+
+	projectId := fmt.Sprintf("tf-test-%d", randInt(t))
+	projectOrg := getTestOrgFromEnv(t)
+	projectBillingAccount := getTestBillingAccountFromEnv(t)
+	keyRingName := fmt.Sprintf("tf-test-%s", randString(t, 10))
+	cryptoKeyName := fmt.Sprintf("tf-test-%s", randString(t, 10))
+
+	vcrTest(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				// The initial version is left behind once the new one is primary.
+				Config:             testGoogleKmsCryptoKey_maxEnabledVersions(projectId, projectOrg, projectBillingAccount, keyRingName, cryptoKeyName),
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: testGoogleKmsCryptoKey_maxEnabledVersions(projectId, projectOrg, projectBillingAccount, keyRingName, cryptoKeyName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("google_kms_crypto_key.crypto_key", "versions_to_disable.#", "0"),
+				),
+			},
+		},
+	})
+}
+
+func testGoogleKmsCryptoKeyVersion_lifecycle(projectId, projectOrg, projectBillingAccount, keyRingName, cryptoKeyName string, oldPrimary bool, oldState string) string {
+	newState := "DISABLED"
+	if !oldPrimary {
+		newState = "ENABLED"
+	}
+	return fmt.Sprintf(`
+resource "google_project" "acceptance" {
+  name            = "%s"
+  project_id      = "%s"
+  org_id          = "%s"
+  billing_account = "%s"
+}
+
+resource "google_project_service" "acceptance" {
+  project = google_project.acceptance.project_id
+  service = "cloudkms.googleapis.com"
+}
+
+resource "google_kms_key_ring" "key_ring" {
+  project  = google_project_service.acceptance.project
+  name     = "%s"
+  location = "us-central1"
+}
+
+resource "google_kms_crypto_key" "crypto_key" {
+  name                          = "%s"
+  key_ring                      = google_kms_key_ring.key_ring.id
+  skip_initial_version_creation = true
+}
+
+resource "google_kms_crypto_key_version" "old" {
+  crypto_key = google_kms_crypto_key.crypto_key.id
+  primary    = %t
+  state      = "%s"
+}
+
+resource "google_kms_crypto_key_version" "new" {
+  crypto_key = google_kms_crypto_key.crypto_key.id
+  primary    = %t
+  state      = "%s"
+
+  depends_on = [google_kms_crypto_key_version.old]
+}
+`, projectId, projectId, projectOrg, projectBillingAccount, keyRingName, cryptoKeyName, oldPrimary, oldState, !oldPrimary, newState)
+}
+
+func testGoogleKmsCryptoKey_maxEnabledVersions(projectId, projectOrg, projectBillingAccount, keyRingName, cryptoKeyName string) string {
+	return fmt.Sprintf(`
+resource "google_project" "acceptance" {
+  name            = "%s"
+  project_id      = "%s"
+  org_id          = "%s"
+  billing_account = "%s"
+}
+
+resource "google_project_service" "acceptance" {
+  project = google_project.acceptance.project_id
+  service = "cloudkms.googleapis.com"
+}
+
+resource "google_kms_key_ring" "key_ring" {
+  project  = google_project_service.acceptance.project
+  name     = "%s"
+  location = "us-central1"
+}
+
+resource "google_kms_crypto_key" "crypto_key" {
+  name                 = "%s"
+  key_ring             = google_kms_key_ring.key_ring.id
+  max_enabled_versions = 1
+}
+
+resource "google_kms_crypto_key_version" "rotated" {
+  crypto_key = google_kms_crypto_key.crypto_key.id
+  primary    = true
+}
+`, projectId, projectId, projectOrg, projectBillingAccount, keyRingName, cryptoKeyName)
+}
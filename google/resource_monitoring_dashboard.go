@@ -2,7 +2,9 @@ package google
 
 import (
 	"fmt"
+	"log"
 	"reflect"
+	"strings"
 	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -51,7 +53,8 @@ func resourceMonitoringDashboard() *schema.Resource {
 		Schema: map[string]*schema.Schema{
 			"dashboard_json": {
 				Type:             schema.TypeString,
-				Required:         true,
+				Optional:         true,
+				ExactlyOneOf:     []string{"dashboard_json", "display_name"},
 				ValidateFunc:     validation.StringIsJSON,
 				DiffSuppressFunc: monitoringDashboardDiffSuppress,
 				StateFunc: func(v interface{}) string {
@@ -60,6 +63,14 @@ func resourceMonitoringDashboard() *schema.Resource {
 				},
 				Description: `The JSON representation of a dashboard, following the format at https://cloud.google.com/monitoring/api/ref_v3/rest/v1/projects.dashboards.`,
 			},
+			"display_name": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ExactlyOneOf: []string{"dashboard_json", "display_name"},
+				Description:  `The name of the dashboard. Setting it defines the dashboard with the typed layout arguments instead of dashboard_json.`,
+			},
+			"grid_layout":   monitoringDashboardGridLayoutSchema(),
+			"mosaic_layout": monitoringDashboardMosaicLayoutSchema(),
 			"project": {
 				Type:        schema.TypeString,
 				Optional:    true,
@@ -79,7 +90,12 @@ func resourceMonitoringDashboardCreate(d *schema.ResourceData, meta interface{})
 		return err
 	}
 
-	obj, err := structure.ExpandJsonFromString(d.Get("dashboard_json").(string))
+	var obj map[string]interface{}
+	if _, ok := d.GetOk("display_name"); ok {
+		obj, err = expandMonitoringDashboard(d)
+	} else {
+		obj, err = structure.ExpandJsonFromString(d.Get("dashboard_json").(string))
+	}
 	if err != nil {
 		return err
 	}
@@ -130,6 +146,19 @@ func resourceMonitoringDashboardRead(d *schema.ResourceData, meta interface{}) e
 		return fmt.Errorf("Error setting Dashboard: %s", err)
 	}
 
+	// Dashboards defined with the typed arguments are read into them, so
+	// that changes show up field by field. Fields they don't cover are only
+	// logged here, updates check for them before replacing the dashboard.
+	if _, ok := d.GetOk("display_name"); ok {
+		if fields := monitoringDashboardUnmanagedFields(res); len(fields) > 0 {
+			log.Printf("[WARN] Dashboard %q has fields that display_name, grid_layout and mosaic_layout don't manage: %s", d.Id(), strings.Join(fields, ", "))
+		}
+		if err := d.Set("dashboard_json", ""); err != nil {
+			return fmt.Errorf("Error reading Dashboard: %s", err)
+		}
+		return flattenMonitoringDashboard(d, res)
+	}
+
 	str, err := structure.FlattenJsonToString(res)
 	if err != nil {
 		return fmt.Errorf("Error reading Dashboard: %s", err)
@@ -148,6 +177,35 @@ func resourceMonitoringDashboardUpdate(d *schema.ResourceData, meta interface{})
 		return err
 	}
 
+	project, err := getProject(d, config)
+	if err != nil {
+		return err
+	}
+	url := config.MonitoringBasePath + "v1/" + d.Id()
+
+	if _, ok := d.GetOk("display_name"); ok {
+		// The typed arguments replace the whole dashboard, so check it has
+		// nothing else, such as when it was imported.
+		res, err := sendRequest(config, "GET", project, url, userAgent, nil, isMonitoringConcurrentEditError)
+		if err != nil {
+			return fmt.Errorf("Error reading Dashboard %q: %s", d.Id(), err)
+		}
+		if err := monitoringDashboardCheckTyped(d.Id(), res); err != nil {
+			return err
+		}
+
+		obj, err := expandMonitoringDashboard(d)
+		if err != nil {
+			return err
+		}
+		obj["name"] = d.Id()
+		_, err = sendRequestWithTimeout(config, "PATCH", project, url, userAgent, obj, d.Timeout(schema.TimeoutUpdate), isMonitoringConcurrentEditError)
+		if err != nil {
+			return fmt.Errorf("Error updating Dashboard %q: %s", d.Id(), err)
+		}
+		return resourceMonitoringDashboardRead(d, config)
+	}
+
 	o, n := d.GetChange("dashboard_json")
 	oObj, err := structure.ExpandJsonFromString(o.(string))
 	if err != nil {
@@ -160,12 +218,6 @@ func resourceMonitoringDashboardUpdate(d *schema.ResourceData, meta interface{})
 
 	nObj["etag"] = oObj["etag"]
 
-	project, err := getProject(d, config)
-	if err != nil {
-		return err
-	}
-
-	url := config.MonitoringBasePath + "v1/" + d.Id()
 	_, err = sendRequestWithTimeout(config, "PATCH", project, url, userAgent, nObj, d.Timeout(schema.TimeoutUpdate), isMonitoringConcurrentEditError)
 	if err != nil {
 		return fmt.Errorf("Error updating Dashboard %q: %s", d.Id(), err)
@@ -174,6 +226,18 @@ func resourceMonitoringDashboardUpdate(d *schema.ResourceData, meta interface{})
 	return resourceMonitoringDashboardRead(d, config)
 }
 
+// monitoringDashboardCheckTyped returns an error if the dashboard has fields
+// the typed arguments don't manage, as updating it from them would delete
+// these fields.
+func monitoringDashboardCheckTyped(id string, res map[string]interface{}) error {
+	if fields := monitoringDashboardUnmanagedFields(res); len(fields) > 0 {
+		return fmt.Errorf("Dashboard %q has fields that display_name, grid_layout and mosaic_layout don't manage, "+
+			"and that an update from them would delete: %s. Use dashboard_json to manage this dashboard instead.",
+			id, strings.Join(fields, ", "))
+	}
+	return nil
+}
+
 func resourceMonitoringDashboardDelete(d *schema.ResourceData, meta interface{}) error {
 	config := meta.(*Config)
 	userAgent, err := generateUserAgentString(d, config.userAgent)
@@ -1,11 +1,14 @@
 package google
 
 import (
+	"encoding/json"
 	"fmt"
+	"reflect"
 	"strings"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
 
@@ -115,6 +118,225 @@ func TestAccMonitoringDashboard_update(t *testing.T) {
 	})
 }
 
+func TestMonitoringDashboardTypedRoundTrip(t *testing.T) {
+	query := map[string]interface{}{
+		"time_series_filter": []interface{}{
+			map[string]interface{}{
+				"filter": `metric.type="compute.googleapis.com/instance/cpu/utilization"`,
+				"aggregation": []interface{}{
+					map[string]interface{}{
+						"alignment_period":     "60s",
+						"per_series_aligner":   "ALIGN_MEAN",
+						"cross_series_reducer": "REDUCE_MEAN",
+						"group_by_fields":      []interface{}{"resource.label.zone"},
+					},
+				},
+			},
+		},
+	}
+	raw := map[string]interface{}{
+		"display_name": "typed",
+		"grid_layout": []interface{}{
+			map[string]interface{}{
+				"columns": 2,
+				"widgets": []interface{}{
+					map[string]interface{}{
+						"title": "CPU",
+						"xy_chart": []interface{}{
+							map[string]interface{}{
+								"data_sets": []interface{}{
+									map[string]interface{}{
+										"time_series_query": []interface{}{query},
+										"plot_type":         "LINE",
+									},
+								},
+								"y_axis": []interface{}{
+									map[string]interface{}{"label": "y1Axis", "scale": "LINEAR"},
+								},
+								"thresholds": []interface{}{
+									map[string]interface{}{"value": 0.8, "color": "RED", "direction": "ABOVE"},
+								},
+							},
+						},
+					},
+					map[string]interface{}{
+						"scorecard": []interface{}{
+							map[string]interface{}{
+								"time_series_query": []interface{}{
+									map[string]interface{}{"time_series_query_language": "fetch gce_instance | metric 'compute.googleapis.com/instance/cpu/utilization'"},
+								},
+								"gauge_view": []interface{}{
+									map[string]interface{}{"lower_bound": 0.0, "upper_bound": 1.0},
+								},
+							},
+						},
+					},
+					map[string]interface{}{
+						"text": []interface{}{
+							map[string]interface{}{"content": "Hello", "format": "MARKDOWN"},
+						},
+					},
+				},
+			},
+		},
+	}
+	sch := resourceMonitoringDashboard().Schema
+	d := schema.TestResourceDataRaw(t, sch, raw)
+
+	obj, err := expandMonitoringDashboard(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	grid := obj["gridLayout"].(map[string]interface{})
+	if grid["columns"] != "2" {
+		t.Errorf("expected columns to be encoded as \"2\", got %#v", grid["columns"])
+	}
+
+	// Read the object back the way the API returns it.
+	b, err := json.Marshal(obj)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoder := json.NewDecoder(strings.NewReader(string(b)))
+	decoder.UseNumber()
+	var res map[string]interface{}
+	if err := decoder.Decode(&res); err != nil {
+		t.Fatal(err)
+	}
+	if fields := monitoringDashboardUnmanagedFields(res); len(fields) > 0 {
+		t.Errorf("expected all fields to be managed, got %v", fields)
+	}
+	read := schema.TestResourceDataRaw(t, sch, map[string]interface{}{})
+	if err := flattenMonitoringDashboard(read, res); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, k := range []string{
+		"display_name",
+		"grid_layout.0.columns",
+		"grid_layout.0.widgets.0.title",
+		"grid_layout.0.widgets.0.xy_chart.0.data_sets.0.time_series_query",
+		"grid_layout.0.widgets.0.xy_chart.0.data_sets.0.plot_type",
+		"grid_layout.0.widgets.0.xy_chart.0.y_axis",
+		"grid_layout.0.widgets.0.xy_chart.0.thresholds",
+		"grid_layout.0.widgets.1.scorecard",
+		"grid_layout.0.widgets.2.text",
+		"mosaic_layout",
+	} {
+		if expected, got := d.Get(k), read.Get(k); !reflect.DeepEqual(expected, got) {
+			t.Errorf("%s: expected %#v, got %#v", k, expected, got)
+		}
+	}
+}
+
+func TestMonitoringDashboardUnmanagedFields(t *testing.T) {
+	res := map[string]interface{}{
+		"name":        "projects/my-project/dashboards/abc",
+		"etag":        "123",
+		"displayName": "imported",
+		"labels":      map[string]interface{}{"team": "a"},
+		"mosaicLayout": map[string]interface{}{
+			"columns": json.Number("12"),
+			"tiles": []interface{}{
+				map[string]interface{}{
+					"width":  json.Number("6"),
+					"height": json.Number("4"),
+					"widget": map[string]interface{}{
+						"title": "Text",
+						"text":  map[string]interface{}{"content": "Hello", "format": "MARKDOWN"},
+					},
+				},
+				map[string]interface{}{
+					"width": json.Number("6"),
+					"widget": map[string]interface{}{
+						"title":     "Logs",
+						"logsPanel": map[string]interface{}{"filter": "severity>=ERROR"},
+					},
+				},
+			},
+		},
+	}
+
+	expected := []string{"labels", "mosaicLayout.tiles.1.widget.logsPanel"}
+	if fields := monitoringDashboardUnmanagedFields(res); !reflect.DeepEqual(fields, expected) {
+		t.Errorf("expected %v, got %v", expected, fields)
+	}
+}
+
+func TestMonitoringDashboardTypedValidation(t *testing.T) {
+	cases := map[string]map[string]interface{}{
+		"no widget type": {
+			"title": "empty",
+		},
+		"two widget types": {
+			"text": []interface{}{map[string]interface{}{"content": "a"}},
+			"scorecard": []interface{}{
+				map[string]interface{}{
+					"time_series_query": []interface{}{
+						map[string]interface{}{"prometheus_query_language": "up"},
+					},
+				},
+			},
+		},
+		"two queries": {
+			"scorecard": []interface{}{
+				map[string]interface{}{
+					"time_series_query": []interface{}{
+						map[string]interface{}{"prometheus_query_language": "up", "time_series_query_language": "fetch gce_instance"},
+					},
+				},
+			},
+		},
+	}
+
+	for tn, widget := range cases {
+		d := schema.TestResourceDataRaw(t, resourceMonitoringDashboard().Schema, map[string]interface{}{
+			"display_name": tn,
+			"mosaic_layout": []interface{}{
+				map[string]interface{}{
+					"columns": 12,
+					"tiles": []interface{}{
+						map[string]interface{}{"width": 6, "height": 4, "widget": []interface{}{widget}},
+					},
+				},
+			},
+		})
+		if _, err := expandMonitoringDashboard(d); err == nil {
+			t.Errorf("%s: expected error", tn)
+		}
+	}
+}
+
+func TestAccMonitoringDashboard_typed(t *testing.T) {
+	t.Parallel()
+
+	vcrTest(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckMonitoringDashboardDestroyProducer(t),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccMonitoringDashboard_typed("Typed Dashboard", "ALIGN_RATE"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("google_monitoring_dashboard.dashboard", "mosaic_layout.0.tiles.#", "3"),
+					resource.TestCheckResourceAttr("google_monitoring_dashboard.dashboard", "mosaic_layout.0.tiles.0.widget.0.xy_chart.0.data_sets.0.time_series_query.0.time_series_filter.0.aggregation.0.per_series_aligner", "ALIGN_RATE"),
+				),
+			},
+			{
+				Config:   testAccMonitoringDashboard_typed("Typed Dashboard", "ALIGN_RATE"),
+				PlanOnly: true,
+			},
+			{
+				Config: testAccMonitoringDashboard_typed("Typed Dashboard Updated", "ALIGN_MEAN"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("google_monitoring_dashboard.dashboard", "display_name", "Typed Dashboard Updated"),
+					resource.TestCheckResourceAttr("google_monitoring_dashboard.dashboard", "mosaic_layout.0.tiles.0.widget.0.xy_chart.0.data_sets.0.time_series_query.0.time_series_filter.0.aggregation.0.per_series_aligner", "ALIGN_MEAN"),
+				),
+			},
+		},
+	})
+}
+
 func testAccCheckMonitoringDashboardDestroyProducer(t *testing.T) func(s *terraform.State) error {
 	return func(s *terraform.State) error {
 		for name, rs := range s.RootModule().Resources {
@@ -269,3 +491,75 @@ EOF
 }
 `)
 }
+
+func testAccMonitoringDashboard_typed(displayName, aligner string) string {
+	return fmt.Sprintf(`
+resource "google_monitoring_dashboard" "dashboard" {
+  display_name = "%s"
+
+  mosaic_layout {
+    columns = 12
+
+    tiles {
+      width  = 6
+      height = 4
+      widget {
+        title = "VM Instance - CPU utilization"
+        xy_chart {
+          data_sets {
+            plot_type = "LINE"
+            time_series_query {
+              time_series_filter {
+                filter = "metric.type=\"compute.googleapis.com/instance/cpu/utilization\" resource.type=\"gce_instance\""
+                aggregation {
+                  alignment_period   = "60s"
+                  per_series_aligner = "%s"
+                }
+              }
+            }
+          }
+          y_axis {
+            label = "y1Axis"
+            scale = "LINEAR"
+          }
+          thresholds {
+            value     = 0.8
+            color     = "RED"
+            direction = "ABOVE"
+          }
+        }
+      }
+    }
+
+    tiles {
+      x_pos  = 6
+      width  = 6
+      height = 4
+      widget {
+        title = "Uptime"
+        scorecard {
+          time_series_query {
+            prometheus_query_language = "sum(up)"
+          }
+          spark_chart_view {
+            spark_chart_type = "SPARK_LINE"
+          }
+        }
+      }
+    }
+
+    tiles {
+      y_pos  = 4
+      width  = 12
+      height = 2
+      widget {
+        text {
+          content = "Managed by Terraform"
+          format  = "MARKDOWN"
+        }
+      }
+    }
+  }
+}
+`, displayName, aligner)
+}
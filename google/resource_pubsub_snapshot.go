@@ -0,0 +1,236 @@
+package google
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func resourcePubsubSnapshot() *schema.Resource {
+	return &schema.Resource{
+		Create: resourcePubsubSnapshotCreate,
+		Read:   resourcePubsubSnapshotRead,
+		Update: resourcePubsubSnapshotUpdate,
+		Delete: resourcePubsubSnapshotDelete,
+
+		Importer: &schema.ResourceImporter{
+			State: resourcePubsubSnapshotImport,
+		},
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(20 * time.Minute),
+			Update: schema.DefaultTimeout(20 * time.Minute),
+			Delete: schema.DefaultTimeout(20 * time.Minute),
+		},
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				DiffSuppressFunc: compareSelfLinkOrResourceName,
+				Description:      `Name of the snapshot.`,
+			},
+			"subscription": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				DiffSuppressFunc: compareSelfLinkOrResourceName,
+				Description: `The subscription whose backlog the snapshot retains: the unacknowledged messages of the
+subscription when the snapshot is created, and the messages published to its topic afterwards.`,
+			},
+			"labels": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				Description: `A set of key/value label pairs to assign to this Snapshot.`,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"topic": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: `The topic of the subscription.`,
+			},
+			"expire_time": {
+				Type:     schema.TypeString,
+				Computed: true,
+				Description: `The time at which the snapshot expires, in RFC3339 UTC "Zulu" format. A snapshot is deleted
+once the oldest unacknowledged message it retains is 7 days old, or sooner when the subscription's oldest
+unacknowledged message was already older when the snapshot was created.`,
+			},
+			"project": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+		},
+		UseJSONNumber: true,
+	}
+}
+
+func resourcePubsubSnapshotCreate(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*Config)
+	userAgent, err := generateUserAgentString(d, config.userAgent)
+	if err != nil {
+		return err
+	}
+
+	project, err := getProject(d, config)
+	if err != nil {
+		return fmt.Errorf("Error fetching project for Snapshot: %s", err)
+	}
+	billingProject := project
+
+	// err == nil indicates that the billing_project value was found
+	if bp, err := getBillingProject(d, config); err == nil {
+		billingProject = bp
+	}
+
+	obj := map[string]interface{}{
+		"subscription": getComputedSubscriptionName(project, d.Get("subscription").(string)),
+	}
+	if v, ok := d.GetOk("labels"); ok {
+		obj["labels"] = v
+	}
+
+	name := getComputedSnapshotName(project, d.Get("name").(string))
+	log.Printf("[DEBUG] Creating new Snapshot: %#v", obj)
+	res, err := sendRequestWithTimeout(config, "PUT", billingProject, config.PubsubBasePath+name, userAgent, obj, d.Timeout(schema.TimeoutCreate))
+	if err != nil {
+		return fmt.Errorf("Error creating Snapshot: %s", err)
+	}
+
+	d.SetId(name)
+
+	log.Printf("[DEBUG] Finished creating Snapshot %q: %#v", d.Id(), res)
+
+	return resourcePubsubSnapshotRead(d, meta)
+}
+
+func resourcePubsubSnapshotRead(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*Config)
+	userAgent, err := generateUserAgentString(d, config.userAgent)
+	if err != nil {
+		return err
+	}
+
+	project, err := getProject(d, config)
+	if err != nil {
+		return fmt.Errorf("Error fetching project for Snapshot: %s", err)
+	}
+	billingProject := project
+
+	// err == nil indicates that the billing_project value was found
+	if bp, err := getBillingProject(d, config); err == nil {
+		billingProject = bp
+	}
+
+	// Expired snapshots are deleted, and then read as gone.
+	res, err := sendRequest(config, "GET", billingProject, config.PubsubBasePath+d.Id(), userAgent, nil)
+	if err != nil {
+		return handleNotFoundError(err, d, fmt.Sprintf("PubsubSnapshot %q", d.Id()))
+	}
+
+	if err := d.Set("project", project); err != nil {
+		return fmt.Errorf("Error reading Snapshot: %s", err)
+	}
+	if err := d.Set("name", res["name"]); err != nil {
+		return fmt.Errorf("Error reading Snapshot: %s", err)
+	}
+	if err := d.Set("topic", res["topic"]); err != nil {
+		return fmt.Errorf("Error reading Snapshot: %s", err)
+	}
+	if err := d.Set("expire_time", res["expireTime"]); err != nil {
+		return fmt.Errorf("Error reading Snapshot: %s", err)
+	}
+	if err := d.Set("labels", res["labels"]); err != nil {
+		return fmt.Errorf("Error reading Snapshot: %s", err)
+	}
+
+	return nil
+}
+
+func resourcePubsubSnapshotUpdate(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*Config)
+	userAgent, err := generateUserAgentString(d, config.userAgent)
+	if err != nil {
+		return err
+	}
+
+	project, err := getProject(d, config)
+	if err != nil {
+		return fmt.Errorf("Error fetching project for Snapshot: %s", err)
+	}
+	billingProject := project
+
+	// err == nil indicates that the billing_project value was found
+	if bp, err := getBillingProject(d, config); err == nil {
+		billingProject = bp
+	}
+
+	obj := map[string]interface{}{
+		"snapshot": map[string]interface{}{
+			"labels": d.Get("labels"),
+		},
+		"updateMask": "labels",
+	}
+
+	log.Printf("[DEBUG] Updating Snapshot %q: %#v", d.Id(), obj)
+	res, err := sendRequestWithTimeout(config, "PATCH", billingProject, config.PubsubBasePath+d.Id(), userAgent, obj, d.Timeout(schema.TimeoutUpdate))
+	if err != nil {
+		return fmt.Errorf("Error updating Snapshot %q: %s", d.Id(), err)
+	}
+	log.Printf("[DEBUG] Finished updating Snapshot %q: %#v", d.Id(), res)
+
+	return resourcePubsubSnapshotRead(d, meta)
+}
+
+func resourcePubsubSnapshotDelete(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*Config)
+	userAgent, err := generateUserAgentString(d, config.userAgent)
+	if err != nil {
+		return err
+	}
+
+	project, err := getProject(d, config)
+	if err != nil {
+		return fmt.Errorf("Error fetching project for Snapshot: %s", err)
+	}
+	billingProject := project
+
+	// err == nil indicates that the billing_project value was found
+	if bp, err := getBillingProject(d, config); err == nil {
+		billingProject = bp
+	}
+
+	log.Printf("[DEBUG] Deleting Snapshot %q", d.Id())
+	_, err = sendRequestWithTimeout(config, "DELETE", billingProject, config.PubsubBasePath+d.Id(), userAgent, nil, d.Timeout(schema.TimeoutDelete))
+	if err != nil {
+		return handleNotFoundError(err, d, "Snapshot")
+	}
+
+	log.Printf("[DEBUG] Finished deleting Snapshot %q", d.Id())
+	return nil
+}
+
+func resourcePubsubSnapshotImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	config := meta.(*Config)
+	if err := parseImportId([]string{
+		"projects/(?P<project>[^/]+)/snapshots/(?P<name>[^/]+)",
+		"(?P<project>[^/]+)/(?P<name>[^/]+)",
+		"(?P<name>[^/]+)",
+	}, d, config); err != nil {
+		return nil, err
+	}
+
+	// Replace import id for the resource id
+	id, err := replaceVars(d, config, "projects/{{project}}/snapshots/{{name}}")
+	if err != nil {
+		return nil, fmt.Errorf("Error constructing id: %s", err)
+	}
+	d.SetId(id)
+
+	return []*schema.ResourceData{d}, nil
+}
@@ -0,0 +1,94 @@
+package google
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccPubsubSnapshot_update(t *testing.T) {
+	t.Parallel()
+
+	topic := fmt.Sprintf("tf-test-topic-%s", randString(t, 10))
+	subscription := fmt.Sprintf("tf-test-sub-%s", randString(t, 10))
+	snapshot := fmt.Sprintf("tf-test-snapshot-%s", randString(t, 10))
+
+	vcrTest(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPubsubSnapshotDestroyProducer(t),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccPubsubSnapshot(topic, subscription, snapshot, "foo"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("google_pubsub_snapshot.foo", "expire_time"),
+					resource.TestCheckResourceAttrPair("google_pubsub_snapshot.foo", "topic", "google_pubsub_topic.foo", "id"),
+				),
+			},
+			{
+				ResourceName:            "google_pubsub_snapshot.foo",
+				ImportStateId:           snapshot,
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"subscription"},
+			},
+			{
+				Config: testAccPubsubSnapshot(topic, subscription, snapshot, "bar"),
+			},
+			{
+				ResourceName:            "google_pubsub_snapshot.foo",
+				ImportStateId:           snapshot,
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"subscription"},
+			},
+		},
+	})
+}
+
+func testAccCheckPubsubSnapshotDestroyProducer(t *testing.T) func(s *terraform.State) error {
+	return func(s *terraform.State) error {
+		for name, rs := range s.RootModule().Resources {
+			if rs.Type != "google_pubsub_snapshot" {
+				continue
+			}
+			if strings.HasPrefix(name, "data.") {
+				continue
+			}
+
+			config := googleProviderConfig(t)
+
+			_, err := sendRequest(config, "GET", "", config.PubsubBasePath+rs.Primary.ID, config.userAgent, nil)
+			if err == nil {
+				return fmt.Errorf("PubsubSnapshot still exists at %s", rs.Primary.ID)
+			}
+		}
+
+		return nil
+	}
+}
+
+func testAccPubsubSnapshot(topic, subscription, snapshot, label string) string {
+	return fmt.Sprintf(`
+resource "google_pubsub_topic" "foo" {
+  name = "%s"
+}
+
+resource "google_pubsub_subscription" "foo" {
+  name  = "%s"
+  topic = google_pubsub_topic.foo.id
+}
+
+resource "google_pubsub_snapshot" "foo" {
+  name         = "%s"
+  subscription = google_pubsub_subscription.foo.id
+
+  labels = {
+    foo = "%s"
+  }
+}
+`, topic, subscription, snapshot, label)
+}
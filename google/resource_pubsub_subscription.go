@@ -294,6 +294,8 @@ Note: if not specified, the Push endpoint URL will be used.`,
 					},
 				},
 			},
+			"bigquery_config":      pubsubSubscriptionBigqueryConfigSchema(),
+			"cloud_storage_config": pubsubSubscriptionCloudStorageConfigSchema(),
 			"retain_acked_messages": {
 				Type:     schema.TypeBool,
 				Optional: true,
@@ -453,6 +455,10 @@ func resourcePubsubSubscriptionCreate(d *schema.ResourceData, meta interface{})
 		billingProject = bp
 	}
 
+	if err := pubsubSubscriptionDeliveryConfigsPreCreate(d, config, obj, project, userAgent); err != nil {
+		return fmt.Errorf("Error creating Subscription: %s", err)
+	}
+
 	res, err := sendRequestWithTimeout(config, "PUT", billingProject, url, userAgent, obj, d.Timeout(schema.TimeoutCreate))
 	if err != nil {
 		return fmt.Errorf("Error creating Subscription: %s", err)
@@ -540,6 +546,18 @@ func resourcePubsubSubscriptionRead(d *schema.ResourceData, meta interface{}) er
 		return handleNotFoundError(err, d, fmt.Sprintf("PubsubSubscription %q", d.Id()))
 	}
 
+	res, err = resourcePubsubSubscriptionDecoder(d, meta, res)
+	if err != nil {
+		return err
+	}
+
+	if res == nil {
+		// Decoding the object has resulted in it being gone. It may be marked deleted
+		log.Printf("[DEBUG] Removing PubsubSubscription because it no longer exists.")
+		d.SetId("")
+		return nil
+	}
+
 	if err := d.Set("project", project); err != nil {
 		return fmt.Errorf("Error reading Subscription: %s", err)
 	}
@@ -708,6 +726,11 @@ func resourcePubsubSubscriptionUpdate(d *schema.ResourceData, meta interface{})
 		billingProject = bp
 	}
 
+	url, err = pubsubSubscriptionDeliveryConfigsPreUpdate(d, config, obj, url, project, userAgent)
+	if err != nil {
+		return fmt.Errorf("Error updating Subscription %q: %s", d.Id(), err)
+	}
+
 	res, err := sendRequestWithTimeout(config, "PATCH", billingProject, url, userAgent, obj, d.Timeout(schema.TimeoutUpdate))
 
 	if err != nil {
@@ -1196,6 +1219,13 @@ func resourcePubsubSubscriptionEncoder(d *schema.ResourceData, meta interface{},
 	return obj, nil
 }
 
+func resourcePubsubSubscriptionDecoder(d *schema.ResourceData, meta interface{}, res map[string]interface{}) (map[string]interface{}, error) {
+	if err := flattenPubsubSubscriptionDeliveryConfigs(d, res, meta.(*Config)); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
 func resourcePubsubSubscriptionUpdateEncoder(d *schema.ResourceData, meta interface{}, obj map[string]interface{}) (map[string]interface{}, error) {
 	newObj := make(map[string]interface{})
 	newObj["subscription"] = obj
@@ -0,0 +1,164 @@
+package google
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// pubsubDefaultMessageRetention is the message retention of subscriptions
+// that don't set message_retention_duration.
+const pubsubDefaultMessageRetention = 7 * 24 * time.Hour
+
+func resourcePubsubSubscriptionSeek() *schema.Resource {
+	return &schema.Resource{
+		Create: resourcePubsubSubscriptionSeekCreate,
+		Read:   resourcePubsubSubscriptionSeekRead,
+		Delete: resourcePubsubSubscriptionSeekDelete,
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(20 * time.Minute),
+		},
+
+		Schema: map[string]*schema.Schema{
+			"subscription": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				DiffSuppressFunc: compareSelfLinkOrResourceName,
+				Description:      `The subscription to seek.`,
+			},
+			"snapshot": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ForceNew:         true,
+				DiffSuppressFunc: compareSelfLinkOrResourceName,
+				ExactlyOneOf:     []string{"snapshot", "time"},
+				Description: `The snapshot to seek to. The acknowledgment state of the messages in the subscription is
+set to the one captured by the snapshot.`,
+			},
+			"time": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.IsRFC3339Time,
+				ExactlyOneOf: []string{"snapshot", "time"},
+				Description: `The time to seek to, in RFC3339 format. Messages published before this time are marked as
+acknowledged, and messages published after it are marked as unacknowledged.`,
+			},
+			"triggers": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				ForceNew:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: `Arbitrary map of values that, when changed, will seek the subscription again.`,
+			},
+			"project": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+		},
+		UseJSONNumber: true,
+	}
+}
+
+func resourcePubsubSubscriptionSeekCreate(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*Config)
+	userAgent, err := generateUserAgentString(d, config.userAgent)
+	if err != nil {
+		return err
+	}
+
+	project, err := getProject(d, config)
+	if err != nil {
+		return fmt.Errorf("Error fetching project for Subscription seek: %s", err)
+	}
+	billingProject := project
+
+	// err == nil indicates that the billing_project value was found
+	if bp, err := getBillingProject(d, config); err == nil {
+		billingProject = bp
+	}
+
+	subscription := getComputedSubscriptionName(project, d.Get("subscription").(string))
+	obj := make(map[string]interface{})
+	if v, ok := d.GetOk("snapshot"); ok {
+		obj["snapshot"] = getComputedSnapshotName(project, v.(string))
+	} else {
+		seekTime, err := time.Parse(time.RFC3339, d.Get("time").(string))
+		if err != nil {
+			return err
+		}
+
+		res, err := sendRequest(config, "GET", billingProject, config.PubsubBasePath+subscription, userAgent, nil)
+		if err != nil {
+			return fmt.Errorf("Error reading Subscription %q: %s", subscription, err)
+		}
+		retention, _ := res["messageRetentionDuration"].(string)
+		retainAcked, _ := res["retainAckedMessages"].(bool)
+		if err := validatePubsubSubscriptionSeekTime(seekTime, time.Now(), retention, retainAcked); err != nil {
+			return fmt.Errorf("Error seeking Subscription %q: %s", subscription, err)
+		}
+		obj["time"] = seekTime.UTC().Format(time.RFC3339Nano)
+	}
+
+	log.Printf("[DEBUG] Seeking Subscription %q: %#v", subscription, obj)
+	_, err = sendRequestWithTimeout(config, "POST", billingProject, config.PubsubBasePath+subscription+":seek", userAgent, obj, d.Timeout(schema.TimeoutCreate))
+	if err != nil {
+		return fmt.Errorf("Error seeking Subscription %q: %s", subscription, err)
+	}
+
+	d.SetId(fmt.Sprintf("%s/seeks/%d", subscription, time.Now().UnixNano()))
+
+	return resourcePubsubSubscriptionSeekRead(d, meta)
+}
+
+func resourcePubsubSubscriptionSeekRead(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*Config)
+
+	project, err := getProject(d, config)
+	if err != nil {
+		return fmt.Errorf("Error fetching project for Subscription seek: %s", err)
+	}
+	if err := d.Set("project", project); err != nil {
+		return fmt.Errorf("Error reading Subscription seek: %s", err)
+	}
+
+	return nil
+}
+
+func resourcePubsubSubscriptionSeekDelete(d *schema.ResourceData, meta interface{}) error {
+	// A seek can't be undone, it's only removed from state.
+	log.Printf("[DEBUG] Removing Subscription seek %q from state", d.Id())
+	d.SetId("")
+	return nil
+}
+
+// validatePubsubSubscriptionSeekTime checks that seeking to seekTime replays
+// the messages the subscription retains: seeking to the past needs acked
+// messages to be retained, for no longer than the retention duration.
+func validatePubsubSubscriptionSeekTime(seekTime, now time.Time, retention string, retainAcked bool) error {
+	if !seekTime.Before(now) {
+		return nil
+	}
+	if !retainAcked {
+		return fmt.Errorf("seeking to %s can't replay acknowledged messages, as retain_acked_messages isn't set on the subscription", seekTime.Format(time.RFC3339))
+	}
+
+	duration := pubsubDefaultMessageRetention
+	if retention != "" {
+		var err error
+		if duration, err = time.ParseDuration(retention); err != nil {
+			return fmt.Errorf("invalid message_retention_duration %q: %s", retention, err)
+		}
+	}
+	if oldest := now.Add(-duration); seekTime.Before(oldest) {
+		return fmt.Errorf("%s is older than the message_retention_duration of the subscription (%s), which retains messages since %s", seekTime.Format(time.RFC3339), duration, oldest.Format(time.RFC3339))
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+package google
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestPubsubSubscriptionSeekTime(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2022, 3, 8, 12, 0, 0, 0, time.UTC)
+	cases := map[string]struct {
+		Time        time.Time
+		Retention   string
+		RetainAcked bool
+		ExpectError bool
+	}{
+		"future time acks everything": {
+			Time: now.Add(time.Hour),
+		},
+		"past time without retained acked messages": {
+			Time:        now.Add(-time.Hour),
+			Retention:   "604800s",
+			ExpectError: true,
+		},
+		"past time within retention": {
+			Time:        now.Add(-time.Hour),
+			Retention:   "7200s",
+			RetainAcked: true,
+		},
+		"past time beyond retention": {
+			Time:        now.Add(-3 * time.Hour),
+			Retention:   "7200s",
+			RetainAcked: true,
+			ExpectError: true,
+		},
+		"default retention": {
+			Time:        now.Add(-6 * 24 * time.Hour),
+			RetainAcked: true,
+		},
+	}
+
+	for tn, tc := range cases {
+		err := validatePubsubSubscriptionSeekTime(tc.Time, now, tc.Retention, tc.RetainAcked)
+		if tc.ExpectError && err == nil {
+			t.Errorf("%s: expected an error", tn)
+		}
+		if !tc.ExpectError && err != nil {
+			t.Errorf("%s: unexpected error: %s", tn, err)
+		}
+	}
+}
+
+func TestAccPubsubSubscriptionSeek_basic(t *testing.T) {
+	t.Parallel()
+
+	topic := fmt.Sprintf("tf-test-topic-%s", randString(t, 10))
+	subscription := fmt.Sprintf("tf-test-sub-%s", randString(t, 10))
+	snapshot := fmt.Sprintf("tf-test-snapshot-%s", randString(t, 10))
+	seekTime := time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)
+
+	vcrTest(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPubsubSnapshotDestroyProducer(t),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccPubsubSubscriptionSeek(topic, subscription, snapshot, seekTime, "1"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("google_pubsub_subscription_seek.snapshot", "id"),
+					resource.TestCheckResourceAttrSet("google_pubsub_subscription_seek.time", "id"),
+				),
+			},
+			{
+				Config: testAccPubsubSubscriptionSeek(topic, subscription, snapshot, seekTime, "2"),
+			},
+		},
+	})
+}
+
+func testAccPubsubSubscriptionSeek(topic, subscription, snapshot, seekTime, trigger string) string {
+	return fmt.Sprintf(`
+resource "google_pubsub_topic" "foo" {
+  name = "%s"
+}
+
+resource "google_pubsub_subscription" "foo" {
+  name                       = "%s"
+  topic                      = google_pubsub_topic.foo.id
+  retain_acked_messages      = true
+  message_retention_duration = "86400s"
+}
+
+resource "google_pubsub_snapshot" "foo" {
+  name         = "%s"
+  subscription = google_pubsub_subscription.foo.id
+}
+
+resource "google_pubsub_subscription_seek" "snapshot" {
+  subscription = google_pubsub_subscription.foo.id
+  snapshot     = google_pubsub_snapshot.foo.id
+
+  triggers = {
+    incident = "%s"
+  }
+}
+
+resource "google_pubsub_subscription_seek" "time" {
+  subscription = google_pubsub_subscription.foo.id
+  time         = "%s"
+
+  depends_on = [google_pubsub_subscription_seek.snapshot]
+}
+`, topic, subscription, snapshot, trigger, seekTime)
+}
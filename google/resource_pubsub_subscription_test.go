@@ -2,9 +2,12 @@ package google
 
 import (
 	"fmt"
+	"reflect"
+	"regexp"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
 
@@ -114,6 +117,116 @@ func TestAccPubsubSubscription_push(t *testing.T) {
 	})
 }
 
+func TestAccPubsubSubscription_bigqueryConfig(t *testing.T) {
+	t.Parallel()
+
+	dataset := fmt.Sprintf("tf_test_%s", randString(t, 10))
+	topic := fmt.Sprintf("tf-test-topic-%s", randString(t, 10))
+	subscription := fmt.Sprintf("tf-test-sub-%s", randString(t, 10))
+
+	vcrTest(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPubsubSubscriptionDestroyProducer(t),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccPubsubSubscription_bigqueryConfig(dataset, topic, subscription, false),
+			},
+			{
+				ResourceName:      "google_pubsub_subscription.foo",
+				ImportStateId:     subscription,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				Config: testAccPubsubSubscription_bigqueryConfig(dataset, topic, subscription, true),
+			},
+			{
+				ResourceName:      "google_pubsub_subscription.foo",
+				ImportStateId:     subscription,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestAccPubsubSubscription_bigqueryConfigMissingPermissions(t *testing.T) {
+	t.Parallel()
+
+	dataset := fmt.Sprintf("tf_test_%s", randString(t, 10))
+	topic := fmt.Sprintf("tf-test-topic-%s", randString(t, 10))
+	subscription := fmt.Sprintf("tf-test-sub-%s", randString(t, 10))
+
+	vcrTest(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPubsubSubscriptionDestroyProducer(t),
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccPubsubSubscription_bigqueryConfigNoGrant(dataset, topic, subscription),
+				ExpectError: regexp.MustCompile("is missing bigquery.tables.get, bigquery.tables.updateData on BigQuery table"),
+			},
+		},
+	})
+}
+
+func TestAccPubsubSubscription_cloudStorageConfig(t *testing.T) {
+	t.Parallel()
+
+	bucket := fmt.Sprintf("tf-test-bucket-%s", randString(t, 10))
+	topic := fmt.Sprintf("tf-test-topic-%s", randString(t, 10))
+	subscription := fmt.Sprintf("tf-test-sub-%s", randString(t, 10))
+
+	vcrTest(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPubsubSubscriptionDestroyProducer(t),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccPubsubSubscription_cloudStorageConfig(bucket, topic, subscription, ""),
+			},
+			{
+				ResourceName:      "google_pubsub_subscription.foo",
+				ImportStateId:     subscription,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				Config: testAccPubsubSubscription_cloudStorageConfig(bucket, topic, subscription, `
+    avro_config {
+      write_metadata = true
+    }
+`),
+			},
+			{
+				ResourceName:      "google_pubsub_subscription.foo",
+				ImportStateId:     subscription,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestAccPubsubSubscription_deliveryConflicts(t *testing.T) {
+	t.Parallel()
+
+	topic := fmt.Sprintf("tf-test-topic-%s", randString(t, 10))
+	subscription := fmt.Sprintf("tf-test-sub-%s", randString(t, 10))
+
+	vcrTest(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccPubsubSubscription_deliveryConflicts(topic, subscription),
+				ExpectError: regexp.MustCompile("conflicts with"),
+			},
+		},
+	})
+}
+
 // Context: hashicorp/terraform-provider-google#4993
 // This test makes a call to GET an subscription before it is actually created.
 // The PubSub API negative-caches responses so this tests we are
@@ -252,6 +365,180 @@ resource "google_pubsub_subscription" "foo" {
 `, topic, subscription)
 }
 
+func testAccPubsubSubscription_bigqueryConfigNoGrant(dataset, topic, subscription string) string {
+	return fmt.Sprintf(`
+resource "google_bigquery_dataset" "test" {
+  dataset_id = "%s"
+}
+
+resource "google_bigquery_table" "test" {
+  deletion_protection = false
+  dataset_id          = google_bigquery_dataset.test.dataset_id
+  table_id            = "messages"
+
+  schema = <<EOF
+[
+  {
+    "name": "data",
+    "type": "STRING",
+    "mode": "NULLABLE"
+  }
+]
+EOF
+}
+
+resource "google_pubsub_topic" "foo" {
+  name = "%s"
+}
+
+resource "google_pubsub_subscription" "foo" {
+  name  = "%s"
+  topic = google_pubsub_topic.foo.id
+
+  bigquery_config {
+    table = "${google_bigquery_table.test.project}:${google_bigquery_table.test.dataset_id}.${google_bigquery_table.test.table_id}"
+  }
+}
+`, dataset, topic, subscription)
+}
+
+func testAccPubsubSubscription_bigqueryConfig(dataset, topic, subscription string, writeMetadata bool) string {
+	return fmt.Sprintf(`
+data "google_project" "project" {}
+
+resource "google_bigquery_dataset" "test" {
+  dataset_id = "%s"
+}
+
+resource "google_bigquery_table" "test" {
+  deletion_protection = false
+  dataset_id          = google_bigquery_dataset.test.dataset_id
+  table_id            = "messages"
+
+  schema = <<EOF
+[
+  {
+    "name": "data",
+    "type": "STRING",
+    "mode": "NULLABLE"
+  },
+  {
+    "name": "subscription_name",
+    "type": "STRING",
+    "mode": "NULLABLE"
+  },
+  {
+    "name": "message_id",
+    "type": "STRING",
+    "mode": "NULLABLE"
+  },
+  {
+    "name": "publish_time",
+    "type": "TIMESTAMP",
+    "mode": "NULLABLE"
+  },
+  {
+    "name": "attributes",
+    "type": "STRING",
+    "mode": "NULLABLE"
+  }
+]
+EOF
+}
+
+resource "google_bigquery_table_iam_member" "editor" {
+  dataset_id = google_bigquery_table.test.dataset_id
+  table_id   = google_bigquery_table.test.table_id
+  role       = "roles/bigquery.dataEditor"
+  member     = "serviceAccount:service-${data.google_project.project.number}@gcp-sa-pubsub.iam.gserviceaccount.com"
+}
+
+resource "google_pubsub_topic" "foo" {
+  name = "%s"
+}
+
+resource "google_pubsub_subscription" "foo" {
+  name  = "%s"
+  topic = google_pubsub_topic.foo.id
+
+  bigquery_config {
+    table          = "${google_bigquery_table.test.project}:${google_bigquery_table.test.dataset_id}.${google_bigquery_table.test.table_id}"
+    write_metadata = %t
+  }
+
+  depends_on = [google_bigquery_table_iam_member.editor]
+}
+`, dataset, topic, subscription, writeMetadata)
+}
+
+func testAccPubsubSubscription_cloudStorageConfig(bucket, topic, subscription, avroConfig string) string {
+	return fmt.Sprintf(`
+data "google_project" "project" {}
+
+resource "google_storage_bucket" "test" {
+  name          = "%s"
+  location      = "US"
+  force_destroy = true
+}
+
+resource "google_storage_bucket_iam_member" "creator" {
+  bucket = google_storage_bucket.test.name
+  role   = "roles/storage.objectCreator"
+  member = "serviceAccount:service-${data.google_project.project.number}@gcp-sa-pubsub.iam.gserviceaccount.com"
+}
+
+resource "google_storage_bucket_iam_member" "reader" {
+  bucket = google_storage_bucket.test.name
+  role   = "roles/storage.legacyBucketReader"
+  member = "serviceAccount:service-${data.google_project.project.number}@gcp-sa-pubsub.iam.gserviceaccount.com"
+}
+
+resource "google_pubsub_topic" "foo" {
+  name = "%s"
+}
+
+resource "google_pubsub_subscription" "foo" {
+  name  = "%s"
+  topic = google_pubsub_topic.foo.id
+
+  cloud_storage_config {
+    bucket          = google_storage_bucket.test.name
+    filename_prefix = "pre-"
+    filename_suffix = "-suf"
+    max_bytes       = 1000
+    max_duration    = "300s"
+%s
+  }
+
+  depends_on = [
+    google_storage_bucket_iam_member.creator,
+    google_storage_bucket_iam_member.reader,
+  ]
+}
+`, bucket, topic, subscription, avroConfig)
+}
+
+func testAccPubsubSubscription_deliveryConflicts(topic, subscription string) string {
+	return fmt.Sprintf(`
+resource "google_pubsub_topic" "foo" {
+  name = "%s"
+}
+
+resource "google_pubsub_subscription" "foo" {
+  name  = "%s"
+  topic = google_pubsub_topic.foo.id
+
+  push_config {
+    push_endpoint = "https://example.com/push"
+  }
+
+  cloud_storage_config {
+    bucket = "tf-test-bucket"
+  }
+}
+`, topic, subscription)
+}
+
 func TestGetComputedTopicName(t *testing.T) {
 	type testData struct {
 		project  string
@@ -280,6 +567,174 @@ func TestGetComputedTopicName(t *testing.T) {
 	}
 }
 
+func TestParsePubsubBigQueryTable(t *testing.T) {
+	t.Parallel()
+
+	for _, table := range []string{"my-project:my_dataset.my_table", "my-project.my_dataset.my_table"} {
+		project, dataset, tableId, err := parsePubsubBigQueryTable(table)
+		if err != nil {
+			t.Errorf("unexpected error parsing %q: %s", table, err)
+			continue
+		}
+		if project != "my-project" || dataset != "my_dataset" || tableId != "my_table" {
+			t.Errorf("bad parsed table %q: %s, %s, %s", table, project, dataset, tableId)
+		}
+	}
+	if _, _, _, err := parsePubsubBigQueryTable("my_dataset.my_table"); err == nil {
+		t.Errorf("expected an error for a table without project")
+	}
+}
+
+func TestExpandPubsubSubscriptionDeliveryConfigs(t *testing.T) {
+	t.Parallel()
+
+	d := schema.TestResourceDataRaw(t, resourcePubsubSubscription().Schema, map[string]interface{}{
+		"name":  "my-sub",
+		"topic": "my-topic",
+		"cloud_storage_config": []interface{}{
+			map[string]interface{}{
+				"bucket":          "my-bucket",
+				"filename_prefix": "pre-",
+			},
+		},
+	})
+	obj := make(map[string]interface{})
+	if err := expandPubsubSubscriptionDeliveryConfigs(d, &Config{}, obj); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]interface{}{
+		"cloudStorageConfig": map[string]interface{}{
+			"bucket":         "my-bucket",
+			"filenamePrefix": "pre-",
+			"maxDuration":    "300s",
+			"textConfig":     map[string]interface{}{},
+		},
+	}
+	if !reflect.DeepEqual(obj, expected) {
+		t.Errorf("expected %#v, got %#v", expected, obj)
+	}
+}
+
+func TestIamMissingPermissions(t *testing.T) {
+	t.Parallel()
+
+	rolePermissions := map[string][]string{
+		"roles/bigquery.dataViewer":  {"bigquery.tables.get", "bigquery.tables.getData"},
+		"roles/bigquery.dataEditor":  {"bigquery.tables.get", "bigquery.tables.updateData"},
+		"projects/p/roles/tableOnly": {"bigquery.tables.updateData"},
+	}
+	permissions := func(role string) ([]string, error) {
+		p, ok := rolePermissions[role]
+		if !ok {
+			return nil, fmt.Errorf("unknown role %s", role)
+		}
+		return p, nil
+	}
+
+	cases := map[string]struct {
+		Roles   []string
+		Missing []string
+	}{
+		"no roles": {
+			Missing: pubsubBigQueryDeliveryPermissions,
+		},
+		"viewer": {
+			Roles:   []string{"roles/bigquery.dataViewer"},
+			Missing: []string{"bigquery.tables.updateData"},
+		},
+		"editor": {
+			Roles: []string{"roles/bigquery.dataEditor"},
+		},
+		"combined roles": {
+			Roles: []string{"roles/bigquery.dataViewer", "projects/p/roles/tableOnly"},
+		},
+	}
+
+	for tn, tc := range cases {
+		missing, err := iamMissingPermissions(pubsubBigQueryDeliveryPermissions, tc.Roles, permissions)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", tn, err)
+			continue
+		}
+		if !reflect.DeepEqual(missing, tc.Missing) {
+			t.Errorf("%s: expected missing permissions %v, got %v", tn, tc.Missing, missing)
+		}
+	}
+
+	if _, err := iamMissingPermissions(pubsubBigQueryDeliveryPermissions, []string{"roles/unknown"}, permissions); err == nil {
+		t.Errorf("expected an error for a role that can't be read")
+	}
+}
+
+func TestPubsubDeliveryGrantsMissingPermissions(t *testing.T) {
+	t.Parallel()
+
+	rolePermissions := map[string][]string{
+		"roles/bigquery.dataViewer": {"bigquery.tables.get", "bigquery.tables.getData"},
+		"roles/bigquery.dataEditor": {"bigquery.tables.get", "bigquery.tables.updateData"},
+	}
+	permissions := func(role string) ([]string, error) {
+		p, ok := rolePermissions[role]
+		if !ok {
+			return nil, fmt.Errorf("unknown role %s", role)
+		}
+		return p, nil
+	}
+	email := "service-123@gcp-sa-pubsub.iam.gserviceaccount.com"
+
+	cases := map[string]struct {
+		Bindings    map[string][]string
+		Conditional bool
+		Unknown     string
+		Missing     []string
+	}{
+		"granted": {
+			Bindings: map[string][]string{"roles/bigquery.dataEditor": {"serviceAccount:" + email}},
+		},
+		"missing": {
+			Bindings: map[string][]string{
+				"roles/bigquery.dataViewer": {"serviceAccount:" + email},
+				"roles/bigquery.dataEditor": {"user:someone@example.com", "serviceAccount:other@example.iam.gserviceaccount.com"},
+			},
+			Missing: []string{"bigquery.tables.updateData"},
+		},
+		"granted to a group": {
+			Bindings: map[string][]string{
+				"roles/bigquery.dataViewer": {"serviceAccount:" + email},
+				"roles/bigquery.dataEditor": {"group:writers@example.com"},
+			},
+		},
+		"group without the missing permissions": {
+			Bindings: map[string][]string{"roles/bigquery.dataViewer": {"serviceAccount:" + email, "group:readers@example.com"}},
+			Missing:  []string{"bigquery.tables.updateData"},
+		},
+		"granted under a condition": {
+			Bindings:    map[string][]string{"roles/bigquery.dataEditor": {"serviceAccount:" + email}},
+			Conditional: true,
+		},
+		"unknown access": {
+			Unknown: "the ACLs of the bucket",
+		},
+	}
+
+	for tn, tc := range cases {
+		grants := newPubsubDeliveryGrants(email)
+		grants.unknown = tc.Unknown
+		for role, members := range tc.Bindings {
+			grants.addBinding(role, members, tc.Conditional)
+		}
+		missing, err := grants.missingPermissions(pubsubBigQueryDeliveryPermissions, permissions)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", tn, err)
+			continue
+		}
+		if !reflect.DeepEqual(missing, tc.Missing) {
+			t.Errorf("%s: expected missing permissions %v, got %v", tn, tc.Missing, missing)
+		}
+	}
+}
+
 func testAccCheckPubsubSubscriptionCache404(t *testing.T, subName string) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		config := googleProviderConfig(t)
@@ -55,14 +55,20 @@ func resourceSecretManagerSecretVersion() *schema.Resource {
 
 		Update: resourceSecretManagerSecretVersionUpdate,
 
+		CustomizeDiff: resourceSecretManagerSecretVersionWriteOnlyCustomizeDiff,
+
 		Schema: map[string]*schema.Schema{
 			"secret_data": {
-				Type:        schema.TypeString,
-				Required:    true,
-				ForceNew:    true,
-				Description: `The secret data. Must be no larger than 64KiB.`,
-				Sensitive:   true,
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				Description:  `The secret data. Must be no larger than 64KiB.`,
+				Sensitive:    true,
+				ExactlyOneOf: secretManagerSecretVersionDataKeys,
 			},
+			"secret_data_wo":          secretManagerSecretVersionSecretDataWoSchema(),
+			"secret_data_wo_env":      secretManagerSecretVersionSecretDataWoEnvSchema(),
+			"secret_data_fingerprint": secretManagerSecretVersionSecretDataFingerprintSchema(),
 
 			"secret": {
 				Type:             schema.TypeString,
@@ -301,6 +307,7 @@ func flattenSecretManagerSecretVersionPayload(v interface{}, d *schema.ResourceD
 	// if this secret version is disabled, the api will return an error, as the value cannot be accessed, return what we have
 	if d.Get("enabled").(bool) == false {
 		transformed["secret_data"] = d.Get("secret_data")
+		transformed["secret_data_fingerprint"] = d.Get("secret_data_fingerprint")
 		return []interface{}{transformed}
 	}
 
@@ -326,7 +333,7 @@ func flattenSecretManagerSecretVersionPayload(v interface{}, d *schema.ResourceD
 	if err != nil {
 		return err
 	}
-	transformed["secret_data"] = string(data)
+	flattenSecretManagerSecretVersionPayloadWriteOnly(transformed, string(data), d)
 	return []interface{}{transformed}
 }
 
@@ -365,7 +372,11 @@ func expandSecretManagerSecretVersionEnabled(v interface{}, d TerraformResourceD
 
 func expandSecretManagerSecretVersionPayload(v interface{}, d TerraformResourceData, config *Config) (interface{}, error) {
 	transformed := make(map[string]interface{})
-	transformedSecretData, err := expandSecretManagerSecretVersionPayloadSecretData(d.Get("secret_data"), d, config)
+	secretData, err := secretManagerSecretVersionSecretData(d)
+	if err != nil {
+		return nil, err
+	}
+	transformedSecretData, err := expandSecretManagerSecretVersionPayloadSecretData(secretData, d, config)
 	if err != nil {
 		return nil, err
 	} else if val := reflect.ValueOf(transformedSecretData); val.IsValid() && !isEmptyValue(val) {
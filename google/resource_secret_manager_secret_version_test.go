@@ -1,11 +1,87 @@
 package google
 
 import (
+	"io/ioutil"
+	"os"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 )
 
+func TestSecretManagerSecretVersionWriteOnlyData(t *testing.T) {
+	f, err := ioutil.TempFile("", "tf-test-secret-data")
+	if err != nil {
+		t.Fatalf("Cannot create temp file: %s", err)
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.WriteString("from-file"); err != nil {
+		t.Fatalf("Cannot write temp file: %s", err)
+	}
+	f.Close()
+
+	os.Setenv("TF_TEST_SECRET_DATA_WO", "from-env")
+	defer os.Unsetenv("TF_TEST_SECRET_DATA_WO")
+
+	cases := map[string]struct {
+		wo, env     string
+		expected    string
+		expectError bool
+	}{
+		"contents": {
+			wo:       "inline-secret",
+			expected: "inline-secret",
+		},
+		"path": {
+			wo:       f.Name(),
+			expected: "from-file",
+		},
+		"env": {
+			env:      "TF_TEST_SECRET_DATA_WO",
+			expected: "from-env",
+		},
+		"missing file": {
+			wo:          f.Name() + "-missing",
+			expectError: true,
+		},
+		"relative path": {
+			wo:          "./secrets/missing.txt",
+			expectError: true,
+		},
+		"contents with a slash": {
+			wo:       "/not a path",
+			expected: "/not a path",
+		},
+		"unset env": {
+			env:         "TF_TEST_SECRET_DATA_WO_UNSET",
+			expectError: true,
+		},
+	}
+
+	for tn, tc := range cases {
+		data, err := secretManagerSecretVersionWriteOnlyData(tc.wo, tc.env)
+		if tc.expectError {
+			if err == nil {
+				t.Errorf("%s: expected error, got none", tn)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", tn, err)
+			continue
+		}
+		if data != tc.expected {
+			t.Errorf("%s: expected %q, got %q", tn, tc.expected, data)
+		}
+	}
+}
+
+func TestSecretManagerSecretVersionFingerprint(t *testing.T) {
+	expected := "c852753aa9ea32410c200d7fc8df959bf199567bb724fb76dfbd0dc7c2db85fd"
+	if got := secretManagerSecretVersionFingerprint("secret-data"); got != expected {
+		t.Errorf("expected %q, got %q", expected, got)
+	}
+}
+
 func TestAccSecretManagerSecretVersion_update(t *testing.T) {
 	t.Parallel()
 
@@ -94,3 +170,54 @@ resource "google_secret_manager_secret_version" "secret-version-basic" {
 }
 `, context)
 }
+
+func TestAccSecretManagerSecretVersion_writeOnly(t *testing.T) {
+	t.Parallel()
+
+	context := map[string]interface{}{
+		"random_suffix": randString(t, 10),
+		"secret_data":   "my-tf-test-secret",
+	}
+
+	vcrTest(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckSecretManagerSecretVersionDestroyProducer(t),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccSecretManagerSecretVersion_writeOnly(context),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("google_secret_manager_secret_version.secret-version-wo", "secret_data", ""),
+					resource.TestCheckResourceAttr("google_secret_manager_secret_version.secret-version-wo", "secret_data_fingerprint", secretManagerSecretVersionFingerprint("my-tf-test-secret")),
+				),
+			},
+			{
+				Config: testAccSecretManagerSecretVersion_writeOnly(map[string]interface{}{
+					"random_suffix": context["random_suffix"],
+					"secret_data":   "my-tf-test-secret-rotated",
+				}),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("google_secret_manager_secret_version.secret-version-wo", "secret_data_fingerprint", secretManagerSecretVersionFingerprint("my-tf-test-secret-rotated")),
+				),
+			},
+		},
+	})
+}
+
+func testAccSecretManagerSecretVersion_writeOnly(context map[string]interface{}) string {
+	return Nprintf(`
+resource "google_secret_manager_secret" "secret-basic" {
+  secret_id = "tf-test-secret-version-%{random_suffix}"
+
+  replication {
+    automatic = true
+  }
+}
+
+resource "google_secret_manager_secret_version" "secret-version-wo" {
+  secret = google_secret_manager_secret.secret-basic.name
+
+  secret_data_wo = "%{secret_data}"
+}
+`, context)
+}
@@ -21,6 +21,7 @@ import (
 	"reflect"
 	"time"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
@@ -31,6 +32,12 @@ func resourceSpannerDBDdlCustomDiffFunc(diff TerraformResourceDiff) error {
 	newDdls := new.([]interface{})
 	var err error
 
+	// In declarative mode, ddl is the desired schema and the statements
+	// migrating the database to it are planned instead.
+	if declarative, ok := diff.GetOk("declarative_ddl"); ok && declarative.(bool) {
+		return nil
+	}
+
 	if len(newDdls) < len(oldDdls) {
 		err = diff.ForceNew("ddl")
 		if err != nil {
@@ -73,7 +80,10 @@ func resourceSpannerDatabase() *schema.Resource {
 			Delete: schema.DefaultTimeout(20 * time.Minute),
 		},
 
-		CustomizeDiff: resourceSpannerDBDdlCustomDiff,
+		CustomizeDiff: customdiff.All(
+			resourceSpannerDBDdlCustomDiff,
+			resourceSpannerDatabaseDeclarativeDdlCustomizeDiff,
+		),
 
 		Schema: map[string]*schema.Schema{
 			"instance": {
@@ -114,6 +124,22 @@ error in any statement, the database is not created.`,
 					Type: schema.TypeString,
 				},
 			},
+			"declarative_ddl": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Description: `When true, ddl is the full desired schema of the database instead of a list
+of statements to append. The statements migrating the database to it are
+computed from its current DDL and shown in ddl_migration.`,
+			},
+			"ddl_migration": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Description: `With declarative_ddl, the statements of the last DDL migration. The plan
+shows the statements of a pending migration.`,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
 			"encryption_config": {
 				Type:        schema.TypeList,
 				Optional:    true,
@@ -332,6 +358,12 @@ func resourceSpannerDatabaseRead(d *schema.ResourceData, meta interface{}) error
 		return fmt.Errorf("Error reading Database: %s", err)
 	}
 
+	if d.Get("declarative_ddl").(bool) {
+		if err := spannerDatabaseReadDeclarativeDdl(d, config, billingProject, userAgent); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -352,7 +384,7 @@ func resourceSpannerDatabaseUpdate(d *schema.ResourceData, meta interface{}) err
 
 	d.Partial(true)
 
-	if d.HasChange("ddl") {
+	if d.HasChanges("ddl", "declarative_ddl") {
 		obj := make(map[string]interface{})
 
 		extraStatementsProp, err := expandSpannerDatabaseDdl(d.Get("ddl"), d, config)
@@ -366,6 +398,11 @@ func resourceSpannerDatabaseUpdate(d *schema.ResourceData, meta interface{}) err
 		if err != nil {
 			return err
 		}
+		if obj == nil {
+			// The encoder found no statements to send.
+			d.Partial(false)
+			return resourceSpannerDatabaseRead(d, meta)
+		}
 
 		url, err := replaceVars(d, config, "{{SpannerBasePath}}projects/{{project}}/instances/{{instance}}/databases/{{name}}/ddl")
 		if err != nil {
@@ -569,9 +606,22 @@ func resourceSpannerDatabaseUpdateEncoder(d *schema.ResourceData, meta interface
 	newDdls := new.([]interface{})
 	updateDdls := []string{}
 
-	//Only new ddl statments to be add to update call
-	for i := len(oldDdls); i < len(newDdls); i++ {
-		updateDdls = append(updateDdls, newDdls[i].(string))
+	if d.Get("declarative_ddl").(bool) {
+		statements, err := spannerDatabaseDeclarativeDdlStatements(d, meta.(*Config))
+		if err != nil {
+			return nil, err
+		}
+		if len(statements) == 0 {
+			// The ddl change only reformats the statements, or the database
+			// already matches it.
+			return nil, nil
+		}
+		updateDdls = append(updateDdls, statements...)
+	} else {
+		//Only new ddl statments to be add to update call
+		for i := len(oldDdls); i < len(newDdls); i++ {
+			updateDdls = append(updateDdls, newDdls[i].(string))
+		}
 	}
 
 	obj["statements"] = updateDdls
@@ -3,6 +3,7 @@ package google
 import (
 	"fmt"
 	"regexp"
+	"strings"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
@@ -290,6 +291,192 @@ func TestSpannerDatabase_resourceSpannerDBDdlCustomDiffFuncForceNew(t *testing.T
 	}
 }
 
+func TestSpannerDatabaseDdlMigration(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		current     []string
+		desired     []string
+		statements  []string
+		destructive int
+		expectErr   bool
+	}{
+		"formatting only": {
+			current: []string{"CREATE TABLE t1 (\n  t1 INT64 NOT NULL,\n  s STRING(MAX),\n) PRIMARY KEY(t1)"},
+			desired: []string{"create table T1 (t1 int64 not null, s string(max)) primary key (t1);"},
+		},
+		"add and drop tables": {
+			current: []string{
+				"CREATE TABLE t1 (t1 INT64 NOT NULL,) PRIMARY KEY(t1)",
+				"CREATE TABLE t2 (t2 INT64 NOT NULL,) PRIMARY KEY(t2)",
+				"CREATE TABLE t2c (t2 INT64 NOT NULL, c INT64 NOT NULL,) PRIMARY KEY(t2, c), INTERLEAVE IN PARENT t2 ON DELETE CASCADE",
+			},
+			desired: []string{
+				"CREATE TABLE t1 (t1 INT64 NOT NULL,) PRIMARY KEY(t1)",
+				"CREATE TABLE t3 (t3 INT64 NOT NULL,) PRIMARY KEY(t3)",
+			},
+			statements: []string{
+				"DROP TABLE T2C",
+				"DROP TABLE T2",
+				"CREATE TABLE t3 (t3 INT64 NOT NULL,) PRIMARY KEY(t3)",
+			},
+			destructive: 2,
+		},
+		"columns": {
+			current: []string{"CREATE TABLE t1 (t1 INT64 NOT NULL, a STRING(10), b BYTES(MAX),) PRIMARY KEY(t1)"},
+			desired: []string{"CREATE TABLE t1 (t1 INT64 NOT NULL, a STRING(20), c ARRAY<STRING(MAX)>, d BOOL) PRIMARY KEY(t1)"},
+			statements: []string{
+				"ALTER TABLE T1 DROP COLUMN B",
+				"ALTER TABLE T1 ADD COLUMN c ARRAY<STRING(MAX)>",
+				"ALTER TABLE T1 ADD COLUMN d BOOL",
+				"ALTER TABLE T1 ALTER COLUMN a STRING(20)",
+			},
+			destructive: 1,
+		},
+		"indexes and change streams": {
+			current: []string{
+				"CREATE TABLE t1 (t1 INT64 NOT NULL, a STRING(MAX),) PRIMARY KEY(t1)",
+				"CREATE INDEX i1 ON t1(a)",
+				"CREATE INDEX i2 ON t1(a)",
+				"CREATE CHANGE STREAM s1 FOR ALL",
+			},
+			desired: []string{
+				"CREATE TABLE t1 (t1 INT64 NOT NULL, a STRING(MAX),) PRIMARY KEY(t1)",
+				"CREATE INDEX i1 ON t1(a DESC)",
+				"CREATE UNIQUE NULL_FILTERED INDEX i3 ON t1(a)",
+				"CREATE CHANGE STREAM s2 FOR t1",
+			},
+			statements: []string{
+				"DROP CHANGE STREAM S1",
+				"DROP INDEX I1",
+				"DROP INDEX I2",
+				"CREATE INDEX i1 ON t1(a DESC)",
+				"CREATE UNIQUE NULL_FILTERED INDEX i3 ON t1(a)",
+				"CREATE CHANGE STREAM s2 FOR t1",
+			},
+			destructive: 1,
+		},
+		"constraints and row deletion policies": {
+			current: []string{
+				"CREATE TABLE t1 (t1 INT64 NOT NULL, ts TIMESTAMP, CONSTRAINT c1 CHECK (t1 > 0),) PRIMARY KEY(t1)",
+			},
+			desired: []string{
+				"CREATE TABLE t1 (t1 INT64 NOT NULL, ts TIMESTAMP, CONSTRAINT c2 CHECK (t1 < 100)) PRIMARY KEY(t1), ROW DELETION POLICY (OLDER_THAN(ts, INTERVAL 30 DAY))",
+			},
+			statements: []string{
+				"ALTER TABLE T1 DROP CONSTRAINT C1",
+				"ALTER TABLE T1 ADD ROW DELETION POLICY (OLDER_THAN(ts, INTERVAL 30 DAY))",
+				"ALTER TABLE T1 ADD CONSTRAINT c2 CHECK (t1 < 100)",
+			},
+		},
+		"primary key change": {
+			current:   []string{"CREATE TABLE t1 (t1 INT64 NOT NULL, a INT64 NOT NULL,) PRIMARY KEY(t1)"},
+			desired:   []string{"CREATE TABLE t1 (t1 INT64 NOT NULL, a INT64 NOT NULL,) PRIMARY KEY(t1, a)"},
+			expectErr: true,
+		},
+		"removed other statement": {
+			current:   []string{"CREATE ROLE analyst"},
+			desired:   []string{},
+			expectErr: true,
+		},
+		"added other statement": {
+			current:    []string{},
+			desired:    []string{"CREATE ROLE analyst"},
+			statements: []string{"CREATE ROLE analyst"},
+		},
+		"appended statements": {
+			current: []string{
+				"CREATE TABLE t1 (\n  t1 INT64 NOT NULL,\n  b STRING(20),\n  c BOOL,\n  CONSTRAINT c1 CHECK (t1 > 0),\n) PRIMARY KEY(t1), ROW DELETION POLICY (OLDER_THAN(ts, INTERVAL 7 DAY))",
+				"CREATE INDEX i2 ON t1(c)",
+				"ALTER DATABASE db SET OPTIONS (version_retention_period = '7d')",
+			},
+			desired: []string{
+				"CREATE TABLE t1 (t1 INT64 NOT NULL, a STRING(MAX), b STRING(10)) PRIMARY KEY (t1)",
+				"CREATE TABLE t2 (t2 INT64 NOT NULL) PRIMARY KEY (t2)",
+				"CREATE INDEX i1 ON t1(a)",
+				"ALTER TABLE t1 ADD COLUMN c BOOL",
+				"ALTER TABLE t1 DROP COLUMN a",
+				"ALTER TABLE t1 ALTER COLUMN b STRING(20)",
+				"ALTER TABLE t1 ADD CONSTRAINT c1 CHECK (t1 > 0)",
+				"ALTER TABLE t1 ADD ROW DELETION POLICY (OLDER_THAN(ts, INTERVAL 7 DAY))",
+				"DROP TABLE t2",
+				"DROP INDEX i1",
+				"CREATE INDEX i2 ON t1(c)",
+				"ALTER DATABASE db SET OPTIONS (version_retention_period = '7d')",
+			},
+		},
+		"appended statements of a new table": {
+			current: []string{},
+			desired: []string{
+				"CREATE TABLE t1 (t1 INT64 NOT NULL, a STRING(MAX)) PRIMARY KEY (t1)",
+				"ALTER TABLE t1 ADD COLUMN b BOOL",
+			},
+			statements: []string{"CREATE TABLE t1 (\n  t1 INT64 NOT NULL,\n  a STRING(MAX),\n  b BOOL,\n) PRIMARY KEY (t1)"},
+		},
+		"dropped undefined table": {
+			current:   []string{},
+			desired:   []string{"DROP TABLE t1"},
+			expectErr: true,
+		},
+	}
+
+	for tn, tc := range cases {
+		migration, err := spannerDatabaseDdlMigration(tc.current, tc.desired)
+		if tc.expectErr {
+			if err == nil {
+				t.Errorf("%s: expected error, got statements %v", tn, migration.statements)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", tn, err)
+			continue
+		}
+		if fmt.Sprintf("%q", migration.statements) != fmt.Sprintf("%q", tc.statements) {
+			t.Errorf("%s: expected statements %q, got %q", tn, tc.statements, migration.statements)
+		}
+		if len(migration.destructive) != tc.destructive {
+			t.Errorf("%s: expected %d destructive statements, got %q", tn, tc.destructive, migration.destructive)
+		}
+	}
+}
+
+func TestSpannerDatabase_resourceSpannerDBDdlCustomDiffFuncDeclarative(t *testing.T) {
+	t.Parallel()
+
+	d := &ResourceDiffMock{
+		Before: map[string]interface{}{"ddl": []interface{}{"CREATE TABLE t1 (t1 INT64 NOT NULL, a STRING(MAX),) PRIMARY KEY(t1)"}},
+		After:  map[string]interface{}{"ddl": []interface{}{"CREATE TABLE t1 (t1 INT64 NOT NULL,) PRIMARY KEY(t1)"}, "declarative_ddl": true},
+	}
+	if err := resourceSpannerDBDdlCustomDiffFunc(d); err != nil {
+		t.Fatal(err)
+	}
+	if d.IsForceNew {
+		t.Errorf("expected declarative ddl changes not to recreate the database")
+	}
+}
+
+func TestSpannerDatabaseDeclarativeDdlPlan(t *testing.T) {
+	t.Parallel()
+
+	current := []string{"CREATE TABLE t1 (t1 INT64 NOT NULL, a STRING(MAX),) PRIMARY KEY(t1)"}
+	desired := []string{"CREATE TABLE t1 (t1 INT64 NOT NULL,) PRIMARY KEY(t1)"}
+
+	if _, err := spannerDatabaseDeclarativeDdlPlan(current, desired, true); err == nil {
+		t.Errorf("expected dropping a column to fail with deletion_protection")
+	}
+	statements, err := spannerDatabaseDeclarativeDdlPlan(current, desired, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if migration := fmt.Sprint(statements); migration != "[ALTER TABLE T1 DROP COLUMN A]" {
+		t.Errorf("unexpected ddl_migration %s", migration)
+	}
+	if statements, err := spannerDatabaseDeclarativeDdlPlan(current, current, true); err != nil || statements == nil || len(statements) != 0 {
+		t.Errorf("expected an empty migration, got %q, %v", statements, err)
+	}
+}
+
 func TestAccSpannerDatabase_deletionProtection(t *testing.T) {
 	skipIfVcr(t)
 	t.Parallel()
@@ -342,3 +529,96 @@ resource "google_spanner_database" "database" {
 }
 `, context)
 }
+
+func TestAccSpannerDatabase_declarativeDdl(t *testing.T) {
+	t.Parallel()
+
+	context := map[string]interface{}{
+		"random_suffix": randString(t, 10),
+	}
+
+	vcrTest(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckSpannerDatabaseDestroyProducer(t),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccSpannerDatabase_declarativeDdl(context, `
+    "CREATE TABLE t1 (t1 INT64 NOT NULL, a STRING(10), b BYTES(MAX)) PRIMARY KEY (t1)",
+    "CREATE TABLE t2 (t2 INT64 NOT NULL) PRIMARY KEY (t2)",
+    "CREATE INDEX t1_by_a ON t1 (a)",`),
+			},
+			{
+				// The DDL read from the database defines the same schema.
+				Config: testAccSpannerDatabase_declarativeDdl(context, `
+    "CREATE TABLE t1 (t1 INT64 NOT NULL, a STRING(10), b BYTES(MAX)) PRIMARY KEY (t1)",
+    "CREATE TABLE t2 (t2 INT64 NOT NULL) PRIMARY KEY (t2)",
+    "CREATE INDEX t1_by_a ON t1 (a)",`),
+				PlanOnly: true,
+			},
+			{
+				Config: testAccSpannerDatabase_declarativeDdl(context, `
+    "CREATE TABLE t1 (t1 INT64 NOT NULL, a STRING(20), c BOOL) PRIMARY KEY (t1)",
+    "CREATE INDEX t1_by_c ON t1 (c)",
+    "CREATE CHANGE STREAM t1_changes FOR t1",`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("google_spanner_database.database", "ddl_migration.#", "7"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccSpannerDatabase_declarativeDdlSwitch(t *testing.T) {
+	t.Parallel()
+
+	context := map[string]interface{}{
+		"random_suffix": randString(t, 10),
+	}
+	ddl := `
+    "CREATE TABLE t1 (t1 INT64 NOT NULL, a STRING(10)) PRIMARY KEY (t1)",
+    "ALTER TABLE t1 ADD COLUMN b BOOL",
+    "ALTER TABLE t1 DROP COLUMN a",`
+
+	vcrTest(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckSpannerDatabaseDestroyProducer(t),
+		Steps: []resource.TestStep{
+			{
+				Config: strings.Replace(testAccSpannerDatabase_declarativeDdl(context, ddl), "declarative_ddl     = true", "declarative_ddl     = false", 1),
+			},
+			{
+				// The statements appended so far define the schema of the database.
+				Config: testAccSpannerDatabase_declarativeDdl(context, ddl),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("google_spanner_database.database", "ddl_migration.#", "0"),
+				),
+			},
+			{
+				Config:   testAccSpannerDatabase_declarativeDdl(context, ddl),
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+func testAccSpannerDatabase_declarativeDdl(context map[string]interface{}, ddl string) string {
+	context["ddl"] = ddl
+	return Nprintf(`
+resource "google_spanner_instance" "main" {
+  config       = "regional-europe-west1"
+  display_name = "tf-test-%{random_suffix}"
+  num_nodes    = 1
+}
+
+resource "google_spanner_database" "database" {
+  instance            = google_spanner_instance.main.name
+  name                = "tf-test-%{random_suffix}"
+  declarative_ddl     = true
+  deletion_protection = false
+  ddl = [%{ddl}
+  ]
+}
+`, context)
+}
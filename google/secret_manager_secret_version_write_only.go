@@ -0,0 +1,163 @@
+package google
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// The write-only inputs of google_secret_manager_secret_version keep the
+// secret payload out of state, storing only its SHA-256 fingerprint.
+
+var secretManagerSecretVersionDataKeys = []string{
+	"secret_data",
+	"secret_data_wo",
+	"secret_data_wo_env",
+}
+
+func secretManagerSecretVersionSecretDataWoSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeString,
+		Optional: true,
+		ForceNew: true,
+		// Only the fingerprint of the payload is ever written to state. A
+		// path that can't be read fails the plan in
+		// resourceSecretManagerSecretVersionWriteOnlyCustomizeDiff.
+		StateFunc: func(v interface{}) string {
+			data, _, err := pathOrContents(v.(string))
+			if err != nil {
+				return ""
+			}
+			return secretManagerSecretVersionFingerprint(data)
+		},
+		Description: `The secret data, or a path to a file containing it. Must be no larger than 64KiB.
+Unlike secret_data, only a SHA-256 fingerprint of the payload is stored in state.`,
+		Sensitive:    true,
+		ExactlyOneOf: secretManagerSecretVersionDataKeys,
+	}
+}
+
+func secretManagerSecretVersionSecretDataWoEnvSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeString,
+		Optional: true,
+		ForceNew: true,
+		Description: `The name of an environment variable holding the secret data. Must be no larger than 64KiB.
+Only a SHA-256 fingerprint of the payload is stored in state.`,
+		ExactlyOneOf: secretManagerSecretVersionDataKeys,
+	}
+}
+
+func secretManagerSecretVersionSecretDataFingerprintSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeString,
+		Computed:    true,
+		Description: `The hex-encoded SHA-256 fingerprint of the secret data.`,
+	}
+}
+
+// secretManagerSecretVersionFingerprint returns the value stored in state in
+// place of the secret payload when one of the write-only inputs is used.
+func secretManagerSecretVersionFingerprint(data string) string {
+	sum := sha256.Sum256([]byte(data))
+	return hex.EncodeToString(sum[:])
+}
+
+// secretManagerSecretVersionIsWriteOnly reports whether the payload is supplied
+// through one of the inputs that must not be persisted in state.
+func secretManagerSecretVersionIsWriteOnly(wo, env string) bool {
+	return wo != "" || env != ""
+}
+
+// secretManagerSecretVersionLooksLikePath reports whether a secret_data_wo
+// value that isn't an existing file was most likely meant as a path.
+func secretManagerSecretVersionLooksLikePath(v string) bool {
+	if strings.ContainsAny(v, " \t\r\n") {
+		return false
+	}
+	for _, prefix := range []string{"/", "./", "../", "~/"} {
+		if strings.HasPrefix(v, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// secretManagerSecretVersionWriteOnlyData resolves the payload of a write-only
+// input, reading the named environment variable or the referenced file.
+func secretManagerSecretVersionWriteOnlyData(wo, env string) (string, error) {
+	if env != "" {
+		data, ok := os.LookupEnv(env)
+		if !ok {
+			return "", fmt.Errorf("environment variable %q referenced by secret_data_wo_env is not set", env)
+		}
+		return data, nil
+	}
+
+	data, isPath, err := pathOrContents(wo)
+	if err != nil {
+		return "", fmt.Errorf("Error reading secret_data_wo: %s", err)
+	}
+	// The value isn't included in the error, in case it's the secret itself.
+	if !isPath && secretManagerSecretVersionLooksLikePath(wo) {
+		return "", fmt.Errorf("secret_data_wo looks like a file path, but no file exists at it. Fix the path, or pass the payload itself")
+	}
+	return data, nil
+}
+
+// secretManagerSecretVersionSecretData returns the payload to create the
+// version with, from secret_data or one of the write-only inputs.
+func secretManagerSecretVersionSecretData(d TerraformResourceData) (interface{}, error) {
+	wo, env := d.Get("secret_data_wo").(string), d.Get("secret_data_wo_env").(string)
+	if !secretManagerSecretVersionIsWriteOnly(wo, env) {
+		return d.Get("secret_data"), nil
+	}
+	return secretManagerSecretVersionWriteOnlyData(wo, env)
+}
+
+// flattenSecretManagerSecretVersionPayloadWriteOnly sets the accessed payload
+// of the version in transformed, or only its fingerprint for write-only
+// inputs.
+func flattenSecretManagerSecretVersionPayloadWriteOnly(transformed map[string]interface{}, data string, d *schema.ResourceData) {
+	transformed["secret_data_fingerprint"] = secretManagerSecretVersionFingerprint(data)
+	if !secretManagerSecretVersionIsWriteOnly(d.Get("secret_data_wo").(string), d.Get("secret_data_wo_env").(string)) {
+		transformed["secret_data"] = data
+	}
+}
+
+// resourceSecretManagerSecretVersionWriteOnlyCustomizeDiff checks that the
+// configured write-only input can be read, compares its fingerprint against
+// the fingerprint of the accessed version and plans a new version when they
+// differ.
+func resourceSecretManagerSecretVersionWriteOnlyCustomizeDiff(_ context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if !d.NewValueKnown("secret_data_wo") || !d.NewValueKnown("secret_data_wo_env") {
+		return nil
+	}
+	wo, env := d.Get("secret_data_wo").(string), d.Get("secret_data_wo_env").(string)
+	if !secretManagerSecretVersionIsWriteOnly(wo, env) {
+		return nil
+	}
+
+	data, err := secretManagerSecretVersionWriteOnlyData(wo, env)
+	if err != nil {
+		return err
+	}
+
+	fingerprint := secretManagerSecretVersionFingerprint(data)
+	if d.Get("secret_data_fingerprint").(string) == fingerprint {
+		return nil
+	}
+
+	if err := d.SetNew("secret_data_fingerprint", fingerprint); err != nil {
+		return err
+	}
+	if d.Id() == "" {
+		return nil
+	}
+	return d.ForceNew("secret_data_fingerprint")
+}
@@ -0,0 +1,703 @@
+package google
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// The declarative DDL mode of google_spanner_database compares the desired
+// schema with the current one object by object. Tables, their columns,
+// constraints and row deletion policies, indexes and change streams are
+// migrated; other statements can only be added. ALTER and DROP statements
+// of these objects, such as the ones appended to ddl before switching to the
+// declarative mode, are folded into the objects they change.
+
+type spannerDdlToken struct {
+	// canonical is the upper-cased text of the token, or the text of a
+	// literal, used to compare statements regardless of formatting.
+	canonical  string
+	start, end int
+}
+
+// tokenizeSpannerDdl splits a statement into tokens, dropping whitespace and
+// comments.
+func tokenizeSpannerDdl(s string) ([]spannerDdlToken, error) {
+	var tokens []spannerDdlToken
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '#' || strings.HasPrefix(s[i:], "--"):
+			for i < len(s) && s[i] != '\n' {
+				i++
+			}
+		case strings.HasPrefix(s[i:], "/*"):
+			end := strings.Index(s[i+2:], "*/")
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated comment in %q", s)
+			}
+			i += end + 4
+		case c == '\'' || c == '"':
+			quote := string(c)
+			if strings.HasPrefix(s[i:], strings.Repeat(quote, 3)) {
+				quote = strings.Repeat(quote, 3)
+			}
+			j := i + len(quote)
+			for ; j < len(s) && !strings.HasPrefix(s[j:], quote); j++ {
+				if s[j] == '\\' {
+					j++
+				}
+			}
+			if j >= len(s) {
+				return nil, fmt.Errorf("unterminated string in %q", s)
+			}
+			j += len(quote)
+			tokens = append(tokens, spannerDdlToken{canonical: s[i:j], start: i, end: j})
+			i = j
+		case c == '`':
+			end := strings.IndexByte(s[i+1:], '`')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated quoted identifier in %q", s)
+			}
+			j := i + end + 2
+			// Identifiers are case insensitive, quoted or not.
+			tokens = append(tokens, spannerDdlToken{canonical: strings.ToUpper(s[i+1 : j-1]), start: i, end: j})
+			i = j
+		case isSpannerDdlWordChar(c):
+			j := i
+			for j < len(s) && isSpannerDdlWordChar(s[j]) {
+				j++
+			}
+			tokens = append(tokens, spannerDdlToken{canonical: strings.ToUpper(s[i:j]), start: i, end: j})
+			i = j
+		default:
+			tokens = append(tokens, spannerDdlToken{canonical: string(c), start: i, end: i + 1})
+			i++
+		}
+	}
+	return tokens, nil
+}
+
+func isSpannerDdlWordChar(c byte) bool {
+	return c == '_' || c == '.' || (c >= '0' && c <= '9') || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+// spannerDdlCanonical returns the canonical form of a sequence of tokens.
+func spannerDdlCanonical(tokens []spannerDdlToken) string {
+	parts := make([]string, len(tokens))
+	for i, t := range tokens {
+		parts[i] = t.canonical
+	}
+	return strings.Join(parts, " ")
+}
+
+// splitSpannerDdlTokens splits tokens at the commas outside parentheses,
+// dropping empty parts such as the one after a trailing comma.
+func splitSpannerDdlTokens(tokens []spannerDdlToken) [][]spannerDdlToken {
+	var parts [][]spannerDdlToken
+	depth, angles, start := 0, 0, 0
+	for i, t := range tokens {
+		switch t.canonical {
+		case "(", "[":
+			depth++
+		case ")", "]":
+			depth--
+		case "<":
+			// Only the brackets of ARRAY<...> and STRUCT<...> nest, unlike
+			// comparisons.
+			if i > 0 && (tokens[i-1].canonical == "ARRAY" || tokens[i-1].canonical == "STRUCT") {
+				angles++
+				depth++
+			}
+		case ">":
+			if angles > 0 {
+				angles--
+				depth--
+			}
+		case ",":
+			if depth == 0 {
+				if i > start {
+					parts = append(parts, tokens[start:i])
+				}
+				start = i + 1
+			}
+		}
+	}
+	if len(tokens) > start {
+		parts = append(parts, tokens[start:])
+	}
+	return parts
+}
+
+// spannerDdlElement is a part of a statement, such as a column definition.
+type spannerDdlElement struct {
+	name      string
+	canonical string
+	text      string
+}
+
+func newSpannerDdlElement(statement, name string, tokens []spannerDdlToken) spannerDdlElement {
+	return spannerDdlElement{
+		name:      name,
+		canonical: spannerDdlCanonical(tokens),
+		text:      statement[tokens[0].start:tokens[len(tokens)-1].end],
+	}
+}
+
+const (
+	spannerDdlTable        = "TABLE"
+	spannerDdlIndex        = "INDEX"
+	spannerDdlChangeStream = "CHANGE STREAM"
+	spannerDdlOther        = "OTHER"
+)
+
+// spannerDdlObject is a schema object created by a statement.
+type spannerDdlObject struct {
+	kind      string
+	name      string
+	canonical string
+	text      string
+
+	// set for tables
+	nameText          string
+	columns           []spannerDdlElement
+	constraints       []spannerDdlElement
+	primaryKey        spannerDdlElement
+	interleave        spannerDdlElement
+	rowDeletionPolicy *spannerDdlElement
+}
+
+// tableCanonical returns the canonical form of a table, independent of the
+// order of its clauses and of trailing commas.
+func (obj *spannerDdlObject) tableCanonical() string {
+	parts := []string{"TABLE", obj.name}
+	for _, c := range obj.columns {
+		parts = append(parts, "COLUMN", c.canonical)
+	}
+	for _, c := range obj.constraints {
+		parts = append(parts, "CONSTRAINT", c.canonical)
+	}
+	parts = append(parts, obj.primaryKey.canonical, obj.interleave.canonical)
+	if obj.rowDeletionPolicy != nil {
+		parts = append(parts, obj.rowDeletionPolicy.canonical)
+	}
+	return strings.Join(parts, " ")
+}
+
+// tableText returns a CREATE TABLE statement for a table changed by ALTER
+// statements.
+func (obj *spannerDdlObject) tableText() string {
+	elements := make([]string, 0, len(obj.columns)+len(obj.constraints))
+	for _, c := range obj.columns {
+		elements = append(elements, c.text)
+	}
+	for _, c := range obj.constraints {
+		elements = append(elements, c.text)
+	}
+	clauses := []string{obj.primaryKey.text}
+	if obj.interleave.text != "" {
+		clauses = append(clauses, obj.interleave.text)
+	}
+	if obj.rowDeletionPolicy != nil {
+		clauses = append(clauses, obj.rowDeletionPolicy.text)
+	}
+	return fmt.Sprintf("CREATE TABLE %s (\n  %s,\n) %s", obj.nameText, strings.Join(elements, ",\n  "), strings.Join(clauses, ", "))
+}
+
+func parseSpannerDdlStatement(statement string) (*spannerDdlObject, error) {
+	statement = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(statement), ";"))
+	tokens, err := tokenizeSpannerDdl(statement)
+	if err != nil {
+		return nil, err
+	}
+	obj := &spannerDdlObject{
+		kind:      spannerDdlOther,
+		canonical: spannerDdlCanonical(tokens),
+		text:      statement,
+	}
+	obj.name = obj.canonical
+
+	words := make([]string, 0, 4)
+	for i := 0; i < len(tokens) && i < 4; i++ {
+		words = append(words, tokens[i].canonical)
+	}
+	prefix := strings.Join(words, " ")
+
+	switch {
+	case strings.HasPrefix(prefix, "CREATE TABLE "):
+		return parseSpannerDdlTable(obj, statement, tokens)
+	case strings.HasPrefix(prefix, "CREATE CHANGE STREAM "):
+		obj.kind = spannerDdlChangeStream
+		obj.name = tokens[3].canonical
+	case strings.HasPrefix(prefix, "CREATE INDEX ") || strings.HasPrefix(prefix, "CREATE UNIQUE ") || strings.HasPrefix(prefix, "CREATE NULL_FILTERED "):
+		for i, t := range tokens {
+			if t.canonical == "INDEX" && i+1 < len(tokens) {
+				obj.kind = spannerDdlIndex
+				obj.name = tokens[i+1].canonical
+				break
+			}
+		}
+	}
+	return obj, nil
+}
+
+func parseSpannerDdlTable(obj *spannerDdlObject, statement string, tokens []spannerDdlToken) (*spannerDdlObject, error) {
+	obj.kind = spannerDdlTable
+	if len(tokens) < 4 || tokens[3].canonical != "(" {
+		return nil, fmt.Errorf("unable to parse %q", statement)
+	}
+	obj.name = tokens[2].canonical
+	obj.nameText = statement[tokens[2].start:tokens[2].end]
+
+	// Find the end of the body holding the columns and constraints.
+	depth, end := 0, -1
+	for i := 3; i < len(tokens) && end < 0; i++ {
+		switch tokens[i].canonical {
+		case "(":
+			depth++
+		case ")":
+			depth--
+			if depth == 0 {
+				end = i
+			}
+		}
+	}
+	if end < 0 {
+		return nil, fmt.Errorf("unable to parse %q", statement)
+	}
+
+	for _, element := range splitSpannerDdlTokens(tokens[4:end]) {
+		switch element[0].canonical {
+		case "CONSTRAINT":
+			name := ""
+			if len(element) > 1 {
+				name = element[1].canonical
+			}
+			obj.constraints = append(obj.constraints, newSpannerDdlElement(statement, name, element))
+		case "FOREIGN", "CHECK":
+			obj.constraints = append(obj.constraints, newSpannerDdlElement(statement, "", element))
+		default:
+			obj.columns = append(obj.columns, newSpannerDdlElement(statement, element[0].canonical, element))
+		}
+	}
+
+	for _, clause := range splitSpannerDdlTokens(tokens[end+1:]) {
+		element := newSpannerDdlElement(statement, "", clause)
+		switch {
+		case strings.HasPrefix(element.canonical, "PRIMARY KEY"):
+			obj.primaryKey = element
+		case strings.HasPrefix(element.canonical, "INTERLEAVE IN"):
+			obj.interleave = element
+		case strings.HasPrefix(element.canonical, "ROW DELETION POLICY"):
+			obj.rowDeletionPolicy = &element
+		default:
+			return nil, fmt.Errorf("unable to parse the clause %q of %q", element.text, statement)
+		}
+	}
+	obj.canonical = obj.tableCanonical()
+	return obj, nil
+}
+
+type spannerDdlSchema struct {
+	objects []*spannerDdlObject
+	byKey   map[string]*spannerDdlObject
+}
+
+func parseSpannerDdlSchema(statements []string) (*spannerDdlSchema, error) {
+	s := &spannerDdlSchema{byKey: make(map[string]*spannerDdlObject)}
+	for _, statement := range statements {
+		if strings.TrimSpace(statement) == "" {
+			continue
+		}
+		obj, err := parseSpannerDdlStatement(statement)
+		if err != nil {
+			return nil, err
+		}
+		if obj.kind == spannerDdlOther {
+			folded, err := s.fold(obj.text)
+			if err != nil {
+				return nil, err
+			}
+			if folded {
+				continue
+			}
+		}
+		key := obj.kind + " " + obj.name
+		if _, ok := s.byKey[key]; ok {
+			return nil, fmt.Errorf("%s %s is defined more than once", strings.ToLower(obj.kind), obj.name)
+		}
+		s.objects = append(s.objects, obj)
+		s.byKey[key] = obj
+	}
+	return s, nil
+}
+
+func (s *spannerDdlSchema) get(obj *spannerDdlObject) *spannerDdlObject {
+	return s.byKey[obj.kind+" "+obj.name]
+}
+
+// fold applies an ALTER TABLE or DROP statement to the objects defined by the
+// previous statements, returning false for statements it doesn't handle.
+func (s *spannerDdlSchema) fold(statement string) (bool, error) {
+	tokens, err := tokenizeSpannerDdl(statement)
+	if err != nil {
+		return false, err
+	}
+	words := make([]string, len(tokens))
+	for i, t := range tokens {
+		words[i] = t.canonical
+	}
+
+	if len(words) >= 3 && words[0] == "DROP" {
+		kind, name := "", ""
+		switch {
+		case len(words) == 3 && words[1] == "TABLE":
+			kind, name = spannerDdlTable, words[2]
+		case len(words) == 3 && words[1] == "INDEX":
+			kind, name = spannerDdlIndex, words[2]
+		case len(words) == 4 && words[1] == "CHANGE" && words[2] == "STREAM":
+			kind, name = spannerDdlChangeStream, words[3]
+		default:
+			return false, nil
+		}
+		obj, ok := s.byKey[kind+" "+name]
+		if !ok {
+			return false, fmt.Errorf("%q drops %s %s, which isn't defined by the previous statements", statement, strings.ToLower(kind), name)
+		}
+		delete(s.byKey, kind+" "+name)
+		for i, o := range s.objects {
+			if o == obj {
+				s.objects = append(s.objects[:i], s.objects[i+1:]...)
+				break
+			}
+		}
+		return true, nil
+	}
+
+	if len(words) < 5 || words[0] != "ALTER" || words[1] != "TABLE" {
+		return false, nil
+	}
+	table, ok := s.byKey[spannerDdlTable+" "+words[2]]
+	if !ok {
+		return false, fmt.Errorf("%q alters table %s, which isn't defined by the previous statements", statement, words[2])
+	}
+	action := strings.Join(words[3:5], " ")
+	element := newSpannerDdlElement(statement, "", tokens[4:])
+	switch {
+	case action == "ADD COLUMN" && len(words) > 5:
+		column := newSpannerDdlElement(statement, words[5], tokens[5:])
+		table.columns = append(table.columns, column)
+	case action == "ALTER COLUMN" && len(words) > 6 && words[6] != "SET" && words[6] != "DROP":
+		column := newSpannerDdlElement(statement, words[5], tokens[5:])
+		i := spannerDdlElementIndex(table.columns, column.name)
+		if i < 0 {
+			return false, fmt.Errorf("%q alters column %s, which isn't defined by the previous statements", statement, column.name)
+		}
+		table.columns[i] = column
+	case action == "DROP COLUMN" && len(words) == 6:
+		i := spannerDdlElementIndex(table.columns, words[5])
+		if i < 0 {
+			return false, fmt.Errorf("%q drops column %s, which isn't defined by the previous statements", statement, words[5])
+		}
+		table.columns = append(table.columns[:i], table.columns[i+1:]...)
+	case action == "ADD CONSTRAINT" && len(words) > 5:
+		element.name = words[5]
+		table.constraints = append(table.constraints, element)
+	case action == "ADD FOREIGN" || action == "ADD CHECK":
+		table.constraints = append(table.constraints, element)
+	case action == "DROP CONSTRAINT" && len(words) == 6:
+		i := spannerDdlElementIndex(table.constraints, words[5])
+		if i < 0 {
+			return false, fmt.Errorf("%q drops constraint %s, which isn't defined by the previous statements", statement, words[5])
+		}
+		table.constraints = append(table.constraints[:i], table.constraints[i+1:]...)
+	case (action == "ADD ROW" || action == "REPLACE ROW") && strings.HasPrefix(element.canonical, "ROW DELETION POLICY"):
+		table.rowDeletionPolicy = &element
+	case action == "DROP ROW" && element.canonical == "ROW DELETION POLICY":
+		table.rowDeletionPolicy = nil
+	default:
+		return false, nil
+	}
+	table.canonical = table.tableCanonical()
+	table.text = table.tableText()
+	return true, nil
+}
+
+func spannerDdlElementIndex(elements []spannerDdlElement, name string) int {
+	for i, e := range elements {
+		if e.name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+func spannerDdlElementsByName(elements []spannerDdlElement) map[string]spannerDdlElement {
+	m := make(map[string]spannerDdlElement)
+	for _, e := range elements {
+		m[e.name] = e
+	}
+	return m
+}
+
+// spannerDdlMigration is the list of statements migrating a database from
+// one schema to another.
+type spannerDdlMigration struct {
+	statements []string
+	// destructive lists the statements deleting data.
+	destructive []string
+}
+
+// spannerDatabaseDdlMigration computes the statements migrating a database
+// from the current to the desired schema, returning an error for changes that
+// need the database to be recreated or can't be expressed with DDL.
+func spannerDatabaseDdlMigration(current, desired []string) (*spannerDdlMigration, error) {
+	from, err := parseSpannerDdlSchema(current)
+	if err != nil {
+		return nil, err
+	}
+	to, err := parseSpannerDdlSchema(desired)
+	if err != nil {
+		return nil, err
+	}
+
+	var dropStreams, dropIndexes, dropConstraints, dropTables, dropColumns []string
+	var createTables, addColumns, alterColumns, policies, addConstraints, createIndexes, createStreams, others []string
+	var destructive []string
+
+	for _, obj := range from.objects {
+		target := to.get(obj)
+		changed := target == nil || target.canonical != obj.canonical
+		switch obj.kind {
+		case spannerDdlChangeStream:
+			if changed {
+				statement := fmt.Sprintf("DROP CHANGE STREAM %s", obj.name)
+				dropStreams = append(dropStreams, statement)
+				destructive = append(destructive, statement)
+			}
+		case spannerDdlIndex:
+			if changed {
+				dropIndexes = append(dropIndexes, fmt.Sprintf("DROP INDEX %s", obj.name))
+			}
+		case spannerDdlOther:
+			if target == nil {
+				return nil, fmt.Errorf("the statement %q can't be removed from the schema", obj.text)
+			}
+		}
+	}
+
+	// Interleaved tables follow their parents, so drop them in reverse order.
+	for i := len(from.objects) - 1; i >= 0; i-- {
+		if obj := from.objects[i]; obj.kind == spannerDdlTable && to.get(obj) == nil {
+			statement := fmt.Sprintf("DROP TABLE %s", obj.name)
+			dropTables = append(dropTables, statement)
+			destructive = append(destructive, statement)
+		}
+	}
+
+	for _, obj := range to.objects {
+		current := from.get(obj)
+		switch obj.kind {
+		case spannerDdlTable:
+			if current == nil {
+				createTables = append(createTables, obj.text)
+				continue
+			}
+			if current.canonical == obj.canonical {
+				continue
+			}
+			if current.primaryKey.canonical != obj.primaryKey.canonical || current.interleave.canonical != obj.interleave.canonical {
+				return nil, fmt.Errorf("the primary key or parent of table %s can't be changed without recreating it", obj.name)
+			}
+
+			currentColumns := spannerDdlElementsByName(current.columns)
+			desiredColumns := spannerDdlElementsByName(obj.columns)
+			for _, c := range current.columns {
+				if _, ok := desiredColumns[c.name]; !ok {
+					statement := fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s", obj.name, c.name)
+					dropColumns = append(dropColumns, statement)
+					destructive = append(destructive, statement)
+				}
+			}
+			for _, c := range obj.columns {
+				old, ok := currentColumns[c.name]
+				if !ok {
+					addColumns = append(addColumns, fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s", obj.name, c.text))
+				} else if old.canonical != c.canonical {
+					alterColumns = append(alterColumns, fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s", obj.name, c.text))
+				}
+			}
+
+			currentConstraints := make(map[string]bool)
+			for _, c := range current.constraints {
+				currentConstraints[c.canonical] = true
+			}
+			desiredConstraints := make(map[string]bool)
+			for _, c := range obj.constraints {
+				desiredConstraints[c.canonical] = true
+				if !currentConstraints[c.canonical] {
+					addConstraints = append(addConstraints, fmt.Sprintf("ALTER TABLE %s ADD %s", obj.name, c.text))
+				}
+			}
+			for _, c := range current.constraints {
+				if desiredConstraints[c.canonical] {
+					continue
+				}
+				if c.name == "" {
+					return nil, fmt.Errorf("the unnamed constraint %q of table %s can't be removed", c.text, obj.name)
+				}
+				dropConstraints = append(dropConstraints, fmt.Sprintf("ALTER TABLE %s DROP CONSTRAINT %s", obj.name, c.name))
+			}
+
+			switch {
+			case current.rowDeletionPolicy == nil && obj.rowDeletionPolicy != nil:
+				policies = append(policies, fmt.Sprintf("ALTER TABLE %s ADD %s", obj.name, obj.rowDeletionPolicy.text))
+			case current.rowDeletionPolicy != nil && obj.rowDeletionPolicy == nil:
+				policies = append(policies, fmt.Sprintf("ALTER TABLE %s DROP ROW DELETION POLICY", obj.name))
+			case current.rowDeletionPolicy != nil && current.rowDeletionPolicy.canonical != obj.rowDeletionPolicy.canonical:
+				policies = append(policies, fmt.Sprintf("ALTER TABLE %s REPLACE %s", obj.name, obj.rowDeletionPolicy.text))
+			}
+		case spannerDdlIndex:
+			if current == nil || current.canonical != obj.canonical {
+				createIndexes = append(createIndexes, obj.text)
+			}
+		case spannerDdlChangeStream:
+			if current == nil || current.canonical != obj.canonical {
+				createStreams = append(createStreams, obj.text)
+			}
+		default:
+			if current == nil {
+				others = append(others, obj.text)
+			}
+		}
+	}
+
+	// Objects depending on others are dropped first and created last.
+	var statements []string
+	for _, group := range [][]string{dropStreams, dropIndexes, dropConstraints, dropTables, dropColumns, createTables, addColumns, alterColumns, policies, addConstraints, createIndexes, createStreams, others} {
+		statements = append(statements, group...)
+	}
+	return &spannerDdlMigration{statements: statements, destructive: destructive}, nil
+}
+
+// spannerDatabaseDdlEquivalent returns whether two lists of statements
+// define the same schema.
+func spannerDatabaseDdlEquivalent(a, b []string) bool {
+	migration, err := spannerDatabaseDdlMigration(a, b)
+	return err == nil && len(migration.statements) == 0
+}
+
+// spannerDatabaseDeclarativeDdlPlan returns the statements migrating a
+// database from the current to the desired schema, refusing to delete data
+// while deletion protection is enabled.
+func spannerDatabaseDeclarativeDdlPlan(current, desired []string, deletionProtection bool) ([]string, error) {
+	migration, err := spannerDatabaseDdlMigration(current, desired)
+	if err != nil {
+		return nil, fmt.Errorf("Error planning the DDL migration: %s", err)
+	}
+	if deletionProtection && len(migration.destructive) > 0 {
+		return nil, fmt.Errorf("cannot run %q without setting deletion_protection=false and running `terraform apply`", migration.destructive)
+	}
+	if migration.statements == nil {
+		return []string{}, nil
+	}
+	return migration.statements, nil
+}
+
+// resourceSpannerDatabaseDeclarativeDdlCustomizeDiff plans ddl_migration in
+// declarative mode.
+func resourceSpannerDatabaseDeclarativeDdlCustomizeDiff(_ context.Context, diff *schema.ResourceDiff, meta interface{}) error {
+	if diff.Id() == "" || !diff.Get("declarative_ddl").(bool) {
+		return nil
+	}
+	if diff.HasChange("declarative_ddl") {
+		// The ddl in state is the list of statements appended so far, and
+		// may not match the database. The migration is computed from the
+		// DDL of the database during the apply.
+		return diff.SetNewComputed("ddl_migration")
+	}
+	if !diff.HasChange("ddl") {
+		return nil
+	}
+	old, new := diff.GetChange("ddl")
+	statements, err := spannerDatabaseDeclarativeDdlPlan(convertStringArr(old.([]interface{})), convertStringArr(new.([]interface{})), diff.Get("deletion_protection").(bool))
+	if err != nil {
+		return err
+	}
+	return diff.SetNew("ddl_migration", statements)
+}
+
+// spannerDatabaseDeclarativeDdlStatements returns the statements migrating
+// the database to ddl, and records them in ddl_migration.
+func spannerDatabaseDeclarativeDdlStatements(d *schema.ResourceData, config *Config) ([]string, error) {
+	old, new := d.GetChange("ddl")
+	current := convertStringArr(old.([]interface{}))
+	if d.HasChange("declarative_ddl") {
+		userAgent, err := generateUserAgentString(d, config.userAgent)
+		if err != nil {
+			return nil, err
+		}
+		billingProject, err := getProject(d, config)
+		if err != nil {
+			return nil, fmt.Errorf("Error fetching project for Database: %s", err)
+		}
+		// err == nil indicates that the billing_project value was found
+		if bp, err := getBillingProject(d, config); err == nil {
+			billingProject = bp
+		}
+		current, err = spannerDatabaseGetDdl(d, config, billingProject, userAgent)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	statements, err := spannerDatabaseDeclarativeDdlPlan(current, convertStringArr(new.([]interface{})), d.Get("deletion_protection").(bool))
+	if err != nil {
+		return nil, err
+	}
+	if err := d.Set("ddl_migration", statements); err != nil {
+		return nil, fmt.Errorf("Error setting ddl_migration: %s", err)
+	}
+	return statements, nil
+}
+
+// spannerDatabaseGetDdl returns the DDL statements defining the schema of
+// the database.
+func spannerDatabaseGetDdl(d *schema.ResourceData, config *Config, billingProject, userAgent string) ([]string, error) {
+	url, err := replaceVars(d, config, "{{SpannerBasePath}}projects/{{project}}/instances/{{instance}}/databases/{{name}}/ddl")
+	if err != nil {
+		return nil, err
+	}
+	res, err := sendRequest(config, "GET", billingProject, url, userAgent, nil)
+	if err != nil {
+		return nil, fmt.Errorf("Error reading the DDL of Database %q: %s", d.Id(), err)
+	}
+	current := []string{}
+	if statements, ok := res["statements"].([]interface{}); ok {
+		current = convertStringArr(statements)
+	}
+	return current, nil
+}
+
+// spannerDatabaseReadDeclarativeDdl refreshes ddl from the schema of the
+// database, keeping the statements in state while they define the same schema.
+func spannerDatabaseReadDeclarativeDdl(d *schema.ResourceData, config *Config, billingProject, userAgent string) error {
+	current, err := spannerDatabaseGetDdl(d, config, billingProject, userAgent)
+	if err != nil {
+		return err
+	}
+
+	if spannerDatabaseDdlEquivalent(convertStringArr(d.Get("ddl").([]interface{})), current) {
+		return nil
+	}
+	log.Printf("[DEBUG] The schema of Database %q differs from its state, reading its DDL", d.Id())
+	if err := d.Set("ddl", current); err != nil {
+		return fmt.Errorf("Error reading Database: %s", err)
+	}
+	return nil
+}